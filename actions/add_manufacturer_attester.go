@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*AddManufacturerAttester)(nil)
+
+type AddManufacturerAttester struct {
+	// [Manufacturer] is the name of the manufacturer to modify. Only its
+	// owner can add attesters.
+	Manufacturer []byte `json:"manufacturer"`
+
+	// [Attester] is the account allowed to attest machines as made by
+	// [Manufacturer].
+	Attester codec.Address `json:"attester"`
+}
+
+func (*AddManufacturerAttester) GetTypeID() uint8 {
+	return addManufacturerAttesterID
+}
+
+func (a *AddManufacturerAttester) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ManufacturerKey(a.Manufacturer)),
+	}
+}
+
+func (*AddManufacturerAttester) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ManufacturerChunks}
+}
+
+func (*AddManufacturerAttester) OutputsWarpMessage() bool {
+	return false
+}
+
+func (a *AddManufacturerAttester) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	manufacturer, output := getOwnedManufacturer(ctx, mu, a.Manufacturer, auth.Actor())
+	if output != nil {
+		return false, ManageManufacturerComputeUnits, output, nil, nil
+	}
+	if manufacturer.IsAttester(a.Attester) {
+		return false, ManageManufacturerComputeUnits, OutputAttesterExists, nil, nil
+	}
+	if len(manufacturer.Attesters) >= storage.MaxManufacturerAttesters {
+		return false, ManageManufacturerComputeUnits, OutputTooManyAttesters, nil, nil
+	}
+	manufacturer.Attesters = append(manufacturer.Attesters, a.Attester)
+	if err := storage.SetManufacturer(ctx, mu, manufacturer); err != nil {
+		return false, ManageManufacturerComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageManufacturerComputeUnits, nil, nil, nil
+}
+
+func (*AddManufacturerAttester) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageManufacturerComputeUnits
+}
+
+func (a *AddManufacturerAttester) Size() int {
+	return codec.BytesLen(a.Manufacturer) + codec.AddressLen
+}
+
+func (a *AddManufacturerAttester) Marshal(p *codec.Packer) {
+	p.PackBytes(a.Manufacturer)
+	p.PackAddress(a.Attester)
+}
+
+func UnmarshalAddManufacturerAttester(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var add AddManufacturerAttester
+	p.UnpackBytes(MachineManufacturerUnits, true, &add.Manufacturer)
+	p.UnpackAddress(&add.Attester)
+	return &add, p.Err()
+}
+
+func (*AddManufacturerAttester) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*AddProjectMaintainer)(nil)
+
+type AddProjectMaintainer struct {
+	// [Project] is the txID of the [CreateProject] to modify. Only the
+	// project owner can add maintainers.
+	Project    ids.ID        `json:"project"`
+	Maintainer codec.Address `json:"maintainer"`
+}
+
+func (*AddProjectMaintainer) GetTypeID() uint8 {
+	return addProjectMaintainerID
+}
+
+func (a *AddProjectMaintainer) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ProjectKey(a.Project)),
+		string(storage.ProjectMaintainersKey(a.Project)),
+	}
+}
+
+func (*AddProjectMaintainer) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProjectDescriptionChunks, storage.ProjectMaintainersChunks}
+}
+
+func (*AddProjectMaintainer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (a *AddProjectMaintainer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, _, owner, err := getProjectOwner(ctx, mu, a.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ManageProjectComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if owner != auth.Actor() {
+		return false, ManageProjectComputeUnits, OutputNotProjectOwner, nil, nil
+	}
+
+	maintainers, err := storage.GetProjectMaintainers(ctx, mu, a.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if maintainerIndex(maintainers, a.Maintainer) >= 0 {
+		return false, ManageProjectComputeUnits, OutputMaintainerExists, nil, nil
+	}
+	if len(maintainers) >= storage.MaxProjectMaintainers {
+		return false, ManageProjectComputeUnits, OutputTooManyMaintainers, nil, nil
+	}
+	maintainers = append(maintainers, a.Maintainer)
+	if err := storage.SetProjectMaintainers(ctx, mu, a.Project, maintainers); err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageProjectComputeUnits, nil, nil, nil
+}
+
+func (*AddProjectMaintainer) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageProjectComputeUnits
+}
+
+func (*AddProjectMaintainer) Size() int {
+	return consts.IDLen + codec.AddressLen
+}
+
+func (a *AddProjectMaintainer) Marshal(p *codec.Packer) {
+	p.PackID(a.Project)
+	p.PackAddress(a.Maintainer)
+}
+
+func UnmarshalAddProjectMaintainer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var add AddProjectMaintainer
+	p.UnpackID(true, &add.Project)
+	p.UnpackAddress(&add.Maintainer)
+	return &add, p.Err()
+}
+
+func (*AddProjectMaintainer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
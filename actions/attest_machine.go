@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/utils"
 )
@@ -19,24 +20,51 @@ import (
 var _ chain.Action = (*AttestMachine)(nil)
 
 type AttestMachine struct {
-	MachineAddress      []byte `json:"machine_address"`
-	MachineCategory     []byte `json:"machine_category"`
+	// [MachineAddress] is the account the machine signs with. Only this
+	// account can notarize data against the attestation. It must be the
+	// address of the key [MachineCID] was registered with (see
+	// [RegisterMachine]).
+	MachineAddress  codec.Address `json:"machine_address"`
+	MachineCategory []byte        `json:"machine_category"`
+
+	// [MachineManufacturer] is the name of a registered manufacturer, the
+	// actor must be one of its attesters.
 	MachineManufacturer []byte `json:"machine_manufacturer"`
 	MachineCID          []byte `json:"machine_cid"`
+
+	// [Metadata] describes the machine, it must match the metadata schema
+	// registered for [MachineCategory].
+	Metadata storage.Metadata `json:"metadata"`
+
+	// [PreviousAttestTx] is the txID of the latest attestation of
+	// [MachineAddress], empty if it was never attested. A revoked machine
+	// can't be attested again and one that is attested can only be attested
+	// again by the same attester.
+	PreviousAttestTx ids.ID `json:"previous_attest_tx"`
 }
 
 func (*AttestMachine) GetTypeID() uint8 {
 	return attestMachineID
 }
 
-func (*AttestMachine) StateKeys(_ chain.Auth, txID ids.ID) []string {
+func (c *AttestMachine) StateKeys(_ chain.Auth, txID ids.ID) []string {
 	return []string{
 		string(storage.AttestMachineKey(txID)),
+		string(storage.ManufacturerKey(c.MachineManufacturer)),
+		string(storage.MetadataSchemaKey(c.MachineCategory)),
+		string(storage.MachineCIDIndexKey(c.MachineCID)),
+		string(storage.MachineAddressIndexKey(c.MachineAddress)),
+		string(storage.MachineAttestationKey(c.MachineAddress)),
+		string(storage.AttestMachineKey(c.PreviousAttestTx)),
 	}
 }
 
 func (*AttestMachine) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.MachineCategoryChunks}
+	return []uint16{
+		storage.MachineCIDChunks, storage.ManufacturerChunks, storage.MetadataSchemaChunks,
+		storage.MachineIndexChunks, storage.MachineIndexChunks, storage.MachineIndexChunks,
+		storage.MachineCIDChunks,
+	}
 }
 
 func (*AttestMachine) OutputsWarpMessage() bool {
@@ -53,10 +81,6 @@ func (c *AttestMachine) Execute(
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 
-	if len(c.MachineAddress) != 44 {
-		return false, AttestMachineComputeUnits, OutputInvalidMachineAddressLen, nil, nil
-	}
-
 	if len(c.MachineCategory) > 100 {
 		return false, AttestMachineComputeUnits, OutputInvalidMachineCategoryLen, nil, nil
 	}
@@ -69,9 +93,76 @@ func (c *AttestMachine) Execute(
 		return false, AttestMachineComputeUnits, OutputInvalidMachineCIDLen, nil, nil
 	}
 
+	// The CID and the address must have been registered together, so the
+	// attested address is the one that proved possession of the machine key
+	registered, registration, err := storage.GetMachineIndex(ctx, mu, storage.MachineCIDIndexKey(c.MachineCID))
+	if err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !registered {
+		return false, AttestMachineComputeUnits, OutputMachineNotRegistered, nil, nil
+	}
+	registered, addressRegistration, err := storage.GetMachineIndex(ctx, mu, storage.MachineAddressIndexKey(c.MachineAddress))
+	if err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !registered || addressRegistration != registration {
+		return false, AttestMachineComputeUnits, OutputMachineAddressMismatch, nil, nil
+	}
+
+	attested, latest, err := storage.GetMachineIndex(ctx, mu, storage.MachineAttestationKey(c.MachineAddress))
+	if err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if latest != c.PreviousAttestTx {
+		return false, AttestMachineComputeUnits, OutputPreviousAttestationMismatch, nil, nil
+	}
+	if attested {
+		exists, previous, err := storage.GetAttestMachine(ctx, mu, latest)
+		if err != nil {
+			return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		switch {
+		case !exists:
+			return false, AttestMachineComputeUnits, OutputMachineNotAttested, nil, nil
+		case previous.Status == storage.MachineRevoked:
+			return false, AttestMachineComputeUnits, OutputMachineRevoked, nil, nil
+		case previous.Attester != auth.Actor():
+			return false, AttestMachineComputeUnits, OutputMachineAttestedByOther, nil, nil
+		case previous.Status != storage.MachineActive:
+			return false, AttestMachineComputeUnits, OutputMachineNotActive, nil, nil
+		}
+	}
+
+	exists, manufacturer, err := storage.GetManufacturer(ctx, mu, c.MachineManufacturer)
+	if err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, AttestMachineComputeUnits, OutputManufacturerNotFound, nil, nil
+	}
+	if !manufacturer.IsAttester(auth.Actor()) {
+		return false, AttestMachineComputeUnits, OutputNotManufacturerAttester, nil, nil
+	}
+
+	exists, schema, err := storage.GetMetadataSchema(ctx, mu, c.MachineCategory)
+	if err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, AttestMachineComputeUnits, OutputMetadataSchemaNotFound, nil, nil
+	}
+	if err := schema.Validate(c.Metadata); err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
 	// It should only be possible to overwrite an existing asset if there is
 	// a hash collision.
-	if err := storage.AttestMachine(ctx, mu, txID, c.MachineAddress, c.MachineCategory, c.MachineManufacturer, c.MachineCID); err != nil {
+	if err := storage.AttestMachine(ctx, mu, txID, c.MachineAddress, c.MachineCategory, c.MachineManufacturer, c.MachineCID, auth.Actor(), c.Metadata); err != nil {
+		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	// A machine attested again is found by its latest attestation
+	if err := storage.SetMachineIndex(ctx, mu, storage.MachineAttestationKey(c.MachineAddress), txID); err != nil {
 		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, AttestMachineComputeUnits, nil, nil, nil
@@ -83,29 +174,38 @@ func (*AttestMachine) MaxComputeUnits(chain.Rules) uint64 {
 
 func (c *AttestMachine) Size() int {
 	// TODO: add small bytes (smaller int prefix)
-	return (codec.BytesLen(c.MachineAddress) +
+	return (codec.AddressLen +
 		codec.BytesLen(c.MachineCategory) +
 		codec.BytesLen(c.MachineManufacturer) +
-		codec.BytesLen(c.MachineCID))
+		codec.BytesLen(c.MachineCID) +
+		storage.MetadataSize(c.Metadata) +
+		consts.IDLen)
 
 }
 
 func (c *AttestMachine) Marshal(p *codec.Packer) {
-	p.PackBytes(c.MachineAddress)
+	p.PackAddress(c.MachineAddress)
 	p.PackBytes(c.MachineCategory)
 	p.PackBytes(c.MachineManufacturer)
 	p.PackBytes(c.MachineCID)
-
+	storage.PackMetadata(p, c.Metadata)
+	p.PackID(c.PreviousAttestTx)
 }
 
 func UnmarshalAttestMachineCID(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
 
 	var create AttestMachine
 
-	p.UnpackBytes(MachineAddressUnits, true, &create.MachineAddress)
+	p.UnpackAddress(&create.MachineAddress)
 	p.UnpackBytes(MachineCategoryUnits, true, &create.MachineCategory)
 	p.UnpackBytes(MachineManufacturerUnits, true, &create.MachineManufacturer)
 	p.UnpackBytes(MachineCIDUnits, true, &create.MachineCID)
+	metadata, err := storage.UnpackMetadata(p)
+	if err != nil {
+		return nil, err
+	}
+	create.Metadata = metadata
+	p.UnpackID(false, &create.PreviousAttestTx)
 
 	return &create, p.Err()
 
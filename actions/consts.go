@@ -19,6 +19,36 @@ const (
 	registerMachineCIDID uint8 = 11
 	attestMachineID      uint8 = 12
 	notarizeDataID       uint8 = 13
+	reportUpdateResultID uint8 = 14
+
+	addProjectMaintainerID     uint8 = 15
+	removeProjectMaintainerID  uint8 = 16
+	transferProjectOwnershipID uint8 = 17
+	updateProjectMetadataID    uint8 = 18
+	setProjectReleaseKeyID     uint8 = 19
+
+	revokeMachineID    uint8 = 20
+	suspendMachineID   uint8 = 21
+	reinstateMachineID uint8 = 22
+
+	listDataID     uint8 = 23
+	purchaseDataID uint8 = 24
+	delistDataID   uint8 = 25
+
+	offerLicenseID         uint8 = 26
+	purchaseLicenseID      uint8 = 27
+	withdrawLicenseOfferID uint8 = 28
+
+	deliverDataKeyID uint8 = 29
+	notarizeBatchID  uint8 = 30
+	onboardMachineID uint8 = 31
+	setSponsorshipID uint8 = 32
+
+	createManufacturerID         uint8 = 33
+	addManufacturerAttesterID    uint8 = 34
+	revokeManufacturerAttesterID uint8 = 35
+
+	setMetadataSchemaID uint8 = 36
 )
 
 const (
@@ -47,35 +77,45 @@ const (
 	ProjectDescriptionUnits   = 100
 	ProjectOwnerUnits         = 500
 	CreateProjectComputeUnits = 5
+	ManageProjectComputeUnits = 5
 )
 
 // Update storage constants
 const (
-	ProjectTxIDUnits          = 100
 	UpdateExecutableHashUnits = 100
 	UpdateExecutableIPFSUrl   = 100
 	ForDeviceNameUnits        = 100
-	UpdateVersionUnits        = 1
-	SuccessCountUnits         = 1
-	CreateUpdateComputeUnits  = 5
+	CreateUpdateComputeUnits  = 10
+
+	ReportUpdateResultComputeUnits = 5
 )
 
 // Machine cid storage constants
 const (
-	MachineAddressUnits      = 44
 	MachineCategoryUnits     = 100
 	MachineManufacturerUnits = 100
 	MachineCIDUnits          = 66
 
-	RegisterMachineComputeUnits = 5
+	RegisterMachineComputeUnits = 15 // includes verifying the machine key proof
 	AttestMachineComputeUnits   = 5
+	MachineStatusComputeUnits   = 5
+	SetSponsorshipComputeUnits  = 5
+
+	ManageManufacturerComputeUnits = 5
+	SetMetadataSchemaComputeUnits  = 5
 )
 
 // data storage constants
 const (
-	MachineAttestTxUnits     = 49
-	DataOwnerAddrUnits       = 44
 	DataCIDUnits             = 60
 	DataTypeUnits            = 36
 	NotarizeDataComputeUnits = 5
+
+	ListDataComputeUnits     = 5
+	PurchaseDataComputeUnits = 10
+
+	LicenseComputeUnits         = 5
+	PurchaseLicenseComputeUnits = 10
+
+	DeliverDataKeyComputeUnits = 5
 )
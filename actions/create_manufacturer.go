@@ -0,0 +1,95 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*CreateManufacturer)(nil)
+
+// CreateManufacturer registers [Name] as a manufacturer owned by the actor.
+// Machines can only be attested as made by a registered manufacturer, by one
+// of the attesters its owner adds.
+type CreateManufacturer struct {
+	Name []byte `json:"name"`
+}
+
+func (*CreateManufacturer) GetTypeID() uint8 {
+	return createManufacturerID
+}
+
+func (c *CreateManufacturer) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ManufacturerKey(c.Name)),
+	}
+}
+
+func (*CreateManufacturer) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ManufacturerChunks}
+}
+
+func (*CreateManufacturer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *CreateManufacturer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(c.Name) == 0 || len(c.Name) > MachineManufacturerUnits {
+		return false, ManageManufacturerComputeUnits, OutputInvalidMachineManufacturerLen, nil, nil
+	}
+	exists, _, err := storage.GetManufacturer(ctx, mu, c.Name)
+	if err != nil {
+		return false, ManageManufacturerComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists {
+		return false, ManageManufacturerComputeUnits, OutputManufacturerExists, nil, nil
+	}
+	if err := storage.SetManufacturer(ctx, mu, storage.ManufacturerData{
+		Name:  c.Name,
+		Owner: auth.Actor(),
+	}); err != nil {
+		return false, ManageManufacturerComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageManufacturerComputeUnits, nil, nil, nil
+}
+
+func (*CreateManufacturer) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageManufacturerComputeUnits
+}
+
+func (c *CreateManufacturer) Size() int {
+	return codec.BytesLen(c.Name)
+}
+
+func (c *CreateManufacturer) Marshal(p *codec.Packer) {
+	p.PackBytes(c.Name)
+}
+
+func UnmarshalCreateManufacturer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var create CreateManufacturer
+	p.UnpackBytes(MachineManufacturerUnits, true, &create.Name)
+	return &create, p.Err()
+}
+
+func (*CreateManufacturer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
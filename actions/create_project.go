@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/utils"
 )
@@ -23,6 +24,10 @@ type CreateProject struct {
 	ProjectName        []byte `json:"name"`
 	ProjectDescription []byte `json:"description"`
 	Logo               []byte `json:"url"`
+
+	// [Maintainers] may publish updates for the project on behalf of the
+	// owner.
+	Maintainers []codec.Address `json:"maintainers"`
 }
 
 func (*CreateProject) GetTypeID() uint8 {
@@ -32,11 +37,12 @@ func (*CreateProject) GetTypeID() uint8 {
 func (*CreateProject) StateKeys(_ chain.Auth, txID ids.ID) []string {
 	return []string{
 		string(storage.ProjectKey(txID)),
+		string(storage.ProjectMaintainersKey(txID)),
 	}
 }
 
 func (*CreateProject) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.ProjectDescriptionChunks}
+	return []uint16{storage.ProjectDescriptionChunks, storage.ProjectMaintainersChunks}
 }
 
 func (*CreateProject) OutputsWarpMessage() bool {
@@ -59,6 +65,10 @@ func (c *CreateProject) Execute(
 		return false, CreateAssetComputeUnits, OutputProjectDescriptionNotGiven, nil, nil
 	}
 
+	if len(c.Maintainers) > storage.MaxProjectMaintainers {
+		return false, CreateProjectComputeUnits, OutputTooManyMaintainers, nil, nil
+	}
+
 	owner, err := codec.AddressBech32(consts.HRP, auth.Actor())
 
 	if err != nil {
@@ -70,6 +80,11 @@ func (c *CreateProject) Execute(
 	if err := storage.SetProject(ctx, mu, txID, c.ProjectName, c.ProjectDescription, []byte(owner), c.Logo); err != nil {
 		return false, CreateProjectComputeUnits, utils.ErrBytes(err), nil, nil
 	}
+	if len(c.Maintainers) > 0 {
+		if err := storage.SetProjectMaintainers(ctx, mu, txID, c.Maintainers); err != nil {
+			return false, CreateProjectComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	}
 	return true, CreateProjectComputeUnits, nil, nil, nil
 }
 
@@ -81,7 +96,8 @@ func (c *CreateProject) Size() int {
 	// TODO: add small bytes (smaller int prefix)
 	return (codec.BytesLen(c.ProjectName) +
 		codec.BytesLen(c.ProjectDescription) +
-		codec.BytesLen(c.Logo))
+		codec.BytesLen(c.Logo) +
+		hconsts.IntLen + len(c.Maintainers)*codec.AddressLen)
 
 }
 
@@ -89,6 +105,10 @@ func (c *CreateProject) Marshal(p *codec.Packer) {
 	p.PackBytes(c.ProjectName)
 	p.PackBytes(c.ProjectDescription)
 	p.PackBytes(c.Logo)
+	p.PackInt(len(c.Maintainers))
+	for _, maintainer := range c.Maintainers {
+		p.PackAddress(maintainer)
+	}
 }
 
 func UnmarshalCreateProject(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
@@ -99,6 +119,15 @@ func UnmarshalCreateProject(p *codec.Packer, _ *warp.Message) (chain.Action, err
 	p.UnpackBytes(ProjectDescriptionUnits, true, &create.ProjectDescription)
 	p.UnpackBytes(ProjectLogoUnits, true, &create.Logo)
 
+	count := p.UnpackInt(false)
+	if count > storage.MaxProjectMaintainers {
+		return nil, ErrTooManyMaintainers
+	}
+	create.Maintainers = make([]codec.Address, count)
+	for i := range create.Maintainers {
+		p.UnpackAddress(&create.Maintainers[i])
+	}
+
 	return &create, p.Err()
 
 }
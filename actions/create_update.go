@@ -5,6 +5,7 @@ package actions
 
 import (
 	"context"
+	"crypto/sha256"
 
 	"dataverse/storage"
 
@@ -12,6 +13,8 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/utils"
 )
@@ -19,26 +22,64 @@ import (
 var _ chain.Action = (*CreateUpdate)(nil)
 
 type CreateUpdate struct {
-	ProjectTxID          []byte `json:"project_id"` // reference to Project
-	UpdateExecutableHash []byte `json:"executable_hash"`
+	ProjectTxID          ids.ID `json:"project_id"`      // reference to Project
+	UpdateExecutableHash []byte `json:"executable_hash"` // SHA-256 of the executable
 	UpdateIPFSUrl        []byte `json:"executable_ipfs_url"`
 	ForDeviceName        []byte `json:"for_device_name"`
-	UpdateVersion        uint8  `json:"version"`
-	SuccessCount         uint8  `json:"success_count"`
+
+	// [UpdateVersion] must be greater than the version of the latest update
+	// of the project for [ForDeviceName].
+	UpdateVersion storage.Version `json:"version"`
+
+	// [UpdateSize] is the size of the executable in bytes.
+	UpdateSize uint64 `json:"size"`
+
+	// [Signature] is the signature of [UpdateManifest] by the release key of
+	// the project.
+	Signature ed25519.Signature `json:"signature"`
+}
+
+// UpdateManifest returns the message signed by the release key of [project]
+// for an update. It binds the executable digest and size to the project,
+// device and version, so a signature can't be replayed for another release.
+func UpdateManifest(project ids.ID, device []byte, version storage.Version, digest []byte, executableSize uint64) []byte {
+	size := consts.IDLen + codec.BytesLen(device) + storage.VersionLen + codec.BytesLen(digest) + consts.Uint64Len
+	p := codec.NewWriter(size, size)
+	p.PackID(project)
+	p.PackBytes(device)
+	storage.PackVersion(p, version)
+	p.PackBytes(digest)
+	p.PackUint64(executableSize)
+	return p.Bytes()
+}
+
+// Manifest returns the [UpdateManifest] of [c].
+func (c *CreateUpdate) Manifest() []byte {
+	return UpdateManifest(c.ProjectTxID, c.ForDeviceName, c.UpdateVersion, c.UpdateExecutableHash, c.UpdateSize)
 }
 
 func (*CreateUpdate) GetTypeID() uint8 {
 	return createUpdateID
 }
 
-func (*CreateUpdate) StateKeys(_ chain.Auth, txID ids.ID) []string {
+func (c *CreateUpdate) StateKeys(_ chain.Auth, txID ids.ID) []string {
 	return []string{
 		string(storage.UpdateKey(txID)),
+		string(storage.ProjectKey(c.ProjectTxID)),
+		string(storage.ProjectMaintainersKey(c.ProjectTxID)),
+		string(storage.LatestUpdateKey(c.ProjectTxID, c.ForDeviceName)),
+		string(storage.ProjectReleaseKeyKey(c.ProjectTxID)),
 	}
 }
 
 func (*CreateUpdate) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.UpdateExecutableHashChunks}
+	return []uint16{
+		storage.UpdateExecutableHashChunks,
+		storage.ProjectDescriptionChunks,
+		storage.ProjectMaintainersChunks,
+		storage.LatestUpdateChunks,
+		storage.ProjectReleaseKeyChunks,
+	}
 }
 
 func (*CreateUpdate) OutputsWarpMessage() bool {
@@ -55,12 +96,18 @@ func (c *CreateUpdate) Execute(
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 
-	if len(c.ProjectTxID) == 0 {
+	if c.ProjectTxID == ids.Empty {
 		return false, CreateUpdateComputeUnits, OutputProjectTxIdNotProvided, nil, nil
 	}
 	if len(c.UpdateExecutableHash) == 0 {
 		return false, CreateUpdateComputeUnits, OutputUpdateExecutableHashNotProvided, nil, nil
 	}
+	if len(c.UpdateExecutableHash) != sha256.Size {
+		return false, CreateUpdateComputeUnits, OutputInvalidUpdateDigest, nil, nil
+	}
+	if c.UpdateSize == 0 {
+		return false, CreateUpdateComputeUnits, OutputUpdateSizeNotProvided, nil, nil
+	}
 
 	if len(c.ForDeviceName) == 0 {
 		return false, CreateAssetComputeUnits, OutputForDeviceNameNotProvided, nil, nil
@@ -70,13 +117,46 @@ func (c *CreateUpdate) Execute(
 		return false, CreateAssetComputeUnits, OutputUpdateExecutableIPFSNotProvided, nil, nil
 	}
 
-	if c.UpdateVersion == 0 {
+	if c.UpdateVersion.IsZero() {
 		return false, CreateAssetComputeUnits, OutputUpdateVersionNotProvided, nil, nil
 	}
 
+	exists, authorized, err := canPublishUpdates(ctx, mu, c.ProjectTxID, auth.Actor())
+	if err != nil {
+		return false, CreateUpdateComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, CreateUpdateComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if !authorized {
+		return false, CreateUpdateComputeUnits, OutputNotProjectMaintainer, nil, nil
+	}
+
+	exists, releaseKey, err := storage.GetProjectReleaseKey(ctx, mu, c.ProjectTxID)
+	if err != nil {
+		return false, CreateUpdateComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, CreateUpdateComputeUnits, OutputReleaseKeyNotSet, nil, nil
+	}
+	if !ed25519.Verify(c.Manifest(), releaseKey, c.Signature) {
+		return false, CreateUpdateComputeUnits, OutputInvalidUpdateSignature, nil, nil
+	}
+
+	exists, _, latest, err := storage.GetLatestUpdate(ctx, mu, c.ProjectTxID, c.ForDeviceName)
+	if err != nil {
+		return false, CreateUpdateComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists && c.UpdateVersion.Compare(latest) <= 0 {
+		return false, CreateUpdateComputeUnits, OutputUpdateVersionNotIncreasing, nil, nil
+	}
+
 	// It should only be possible to overwrite an existing asset if there is
 	// a hash collision.
-	if err := storage.SetUpdate(ctx, mu, txID, c.ProjectTxID, c.UpdateExecutableHash, c.UpdateIPFSUrl, c.ForDeviceName, byte(c.UpdateVersion), byte(c.SuccessCount)); err != nil {
+	if err := storage.SetUpdate(ctx, mu, txID, c.ProjectTxID, c.UpdateExecutableHash, c.UpdateIPFSUrl, c.ForDeviceName, c.UpdateVersion, c.UpdateSize, c.Signature[:]); err != nil {
+		return false, CreateUpdateComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetLatestUpdate(ctx, mu, c.ProjectTxID, c.ForDeviceName, txID, c.UpdateVersion); err != nil {
 		return false, CreateUpdateComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, CreateUpdateComputeUnits, nil, nil, nil
@@ -88,36 +168,39 @@ func (*CreateUpdate) MaxComputeUnits(chain.Rules) uint64 {
 
 func (c *CreateUpdate) Size() int {
 
-	return (codec.BytesLen(c.ProjectTxID) +
+	return (consts.IDLen +
 		codec.BytesLen(c.UpdateExecutableHash) +
 		codec.BytesLen(c.UpdateIPFSUrl) +
 		codec.BytesLen(c.ForDeviceName) +
-		UpdateVersionUnits +
-		SuccessCountUnits)
+		storage.VersionLen +
+		consts.Uint64Len +
+		ed25519.SignatureLen)
 
 }
 
 func (c *CreateUpdate) Marshal(p *codec.Packer) {
-	p.PackBytes(c.ProjectTxID)
+	p.PackID(c.ProjectTxID)
 	p.PackBytes(c.UpdateExecutableHash)
 	p.PackBytes(c.UpdateIPFSUrl)
 	p.PackBytes(c.ForDeviceName)
-	p.PackByte(c.UpdateVersion)
-	p.PackByte(c.SuccessCount)
-
+	storage.PackVersion(p, c.UpdateVersion)
+	p.PackUint64(c.UpdateSize)
+	p.PackFixedBytes(c.Signature[:])
 }
 
 func UnmarshalCreateUpdate(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
 
 	var create CreateUpdate
 
-	p.UnpackBytes(ProjectTxIDUnits, true, &create.ProjectTxID)
+	p.UnpackID(true, &create.ProjectTxID)
 	p.UnpackBytes(UpdateExecutableHashUnits, true, &create.UpdateExecutableHash)
 	p.UnpackBytes(UpdateExecutableIPFSUrl, true, &create.UpdateIPFSUrl)
 	p.UnpackBytes(ForDeviceNameUnits, true, &create.ForDeviceName)
 
-	create.UpdateVersion = uint8(p.UnpackByte())
-	create.SuccessCount = uint8(p.UnpackByte())
+	create.UpdateVersion = storage.UnpackVersion(p)
+	create.UpdateSize = p.UnpackUint64(true)
+	signature := create.Signature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.SignatureLen, &signature)
 
 	return &create, p.Err()
 
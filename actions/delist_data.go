@@ -0,0 +1,96 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*DelistData)(nil)
+
+type DelistData struct {
+	// [Listing] is the txID of the [ListData] to close. Receipts of past
+	// purchases are kept.
+	Listing ids.ID `json:"listing"`
+}
+
+func (*DelistData) GetTypeID() uint8 {
+	return delistDataID
+}
+
+func (d *DelistData) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ListingKey(d.Listing)),
+	}
+}
+
+func (*DelistData) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ListingChunks}
+}
+
+func (*DelistData) OutputsWarpMessage() bool {
+	return false
+}
+
+func (d *DelistData) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, listing, err := storage.GetListing(ctx, mu, d.Listing)
+	if err != nil {
+		return false, ListDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ListDataComputeUnits, OutputListingNotFound, nil, nil
+	}
+	if listing.Seller != auth.Actor() {
+		return false, ListDataComputeUnits, OutputWrongOwner, nil, nil
+	}
+	if listing.Closed {
+		return false, ListDataComputeUnits, OutputListingClosed, nil, nil
+	}
+	listing.Closed = true
+	if err := storage.SetListing(ctx, mu, d.Listing, listing); err != nil {
+		return false, ListDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ListDataComputeUnits, nil, nil, nil
+}
+
+func (*DelistData) MaxComputeUnits(chain.Rules) uint64 {
+	return ListDataComputeUnits
+}
+
+func (*DelistData) Size() int {
+	return consts.IDLen
+}
+
+func (d *DelistData) Marshal(p *codec.Packer) {
+	p.PackID(d.Listing)
+}
+
+func UnmarshalDelistData(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var delist DelistData
+	p.UnpackID(true, &delist.Listing)
+	return &delist, p.Err()
+}
+
+func (*DelistData) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
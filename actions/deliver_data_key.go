@@ -0,0 +1,121 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/content"
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*DeliverDataKey)(nil)
+
+// DeliverDataKey posts the content key of a listing, sealed to the key a buyer
+// paid with (see [content.SealKey]). Delivering again replaces the envelope.
+type DeliverDataKey struct {
+	// [Listing] is the txID of the [ListData] the buyer purchased.
+	Listing ids.ID `json:"listing"`
+
+	// [Buyer] is the address that holds a receipt for [Listing].
+	Buyer codec.Address `json:"buyer"`
+
+	// [Envelope] is the content key sealed to the buyer key in the receipt,
+	// with [Listing] as context.
+	Envelope []byte `json:"envelope"`
+}
+
+func (*DeliverDataKey) GetTypeID() uint8 {
+	return deliverDataKeyID
+}
+
+func (d *DeliverDataKey) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ListingKey(d.Listing)),
+		string(storage.PurchaseReceiptKey(d.Listing, d.Buyer)),
+		string(storage.KeyEnvelopeKey(d.Listing, d.Buyer)),
+	}
+}
+
+func (*DeliverDataKey) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ListingChunks, storage.PurchaseReceiptChunks, storage.KeyEnvelopeChunks}
+}
+
+func (*DeliverDataKey) OutputsWarpMessage() bool {
+	return false
+}
+
+func (d *DeliverDataKey) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if !content.ValidEnvelope(d.Envelope) {
+		return false, DeliverDataKeyComputeUnits, OutputInvalidKeyEnvelope, nil, nil
+	}
+	exists, listing, err := storage.GetListing(ctx, mu, d.Listing)
+	if err != nil {
+		return false, DeliverDataKeyComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, DeliverDataKeyComputeUnits, OutputListingNotFound, nil, nil
+	}
+	if listing.Seller != auth.Actor() {
+		return false, DeliverDataKeyComputeUnits, OutputWrongOwner, nil, nil
+	}
+	purchased, _, err := storage.GetPurchaseReceipt(ctx, mu, d.Listing, d.Buyer)
+	if err != nil {
+		return false, DeliverDataKeyComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !purchased {
+		return false, DeliverDataKeyComputeUnits, OutputNotPurchased, nil, nil
+	}
+	if err := storage.SetKeyEnvelope(ctx, mu, d.Listing, d.Buyer, storage.KeyEnvelopeData{
+		DeliverTx: txID,
+		Timestamp: timestamp,
+		Envelope:  d.Envelope,
+	}); err != nil {
+		return false, DeliverDataKeyComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, DeliverDataKeyComputeUnits, nil, nil, nil
+}
+
+func (*DeliverDataKey) MaxComputeUnits(chain.Rules) uint64 {
+	return DeliverDataKeyComputeUnits
+}
+
+func (d *DeliverDataKey) Size() int {
+	return consts.IDLen + codec.AddressLen + codec.BytesLen(d.Envelope)
+}
+
+func (d *DeliverDataKey) Marshal(p *codec.Packer) {
+	p.PackID(d.Listing)
+	p.PackAddress(d.Buyer)
+	p.PackBytes(d.Envelope)
+}
+
+func UnmarshalDeliverDataKey(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var deliver DeliverDataKey
+	p.UnpackID(true, &deliver.Listing)
+	p.UnpackAddress(&deliver.Buyer)
+	p.UnpackBytes(content.EnvelopeOverhead+content.MaxContentKeyLen, true, &deliver.Envelope)
+	return &deliver, p.Err()
+}
+
+func (*DeliverDataKey) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -5,4 +5,7 @@ package actions
 
 import "errors"
 
-var ErrNoSwapToFill = errors.New("no swap to fill")
+var (
+	ErrNoSwapToFill       = errors.New("no swap to fill")
+	ErrTooManyMaintainers = errors.New("too many maintainers")
+)
@@ -0,0 +1,119 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*ListData)(nil)
+
+type ListData struct {
+	// [Notarization] is the txID of the [NotarizeData] record to sell access
+	// to. Only the owner of the data can list it.
+	Notarization ids.ID `json:"notarization"`
+
+	// [Asset] is the asset buyers pay with.
+	Asset ids.ID `json:"asset"`
+
+	// [Price] is the amount of [Asset] a buyer pays for access.
+	Price uint64 `json:"price"`
+}
+
+func (*ListData) GetTypeID() uint8 {
+	return listDataID
+}
+
+func (l *ListData) StateKeys(_ chain.Auth, txID ids.ID) []string {
+	return []string{
+		string(storage.NotarizeDataKey(l.Notarization)),
+		string(storage.AssetKey(l.Asset)),
+		string(storage.ListingKey(txID)),
+	}
+}
+
+func (*ListData) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.DataCIDChunks, storage.AssetChunks, storage.ListingChunks}
+}
+
+func (*ListData) OutputsWarpMessage() bool {
+	return false
+}
+
+func (l *ListData) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if l.Price == 0 {
+		return false, ListDataComputeUnits, OutputValueZero, nil, nil
+	}
+	exists, notarized, err := storage.GetNotarizeData(ctx, mu, l.Notarization)
+	if err != nil {
+		return false, ListDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ListDataComputeUnits, OutputNotarizationNotFound, nil, nil
+	}
+	if notarized.DataOwnerAddr != auth.Actor() {
+		return false, ListDataComputeUnits, OutputNotDataOwner, nil, nil
+	}
+	exists, _, _, _, _, _, _, err = storage.GetAsset(ctx, mu, l.Asset)
+	if err != nil {
+		return false, ListDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ListDataComputeUnits, OutputAssetMissing, nil, nil
+	}
+	if err := storage.SetListing(ctx, mu, txID, storage.ListingData{
+		Notarization: l.Notarization,
+		Seller:       auth.Actor(),
+		Asset:        l.Asset,
+		Price:        l.Price,
+	}); err != nil {
+		return false, ListDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ListDataComputeUnits, nil, nil, nil
+}
+
+func (*ListData) MaxComputeUnits(chain.Rules) uint64 {
+	return ListDataComputeUnits
+}
+
+func (*ListData) Size() int {
+	return consts.IDLen*2 + consts.Uint64Len
+}
+
+func (l *ListData) Marshal(p *codec.Packer) {
+	p.PackID(l.Notarization)
+	p.PackID(l.Asset)
+	p.PackUint64(l.Price)
+}
+
+func UnmarshalListData(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var list ListData
+	p.UnpackID(true, &list.Notarization)
+	p.UnpackID(false, &list.Asset) // empty ID is the native asset
+	list.Price = p.UnpackUint64(true)
+	return &list, p.Err()
+}
+
+func (*ListData) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// getAttestedMachine returns the attestation record [tx] if [actor] is the
+// attester that created it. Otherwise, it returns the output explaining why
+// the record can't be managed by [actor].
+func getAttestedMachine(
+	ctx context.Context,
+	im state.Immutable,
+	tx ids.ID,
+	actor codec.Address,
+) (storage.AttestMachineData, []byte) {
+	exists, machine, err := storage.GetAttestMachine(ctx, im, tx)
+	if err != nil {
+		return storage.AttestMachineData{}, utils.ErrBytes(err)
+	}
+	if !exists {
+		return storage.AttestMachineData{}, OutputMachineNotAttested
+	}
+	if machine.Attester != actor {
+		return storage.AttestMachineData{}, OutputNotMachineAttester
+	}
+	return machine, nil
+}
+
+// setMachineStatus changes the status of the attestation record [tx] of
+// [actor] at [timestamp]. [transition] checks the record can move to its new
+// status and sets it, returning the output explaining why it can't otherwise.
+func setMachineStatus(
+	ctx context.Context,
+	mu state.Mutable,
+	tx ids.ID,
+	actor codec.Address,
+	timestamp int64,
+	transition func(*storage.AttestMachineData) []byte,
+) []byte {
+	machine, output := getAttestedMachine(ctx, mu, tx, actor)
+	if output != nil {
+		return output
+	}
+	if output := transition(&machine); output != nil {
+		return output
+	}
+	machine.StatusChanged = timestamp
+	if err := storage.SetAttestMachine(ctx, mu, tx, machine); err != nil {
+		return utils.ErrBytes(err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// getOwnedManufacturer returns the manufacturer [name] if [actor] is its
+// owner. Otherwise, it returns the output explaining why the manufacturer
+// can't be managed by [actor].
+func getOwnedManufacturer(
+	ctx context.Context,
+	im state.Immutable,
+	name []byte,
+	actor codec.Address,
+) (storage.ManufacturerData, []byte) {
+	exists, manufacturer, err := storage.GetManufacturer(ctx, im, name)
+	if err != nil {
+		return storage.ManufacturerData{}, utils.ErrBytes(err)
+	}
+	if !exists {
+		return storage.ManufacturerData{}, OutputManufacturerNotFound
+	}
+	if manufacturer.Owner != actor {
+		return storage.ManufacturerData{}, OutputNotManufacturerOwner
+	}
+	return manufacturer, nil
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/merkle"
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*NotarizeBatch)(nil)
+
+// NotarizeBatch notarizes many data CIDs at once by committing to the root of
+// their Merkle tree. Inclusion of a CID is proven later against the root, see
+// the merkle package.
+type NotarizeBatch struct {
+	// [MachineAttestTx] is the txID of the [AttestMachine] record of the
+	// machine that produced the data. The transaction must be signed by the
+	// attested machine address.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+
+	// [Root] is the root of the tree over the [Count] CIDs of the batch.
+	Root  []byte `json:"root"`
+	Count uint32 `json:"count"`
+
+	// [Start] and [End] are the times (in ms) the first and the last data of
+	// the batch were produced.
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+
+	DataType []byte `json:"data_type"`
+}
+
+func (*NotarizeBatch) GetTypeID() uint8 {
+	return notarizeBatchID
+}
+
+func (n *NotarizeBatch) StateKeys(_ chain.Auth, txID ids.ID) []string {
+	return []string{
+		string(storage.NotarizeBatchKey(txID)),
+		string(storage.AttestMachineKey(n.MachineAttestTx)),
+	}
+}
+
+func (*NotarizeBatch) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.NotarizeBatchChunks, storage.MachineCIDChunks}
+}
+
+func (*NotarizeBatch) OutputsWarpMessage() bool {
+	return false
+}
+
+func (n *NotarizeBatch) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(n.Root) != merkle.HashLen {
+		return false, NotarizeDataComputeUnits, OutputInvalidBatchRoot, nil, nil
+	}
+	if n.Count == 0 {
+		return false, NotarizeDataComputeUnits, OutputValueZero, nil, nil
+	}
+	// Data can't have been produced after the block including it.
+	if n.Start < 0 || n.Start > n.End || n.End > timestamp {
+		return false, NotarizeDataComputeUnits, OutputInvalidBatchTimeRange, nil, nil
+	}
+
+	exists, machine, err := storage.GetAttestMachine(ctx, mu, n.MachineAttestTx)
+	if err != nil {
+		return false, NotarizeDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, NotarizeDataComputeUnits, OutputMachineNotAttested, nil, nil
+	}
+	if machine.MachineAddress != auth.Actor() {
+		return false, NotarizeDataComputeUnits, OutputNotAttestedMachine, nil, nil
+	}
+	if machine.Status != storage.MachineActive {
+		return false, NotarizeDataComputeUnits, OutputMachineNotActive, nil, nil
+	}
+
+	// As with [NotarizeData], the data is owned by whoever attested the
+	// machine.
+	if err := storage.SetNotarizeBatch(ctx, mu, txID, storage.NotarizeBatchData{
+		AttestMachineTx: n.MachineAttestTx,
+		DataOwnerAddr:   machine.Attester,
+		Root:            n.Root,
+		Count:           n.Count,
+		Start:           n.Start,
+		End:             n.End,
+		DataType:        n.DataType,
+		Timestamp:       timestamp,
+	}); err != nil {
+		return false, NotarizeDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, NotarizeDataComputeUnits, nil, nil, nil
+}
+
+func (*NotarizeBatch) MaxComputeUnits(chain.Rules) uint64 {
+	return NotarizeDataComputeUnits
+}
+
+func (n *NotarizeBatch) Size() int {
+	return consts.IDLen + codec.BytesLen(n.Root) + consts.Uint32Len + consts.Int64Len*2 + codec.BytesLen(n.DataType)
+}
+
+func (n *NotarizeBatch) Marshal(p *codec.Packer) {
+	p.PackID(n.MachineAttestTx)
+	p.PackBytes(n.Root)
+	p.PackInt(int(n.Count))
+	p.PackInt64(n.Start)
+	p.PackInt64(n.End)
+	p.PackBytes(n.DataType)
+}
+
+func UnmarshalNotarizeBatch(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var batch NotarizeBatch
+	p.UnpackID(true, &batch.MachineAttestTx)
+	p.UnpackBytes(merkle.HashLen, true, &batch.Root)
+	batch.Count = uint32(p.UnpackInt(true))
+	batch.Start = p.UnpackInt64(false)
+	batch.End = p.UnpackInt64(true)
+	p.UnpackBytes(DataTypeUnits, true, &batch.DataType)
+	return &batch, p.Err()
+}
+
+func (*NotarizeBatch) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -6,12 +6,14 @@ package actions
 import (
 	"context"
 
+	"dataverse/content"
 	"dataverse/storage"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/utils"
 )
@@ -19,24 +21,32 @@ import (
 var _ chain.Action = (*NotarizeData)(nil)
 
 type NotarizeData struct {
-	MachineAttestTx []byte `json:"machine_attest_tx"`
-	DataCID         []byte `json:"data_cid"`
-	DataType        []byte `json:"data_type"`
-	DataOwnerAddr   []byte `json:"data_owner_addr"`
+	// [MachineAttestTx] is the txID of the [AttestMachine] record of the
+	// machine that produced the data. The transaction must be signed by the
+	// attested machine address.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+	// [DataCID] is a CID of the data, in any version and base. It is
+	// indexed by its multihash (see [storage.DataCIDKey]).
+	DataCID  []byte `json:"data_cid"`
+	DataType []byte `json:"data_type"`
 }
 
 func (*NotarizeData) GetTypeID() uint8 {
 	return notarizeDataID
 }
 
-func (*NotarizeData) StateKeys(_ chain.Auth, txID ids.ID) []string {
+func (c *NotarizeData) StateKeys(_ chain.Auth, txID ids.ID) []string {
+	// An invalid CID fails the notarization before the index is written
+	multihash, _ := content.Multihash(string(c.DataCID))
 	return []string{
 		string(storage.NotarizeDataKey(txID)),
+		string(storage.AttestMachineKey(c.MachineAttestTx)),
+		string(storage.DataCIDKey(multihash)),
 	}
 }
 
 func (*NotarizeData) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.DataCIDChunks}
+	return []uint16{storage.DataCIDChunks, storage.MachineCIDChunks, storage.DataCIDIndexChunks}
 }
 
 func (*NotarizeData) OutputsWarpMessage() bool {
@@ -47,22 +57,40 @@ func (c *NotarizeData) Execute(
 	ctx context.Context,
 	_ chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	auth chain.Auth,
 	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 
-	if len(c.DataOwnerAddr) == 0 {
-		return false, AttestMachineComputeUnits, OutputInvalidMachineAddressLen, nil, nil
+	multihash, err := content.Multihash(string(c.DataCID))
+	if err != nil {
+		return false, NotarizeDataComputeUnits, OutputInvalidDataCID, nil, nil
 	}
 
-	// It should only be possible to overwrite an existing asset if there is
-	// a hash collision.
-	if err := storage.NotarizeData(ctx, mu, txID, c.MachineAttestTx, c.DataOwnerAddr, c.DataCID, c.DataType); err != nil {
-		return false, AttestMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	exists, machine, err := storage.GetAttestMachine(ctx, mu, c.MachineAttestTx)
+	if err != nil {
+		return false, NotarizeDataComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	return true, AttestMachineComputeUnits, nil, nil, nil
+	if !exists {
+		return false, NotarizeDataComputeUnits, OutputMachineNotAttested, nil, nil
+	}
+	if machine.MachineAddress != auth.Actor() {
+		return false, NotarizeDataComputeUnits, OutputNotAttestedMachine, nil, nil
+	}
+	if machine.Status != storage.MachineActive {
+		return false, NotarizeDataComputeUnits, OutputMachineNotActive, nil, nil
+	}
+
+	// The data is owned by whoever attested the machine, the machine only
+	// vouches for having produced it.
+	if err := storage.NotarizeData(ctx, mu, txID, c.MachineAttestTx, machine.Attester, c.DataCID, c.DataType, timestamp); err != nil {
+		return false, NotarizeDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddDataCIDNotarization(ctx, mu, multihash, txID, timestamp); err != nil {
+		return false, NotarizeDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, NotarizeDataComputeUnits, nil, nil, nil
 }
 
 func (*NotarizeData) MaxComputeUnits(chain.Rules) uint64 {
@@ -71,16 +99,14 @@ func (*NotarizeData) MaxComputeUnits(chain.Rules) uint64 {
 
 func (c *NotarizeData) Size() int {
 	// TODO: add small bytes (smaller int prefix)
-	return (codec.BytesLen(c.MachineAttestTx) +
-		codec.BytesLen(c.DataOwnerAddr) +
+	return (consts.IDLen +
 		codec.BytesLen(c.DataCID) +
 		codec.BytesLen(c.DataType))
 
 }
 
 func (c *NotarizeData) Marshal(p *codec.Packer) {
-	p.PackBytes(c.MachineAttestTx)
-	p.PackBytes(c.DataOwnerAddr)
+	p.PackID(c.MachineAttestTx)
 	p.PackBytes(c.DataCID)
 	p.PackBytes(c.DataType)
 
@@ -90,8 +116,7 @@ func UnmarshalNotarizeData(p *codec.Packer, _ *warp.Message) (chain.Action, erro
 
 	var create NotarizeData
 
-	p.UnpackBytes(MachineAttestTxUnits, true, &create.MachineAttestTx)
-	p.UnpackBytes(DataOwnerAddrUnits, true, &create.DataOwnerAddr)
+	p.UnpackID(true, &create.MachineAttestTx)
 	p.UnpackBytes(DataCIDUnits, true, &create.DataCID)
 	p.UnpackBytes(DataTypeUnits, true, &create.DataType)
 
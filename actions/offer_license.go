@@ -0,0 +1,170 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*OfferLicense)(nil)
+
+// OfferLicense sells time-boxed access to a dataset or to everything a
+// machine notarizes. Licensees buy it with [PurchaseLicense].
+type OfferLicense struct {
+	// [Kind] selects what [Subject] refers to: the txID of a [NotarizeData]
+	// for [storage.LicenseDataset] or of an [AttestMachine] for
+	// [storage.LicenseMachine]. Datasets are licensed by their owner and
+	// machines by their attester.
+	Kind    storage.LicenseKind `json:"kind"`
+	Subject ids.ID              `json:"subject"`
+
+	// [Price] units of [Asset] buy access for [Period] milliseconds.
+	Asset  ids.ID `json:"asset"`
+	Price  uint64 `json:"price"`
+	Period int64  `json:"period"`
+
+	// [TermsHash] is the sha256 of the license terms agreed to by licensees.
+	TermsHash []byte `json:"terms_hash"`
+}
+
+func (*OfferLicense) GetTypeID() uint8 {
+	return offerLicenseID
+}
+
+func (o *OfferLicense) StateKeys(_ chain.Auth, txID ids.ID) []string {
+	return []string{
+		string(licenseSubjectKey(o.Kind, o.Subject)),
+		string(storage.AssetKey(o.Asset)),
+		string(storage.LicenseOfferKey(txID)),
+	}
+}
+
+func (*OfferLicense) StateKeysMaxChunks() []uint16 {
+	return []uint16{licenseSubjectChunks, storage.AssetChunks, storage.LicenseOfferChunks}
+}
+
+func (*OfferLicense) OutputsWarpMessage() bool {
+	return false
+}
+
+func (o *OfferLicense) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if o.Price == 0 {
+		return false, LicenseComputeUnits, OutputValueZero, nil, nil
+	}
+	if o.Period <= 0 {
+		return false, LicenseComputeUnits, OutputInvalidLicensePeriod, nil, nil
+	}
+	if len(o.TermsHash) != sha256.Size {
+		return false, LicenseComputeUnits, OutputInvalidTermsHash, nil, nil
+	}
+
+	switch o.Kind {
+	case storage.LicenseDataset:
+		exists, notarized, err := storage.GetNotarizeData(ctx, mu, o.Subject)
+		if err != nil {
+			return false, LicenseComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if !exists {
+			return false, LicenseComputeUnits, OutputNotarizationNotFound, nil, nil
+		}
+		if notarized.DataOwnerAddr != auth.Actor() {
+			return false, LicenseComputeUnits, OutputNotDataOwner, nil, nil
+		}
+	case storage.LicenseMachine:
+		machine, output := getAttestedMachine(ctx, mu, o.Subject, auth.Actor())
+		if output != nil {
+			return false, LicenseComputeUnits, output, nil, nil
+		}
+		if machine.Status == storage.MachineRevoked {
+			return false, LicenseComputeUnits, OutputMachineRevoked, nil, nil
+		}
+	default:
+		return false, LicenseComputeUnits, OutputInvalidLicenseKind, nil, nil
+	}
+
+	exists, _, _, _, _, _, _, err := storage.GetAsset(ctx, mu, o.Asset)
+	if err != nil {
+		return false, LicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, LicenseComputeUnits, OutputAssetMissing, nil, nil
+	}
+	if err := storage.SetLicenseOffer(ctx, mu, txID, storage.LicenseOfferData{
+		Kind:      o.Kind,
+		Subject:   o.Subject,
+		Licensor:  auth.Actor(),
+		Asset:     o.Asset,
+		Price:     o.Price,
+		Period:    o.Period,
+		TermsHash: o.TermsHash,
+	}); err != nil {
+		return false, LicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, LicenseComputeUnits, nil, nil, nil
+}
+
+func (*OfferLicense) MaxComputeUnits(chain.Rules) uint64 {
+	return LicenseComputeUnits
+}
+
+func (o *OfferLicense) Size() int {
+	return consts.ByteLen + consts.IDLen*2 + consts.Uint64Len + consts.Int64Len + codec.BytesLen(o.TermsHash)
+}
+
+func (o *OfferLicense) Marshal(p *codec.Packer) {
+	p.PackByte(byte(o.Kind))
+	p.PackID(o.Subject)
+	p.PackID(o.Asset)
+	p.PackUint64(o.Price)
+	p.PackInt64(o.Period)
+	p.PackBytes(o.TermsHash)
+}
+
+func UnmarshalOfferLicense(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var offer OfferLicense
+	offer.Kind = storage.LicenseKind(p.UnpackByte())
+	p.UnpackID(true, &offer.Subject)
+	p.UnpackID(false, &offer.Asset) // empty ID is the native asset
+	offer.Price = p.UnpackUint64(true)
+	offer.Period = p.UnpackInt64(true)
+	p.UnpackBytes(sha256.Size, true, &offer.TermsHash)
+	return &offer, p.Err()
+}
+
+func (*OfferLicense) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// licenseSubjectChunks is the larger of the chunks of the records a license
+// can refer to.
+const licenseSubjectChunks = storage.MachineCIDChunks
+
+// licenseSubjectKey returns the key of the record licensed by [kind] and
+// [subject].
+func licenseSubjectKey(kind storage.LicenseKind, subject ids.ID) []byte {
+	if kind == storage.LicenseMachine {
+		return storage.AttestMachineKey(subject)
+	}
+	return storage.NotarizeDataKey(subject)
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	dauth "dataverse/auth"
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*OnboardMachine)(nil)
+
+// OnboardMachine registers, attests and funds a machine in one transaction.
+// It runs [RegisterMachine], [AttestMachine] and, if [Value] is not zero,
+// [Transfer] in order. If any of them fails, the whole action fails and none
+// of their changes are kept.
+//
+// The registration and the attestation are both recorded under the txID of
+// the onboarding transaction. The machine is attested and funded at the
+// address of [MachineKey].
+type OnboardMachine struct {
+	MachineCategory     []byte `json:"machine_category"`
+	MachineManufacturer []byte `json:"machine_manufacturer"`
+	MachineCID          []byte `json:"machine_cid"`
+
+	// [Metadata] is attested with the machine, see [AttestMachine].
+	Metadata storage.Metadata `json:"metadata"`
+
+	// [KeyType], [MachineKey] and [Signature] prove possession of the
+	// machine key, as for [RegisterMachine].
+	KeyType    uint8  `json:"key_type"`
+	MachineKey []byte `json:"machine_key"`
+	Signature  []byte `json:"signature"`
+
+	// [Value] units of [Asset] are transferred to the machine so it can pay
+	// for its own transactions.
+	Asset ids.ID `json:"asset"`
+	Value uint64 `json:"value"`
+}
+
+func (*OnboardMachine) GetTypeID() uint8 {
+	return onboardMachineID
+}
+
+// MachineAddress is the address of the machine key. A key that doesn't
+// derive one fails the registration step, before the address is used.
+func (o *OnboardMachine) MachineAddress() codec.Address {
+	addr, _ := dauth.MachineKeyAddress(o.KeyType, o.MachineKey)
+	return addr
+}
+
+func (o *OnboardMachine) steps() []chain.Action {
+	addr := o.MachineAddress()
+	steps := []chain.Action{
+		&RegisterMachine{
+			MachineCID: o.MachineCID,
+			KeyType:    o.KeyType,
+			MachineKey: o.MachineKey,
+			Signature:  o.Signature,
+		},
+		&AttestMachine{
+			MachineAddress:      addr,
+			MachineCategory:     o.MachineCategory,
+			MachineManufacturer: o.MachineManufacturer,
+			MachineCID:          o.MachineCID,
+			Metadata:            o.Metadata,
+		},
+	}
+	if o.Value > 0 {
+		steps = append(steps, &Transfer{
+			To:    addr,
+			Asset: o.Asset,
+			Value: o.Value,
+		})
+	}
+	return steps
+}
+
+func (o *OnboardMachine) StateKeys(auth chain.Auth, txID ids.ID) []string {
+	keys := []string{}
+	for _, step := range o.steps() {
+		keys = append(keys, step.StateKeys(auth, txID)...)
+	}
+	return keys
+}
+
+func (o *OnboardMachine) StateKeysMaxChunks() []uint16 {
+	chunks := []uint16{}
+	for _, step := range o.steps() {
+		chunks = append(chunks, step.StateKeysMaxChunks()...)
+	}
+	return chunks
+}
+
+func (*OnboardMachine) OutputsWarpMessage() bool {
+	return false
+}
+
+func (o *OnboardMachine) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	txID ids.ID,
+	warpVerified bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	var computeUnits uint64
+	for _, step := range o.steps() {
+		success, units, output, _, err := step.Execute(ctx, r, mu, timestamp, auth, txID, warpVerified)
+		computeUnits += units
+		if err != nil || !success {
+			return false, computeUnits, output, nil, err
+		}
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (o *OnboardMachine) MaxComputeUnits(r chain.Rules) uint64 {
+	var computeUnits uint64
+	for _, step := range o.steps() {
+		computeUnits += step.MaxComputeUnits(r)
+	}
+	return computeUnits
+}
+
+func (o *OnboardMachine) Size() int {
+	return codec.BytesLen(o.MachineCategory) +
+		codec.BytesLen(o.MachineManufacturer) +
+		codec.BytesLen(o.MachineCID) +
+		storage.MetadataSize(o.Metadata) +
+		consts.ByteLen + codec.BytesLen(o.MachineKey) + codec.BytesLen(o.Signature) +
+		consts.IDLen + consts.Uint64Len
+}
+
+func (o *OnboardMachine) Marshal(p *codec.Packer) {
+	p.PackBytes(o.MachineCategory)
+	p.PackBytes(o.MachineManufacturer)
+	p.PackBytes(o.MachineCID)
+	storage.PackMetadata(p, o.Metadata)
+	p.PackByte(o.KeyType)
+	p.PackBytes(o.MachineKey)
+	p.PackBytes(o.Signature)
+	p.PackID(o.Asset)
+	p.PackUint64(o.Value)
+}
+
+func UnmarshalOnboardMachine(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var onboard OnboardMachine
+	p.UnpackBytes(MachineCategoryUnits, true, &onboard.MachineCategory)
+	p.UnpackBytes(MachineManufacturerUnits, true, &onboard.MachineManufacturer)
+	p.UnpackBytes(MachineCIDUnits, true, &onboard.MachineCID)
+	metadata, err := storage.UnpackMetadata(p)
+	if err != nil {
+		return nil, err
+	}
+	onboard.Metadata = metadata
+	onboard.KeyType = p.UnpackByte()
+	p.UnpackBytes(dauth.MaxMachineKeyLen, true, &onboard.MachineKey)
+	p.UnpackBytes(dauth.MaxMachineSignatureLen, true, &onboard.Signature)
+	p.UnpackID(false, &onboard.Asset) // empty ID is the native asset
+	onboard.Value = p.UnpackUint64(false)
+	return &onboard, p.Err()
+}
+
+func (*OnboardMachine) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
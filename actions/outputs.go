@@ -42,17 +42,82 @@ var (
 	OutputProjectDescriptionNotGiven = []byte("Project Description not provided")
 	OutputProjectNameNotGiven        = []byte("Project Name not provided")
 	OutputProjectInvalidOwner        = []byte("Project Owner Invalid format")
+	OutputProjectNotFound            = []byte("Project not found")
+	OutputTooManyMaintainers         = []byte("Too many project maintainers")
+	OutputNotProjectMaintainer       = []byte("Only the project owner or a maintainer can publish updates")
+	OutputNotProjectOwner            = []byte("Only the project owner can manage the project")
+	OutputMaintainerExists           = []byte("Maintainer already added")
+	OutputMaintainerNotFound         = []byte("Maintainer not found")
+	OutputReleaseKeyNotProvided      = []byte("Release key not provided")
+	OutputReleaseKeyNotSet           = []byte("Project has no release key to verify updates")
 
 	OutputProjectTxIdNotProvided          = []byte("Project Txid not provided")
 	OutputUpdateExecutableHashNotProvided = []byte("Update Executable Hash not provided")
 	OutputUpdateExecutableIPFSNotProvided = []byte("Update Executable IPFS url Not Provided")
 	OutputForDeviceNameNotProvided        = []byte("Update Device Name Not Provided")
 	OutputUpdateVersionNotProvided        = []byte("Update Version Not Provided")
+	OutputUpdateVersionNotIncreasing      = []byte("Update version must be greater than the latest version for the device")
+	OutputInvalidUpdateDigest             = []byte("Update executable hash must be a SHA-256 digest")
+	OutputUpdateSizeNotProvided           = []byte("Update size not provided")
+	OutputInvalidUpdateSignature          = []byte("Update manifest is not signed by the project release key")
+	OutputUpdateNotFound                  = []byte("Update not found")
+	OutputInstalledHashMismatch           = []byte("Installed hash does not match the update")
+	OutputNotReportingMachine             = []byte("Update result must be reported by the attested machine")
 
 	OutputRegisterMachineNotProvided = []byte("Invalid Machine CID")
 
-	OutputInvalidMachineAddressLen      = []byte("Invalid Machine Address, address should be of length 44")
 	OutputInvalidMachineCategoryLen     = []byte("Category should not exceed len 100")
 	OutputInvalidMachineManufacturerLen = []byte("Manufacturer should not exceed len 100")
 	OutputInvalidMachineCIDLen          = []byte("Invalid Machine CID, CID should be of length 66")
+
+	OutputMachineNotAttested          = []byte("Machine attestation not found")
+	OutputNotAttestedMachine          = []byte("Data must be notarized by the attested machine")
+	OutputNotMachineAttester          = []byte("Only the attester can change the status of a machine")
+	OutputMachineNotActive            = []byte("Machine is suspended or revoked")
+	OutputMachineNotSuspended         = []byte("Machine is not suspended")
+	OutputMachineRevoked              = []byte("Machine is already revoked")
+	OutputMachineAttestedByOther      = []byte("Machine is attested by another attester")
+	OutputPreviousAttestationMismatch = []byte("Previous attestation is not the latest attestation of the machine")
+	OutputInvalidCompromiseTime       = []byte("Compromise time must not be in the future")
+
+	OutputInvalidDataCID       = []byte("Data CID is not a valid CID")
+	OutputNotarizationNotFound = []byte("Notarized data not found")
+	OutputNotDataOwner         = []byte("Only the data owner can list it")
+	OutputListingNotFound      = []byte("Listing not found")
+	OutputListingClosed        = []byte("Listing is closed")
+	OutputListingTermsMismatch = []byte("Seller, asset or price does not match the listing")
+	OutputSelfPurchase         = []byte("Seller cannot buy their own listing")
+	OutputAlreadyPurchased     = []byte("Listing already purchased")
+
+	OutputInvalidLicenseKind         = []byte("License must be for a dataset or a machine")
+	OutputInvalidLicensePeriod       = []byte("Invalid license period")
+	OutputInvalidTermsHash           = []byte("Terms hash must be a sha256 digest")
+	OutputLicenseOfferNotFound       = []byte("License offer not found")
+	OutputLicenseOfferClosed         = []byte("License offer is closed")
+	OutputLicenseTermsMismatch       = []byte("Subject, licensor, asset or price does not match the offer")
+	OutputLicenseHeldUnderOtherOffer = []byte("Licensee holds a running license from another offer")
+
+	OutputInvalidKeyEnvelope  = []byte("Invalid key envelope")
+	OutputNotPurchased        = []byte("Buyer has not purchased the listing")
+	OutputBuyerKeyNotProvided = []byte("Buyer key to seal the content key to not provided")
+
+	OutputInvalidBatchRoot      = []byte("Batch root must be a sha256 digest")
+	OutputInvalidBatchTimeRange = []byte("Invalid batch time range")
+
+	OutputInvalidMachineKeyProof = []byte("Machine key signature does not prove possession of the key")
+	OutputMachineCIDRegistered   = []byte("Machine CID is already registered")
+	OutputMachineKeyRegistered   = []byte("Machine key is already registered")
+	OutputMachineNotRegistered   = []byte("Machine CID is not registered with a machine key")
+	OutputMachineAddressMismatch = []byte("Machine address is not the one registered with the machine CID")
+
+	OutputManufacturerExists      = []byte("Manufacturer already exists")
+	OutputManufacturerNotFound    = []byte("Manufacturer not found")
+	OutputNotManufacturerOwner    = []byte("Actor is not the manufacturer owner")
+	OutputNotManufacturerAttester = []byte("Actor is not an attester of the manufacturer")
+	OutputAttesterExists          = []byte("Attester already authorized")
+	OutputAttesterNotFound        = []byte("Attester not authorized")
+	OutputTooManyAttesters        = []byte("Too many attesters")
+
+	OutputMetadataSchemaNotFound = []byte("No metadata schema registered for the machine category")
+	OutputNotSchemaOwner         = []byte("Actor is not the metadata schema owner")
 )
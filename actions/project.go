@@ -0,0 +1,60 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// getProjectOwner returns whether [project] exists, its record and its owner.
+func getProjectOwner(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+) (bool, storage.ProjectData, codec.Address, error) {
+	exists, data, err := storage.GetProject(ctx, im, project)
+	if err != nil || !exists {
+		return exists, data, codec.EmptyAddress, err
+	}
+	owner, err := storage.ProjectOwnerAddress(data)
+	return true, data, owner, err
+}
+
+// canPublishUpdates reports whether [project] exists and [actor] is its owner
+// or one of its maintainers.
+func canPublishUpdates(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+	actor codec.Address,
+) (bool, bool, error) {
+	exists, _, owner, err := getProjectOwner(ctx, im, project)
+	if err != nil || !exists {
+		return exists, false, err
+	}
+	if owner == actor {
+		return true, true, nil
+	}
+	maintainers, err := storage.GetProjectMaintainers(ctx, im, project)
+	if err != nil {
+		return true, false, err
+	}
+	return true, maintainerIndex(maintainers, actor) >= 0, nil
+}
+
+// maintainerIndex returns the position of [addr] in [maintainers] or -1.
+func maintainerIndex(maintainers []codec.Address, addr codec.Address) int {
+	for i, maintainer := range maintainers {
+		if maintainer == addr {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*PurchaseData)(nil)
+
+// PurchaseData pays the seller of a listing and records a receipt for the
+// buyer. Both happen in the same transaction, so neither party has to trust
+// the other to get paid or to hold proof of payment.
+type PurchaseData struct {
+	// [Listing] is the txID of the [ListData] to buy.
+	Listing ids.ID `json:"listing"`
+
+	// [Seller], [Asset] and [Price] must match the listing. We need to
+	// provide them to populate [StateKeys].
+	Seller codec.Address `json:"seller"`
+	Asset  ids.ID        `json:"asset"`
+	Price  uint64        `json:"price"`
+
+	// [BuyerKey] is the ed25519 key the seller seals the content key to. It
+	// is recorded in the receipt and need not be the key the buyer signs
+	// with.
+	BuyerKey ed25519.PublicKey `json:"buyer_key"`
+}
+
+func (*PurchaseData) GetTypeID() uint8 {
+	return purchaseDataID
+}
+
+func (p *PurchaseData) StateKeys(auth chain.Auth, _ ids.ID) []string {
+	return []string{
+		string(storage.ListingKey(p.Listing)),
+		string(storage.PurchaseReceiptKey(p.Listing, auth.Actor())),
+		string(storage.BalanceKey(auth.Actor(), p.Asset)),
+		string(storage.BalanceKey(p.Seller, p.Asset)),
+	}
+}
+
+func (*PurchaseData) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ListingChunks, storage.PurchaseReceiptChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*PurchaseData) OutputsWarpMessage() bool {
+	return false
+}
+
+func (p *PurchaseData) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, listing, err := storage.GetListing(ctx, mu, p.Listing)
+	if err != nil {
+		return false, PurchaseDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, PurchaseDataComputeUnits, OutputListingNotFound, nil, nil
+	}
+	if listing.Closed {
+		return false, PurchaseDataComputeUnits, OutputListingClosed, nil, nil
+	}
+	if listing.Seller != p.Seller || listing.Asset != p.Asset || listing.Price != p.Price {
+		return false, PurchaseDataComputeUnits, OutputListingTermsMismatch, nil, nil
+	}
+	if listing.Seller == auth.Actor() {
+		return false, PurchaseDataComputeUnits, OutputSelfPurchase, nil, nil
+	}
+	if p.BuyerKey == ed25519.EmptyPublicKey {
+		return false, PurchaseDataComputeUnits, OutputBuyerKeyNotProvided, nil, nil
+	}
+	purchased, _, err := storage.GetPurchaseReceipt(ctx, mu, p.Listing, auth.Actor())
+	if err != nil {
+		return false, PurchaseDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if purchased {
+		return false, PurchaseDataComputeUnits, OutputAlreadyPurchased, nil, nil
+	}
+
+	if err := storage.SubBalance(ctx, mu, auth.Actor(), listing.Asset, listing.Price); err != nil {
+		return false, PurchaseDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, listing.Seller, listing.Asset, listing.Price, true); err != nil {
+		return false, PurchaseDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	receipt := storage.PurchaseReceiptData{
+		PurchaseTx: txID,
+		Asset:      listing.Asset,
+		Price:      listing.Price,
+		Timestamp:  timestamp,
+		BuyerKey:   p.BuyerKey,
+	}
+	if err := storage.SetPurchaseReceipt(ctx, mu, p.Listing, auth.Actor(), receipt); err != nil {
+		return false, PurchaseDataComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, PurchaseDataComputeUnits, nil, nil, nil
+}
+
+func (*PurchaseData) MaxComputeUnits(chain.Rules) uint64 {
+	return PurchaseDataComputeUnits
+}
+
+func (*PurchaseData) Size() int {
+	return consts.IDLen*2 + codec.AddressLen + consts.Uint64Len + ed25519.PublicKeyLen
+}
+
+func (p *PurchaseData) Marshal(pk *codec.Packer) {
+	pk.PackID(p.Listing)
+	pk.PackAddress(p.Seller)
+	pk.PackID(p.Asset)
+	pk.PackUint64(p.Price)
+	pk.PackFixedBytes(p.BuyerKey[:])
+}
+
+func UnmarshalPurchaseData(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var purchase PurchaseData
+	p.UnpackID(true, &purchase.Listing)
+	p.UnpackAddress(&purchase.Seller)
+	p.UnpackID(false, &purchase.Asset) // empty ID is the native asset
+	purchase.Price = p.UnpackUint64(true)
+	key := purchase.BuyerKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &key)
+	return &purchase, p.Err()
+}
+
+func (*PurchaseData) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
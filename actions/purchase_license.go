@@ -0,0 +1,167 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*PurchaseLicense)(nil)
+
+// PurchaseLicense pays for [Periods] periods of a license offer. If the actor
+// already holds an unexpired license bought from the same offer, it is
+// extended instead of restarted.
+type PurchaseLicense struct {
+	// [Offer] is the txID of the [OfferLicense] to buy.
+	Offer ids.ID `json:"offer"`
+
+	// [Subject], [Licensor], [Asset] and [Price] must match the offer. We
+	// need to provide them to populate [StateKeys].
+	Subject  ids.ID        `json:"subject"`
+	Licensor codec.Address `json:"licensor"`
+	Asset    ids.ID        `json:"asset"`
+	Price    uint64        `json:"price"`
+
+	// [Periods] is how many periods of the offer to pay for.
+	Periods uint64 `json:"periods"`
+}
+
+func (*PurchaseLicense) GetTypeID() uint8 {
+	return purchaseLicenseID
+}
+
+func (p *PurchaseLicense) StateKeys(auth chain.Auth, _ ids.ID) []string {
+	return []string{
+		string(storage.LicenseOfferKey(p.Offer)),
+		string(storage.LicenseKey(p.Subject, auth.Actor())),
+		string(storage.BalanceKey(auth.Actor(), p.Asset)),
+		string(storage.BalanceKey(p.Licensor, p.Asset)),
+	}
+}
+
+func (*PurchaseLicense) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.LicenseOfferChunks, storage.LicenseChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*PurchaseLicense) OutputsWarpMessage() bool {
+	return false
+}
+
+func (p *PurchaseLicense) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if p.Periods == 0 {
+		return false, PurchaseLicenseComputeUnits, OutputInvalidLicensePeriod, nil, nil
+	}
+	exists, offer, err := storage.GetLicenseOffer(ctx, mu, p.Offer)
+	if err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, PurchaseLicenseComputeUnits, OutputLicenseOfferNotFound, nil, nil
+	}
+	if offer.Closed {
+		return false, PurchaseLicenseComputeUnits, OutputLicenseOfferClosed, nil, nil
+	}
+	if offer.Subject != p.Subject || offer.Licensor != p.Licensor || offer.Asset != p.Asset || offer.Price != p.Price {
+		return false, PurchaseLicenseComputeUnits, OutputLicenseTermsMismatch, nil, nil
+	}
+	if offer.Licensor == auth.Actor() {
+		return false, PurchaseLicenseComputeUnits, OutputSelfPurchase, nil, nil
+	}
+
+	// Extend a running license bought from the same offer. A running license
+	// bought under other terms can't be extended with this offer.
+	start := timestamp
+	exists, license, err := storage.GetLicense(ctx, mu, p.Subject, auth.Actor())
+	if err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists && license.Active(timestamp) {
+		if license.Offer != p.Offer {
+			return false, PurchaseLicenseComputeUnits, OutputLicenseHeldUnderOtherOffer, nil, nil
+		}
+		start = license.Expiry
+	}
+	duration, err := smath.Mul64(uint64(offer.Period), p.Periods)
+	if err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	expiry, err := smath.Add64(uint64(start), duration)
+	if err != nil || expiry > math.MaxInt64 {
+		return false, PurchaseLicenseComputeUnits, OutputInvalidLicensePeriod, nil, nil
+	}
+	cost, err := smath.Mul64(offer.Price, p.Periods)
+	if err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SubBalance(ctx, mu, auth.Actor(), offer.Asset, cost); err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, offer.Licensor, offer.Asset, cost, true); err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetLicense(ctx, mu, p.Subject, auth.Actor(), storage.LicenseData{
+		Offer:     p.Offer,
+		Kind:      offer.Kind,
+		Licensor:  offer.Licensor,
+		Expiry:    int64(expiry),
+		TermsHash: offer.TermsHash,
+	}); err != nil {
+		return false, PurchaseLicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, PurchaseLicenseComputeUnits, nil, nil, nil
+}
+
+func (*PurchaseLicense) MaxComputeUnits(chain.Rules) uint64 {
+	return PurchaseLicenseComputeUnits
+}
+
+func (*PurchaseLicense) Size() int {
+	return consts.IDLen*3 + codec.AddressLen + consts.Uint64Len*2
+}
+
+func (p *PurchaseLicense) Marshal(pk *codec.Packer) {
+	pk.PackID(p.Offer)
+	pk.PackID(p.Subject)
+	pk.PackAddress(p.Licensor)
+	pk.PackID(p.Asset)
+	pk.PackUint64(p.Price)
+	pk.PackUint64(p.Periods)
+}
+
+func UnmarshalPurchaseLicense(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var purchase PurchaseLicense
+	p.UnpackID(true, &purchase.Offer)
+	p.UnpackID(true, &purchase.Subject)
+	p.UnpackAddress(&purchase.Licensor)
+	p.UnpackID(false, &purchase.Asset) // empty ID is the native asset
+	purchase.Price = p.UnpackUint64(true)
+	purchase.Periods = p.UnpackUint64(true)
+	return &purchase, p.Err()
+}
+
+func (*PurchaseLicense) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
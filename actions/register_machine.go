@@ -6,34 +6,63 @@ package actions
 import (
 	"context"
 
+	dauth "dataverse/auth"
 	"dataverse/storage"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*RegisterMachine)(nil)
 
+// registerMachinePrefix separates registration proofs from anything else a
+// machine key may sign.
+var registerMachinePrefix = []byte("dataverse:register-machine:")
+
+// RegisterMachineMessage is what a machine signs with its key to register
+// [machineCID] on [chainID].
+func RegisterMachineMessage(chainID ids.ID, machineCID []byte) []byte {
+	msg := make([]byte, 0, len(registerMachinePrefix)+consts.IDLen+len(machineCID))
+	msg = append(msg, registerMachinePrefix...)
+	msg = append(msg, chainID[:]...)
+	return append(msg, machineCID...)
+}
+
 type RegisterMachine struct {
 	MachineCID []byte `json:"machine_cid"`
+
+	// [MachineKey] is the public key of type [KeyType] (see the auth
+	// package) held by the machine. [Signature] proves possession of it, it
+	// signs [RegisterMachineMessage] for the chain the registration is sent
+	// to. A CID or a key can only be registered once.
+	KeyType    uint8  `json:"key_type"`
+	MachineKey []byte `json:"machine_key"`
+	Signature  []byte `json:"signature"`
 }
 
 func (*RegisterMachine) GetTypeID() uint8 {
 	return registerMachineCIDID
 }
 
-func (*RegisterMachine) StateKeys(_ chain.Auth, txID ids.ID) []string {
+func (c *RegisterMachine) StateKeys(_ chain.Auth, txID ids.ID) []string {
+	// An invalid key fails the registration, the address it indexes is
+	// never written
+	addr, _ := dauth.MachineKeyAddress(c.KeyType, c.MachineKey)
 	return []string{
 		string(storage.RegisterMachineCIDKey(txID)),
+		string(storage.MachineCIDIndexKey(c.MachineCID)),
+		string(storage.MachineKeyIndexKey(c.KeyType, c.MachineKey)),
+		string(storage.MachineAddressIndexKey(addr)),
 	}
 }
 
 func (*RegisterMachine) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.MachineCIDChunks}
+	return []uint16{storage.MachineCIDChunks, storage.MachineIndexChunks, storage.MachineIndexChunks, storage.MachineIndexChunks}
 }
 
 func (*RegisterMachine) OutputsWarpMessage() bool {
@@ -42,10 +71,10 @@ func (*RegisterMachine) OutputsWarpMessage() bool {
 
 func (c *RegisterMachine) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
 	_ int64,
-	auth chain.Auth,
+	_ chain.Auth,
 	txID ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
@@ -53,10 +82,43 @@ func (c *RegisterMachine) Execute(
 	if len(c.MachineCID) != 66 {
 		return false, RegisterMachineComputeUnits, OutputRegisterMachineNotProvided, nil, nil
 	}
+	addr, err := dauth.VerifyMachineKey(c.KeyType, c.MachineKey, RegisterMachineMessage(r.ChainID(), c.MachineCID), c.Signature)
+	if err != nil {
+		return false, RegisterMachineComputeUnits, OutputInvalidMachineKeyProof, nil, nil
+	}
 
-	// It should only be possible to overwrite an existing asset if there is
-	// a hash collision.
-	if err := storage.SetMachineCID(ctx, mu, txID, c.MachineCID); err != nil {
+	cidIndex := storage.MachineCIDIndexKey(c.MachineCID)
+	exists, _, err := storage.GetMachineIndex(ctx, mu, cidIndex)
+	if err != nil {
+		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists {
+		return false, RegisterMachineComputeUnits, OutputMachineCIDRegistered, nil, nil
+	}
+	keyIndex := storage.MachineKeyIndexKey(c.KeyType, c.MachineKey)
+	exists, _, err = storage.GetMachineIndex(ctx, mu, keyIndex)
+	if err != nil {
+		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists {
+		return false, RegisterMachineComputeUnits, OutputMachineKeyRegistered, nil, nil
+	}
+
+	if err := storage.SetMachineCID(ctx, mu, txID, storage.RegisterMachineCIDData{
+		MachineCID:     c.MachineCID,
+		KeyType:        c.KeyType,
+		MachineKey:     c.MachineKey,
+		MachineAddress: addr,
+	}); err != nil {
+		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetMachineIndex(ctx, mu, cidIndex, txID); err != nil {
+		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetMachineIndex(ctx, mu, keyIndex, txID); err != nil {
+		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetMachineIndex(ctx, mu, storage.MachineAddressIndexKey(addr), txID); err != nil {
 		return false, RegisterMachineComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, RegisterMachineComputeUnits, nil, nil, nil
@@ -68,12 +130,18 @@ func (*RegisterMachine) MaxComputeUnits(chain.Rules) uint64 {
 
 func (c *RegisterMachine) Size() int {
 	// TODO: add small bytes (smaller int prefix)
-	return (codec.BytesLen(c.MachineCID))
+	return (codec.BytesLen(c.MachineCID) +
+		consts.ByteLen +
+		codec.BytesLen(c.MachineKey) +
+		codec.BytesLen(c.Signature))
 
 }
 
 func (c *RegisterMachine) Marshal(p *codec.Packer) {
 	p.PackBytes(c.MachineCID)
+	p.PackByte(c.KeyType)
+	p.PackBytes(c.MachineKey)
+	p.PackBytes(c.Signature)
 }
 
 func UnmarshalRegisterMachineCID(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
@@ -81,6 +149,9 @@ func UnmarshalRegisterMachineCID(p *codec.Packer, _ *warp.Message) (chain.Action
 	var create RegisterMachine
 
 	p.UnpackBytes(MachineCIDUnits, true, &create.MachineCID)
+	create.KeyType = p.UnpackByte()
+	p.UnpackBytes(dauth.MaxMachineKeyLen, true, &create.MachineKey)
+	p.UnpackBytes(dauth.MaxMachineSignatureLen, true, &create.Signature)
 
 	return &create, p.Err()
 
@@ -0,0 +1,88 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*ReinstateMachine)(nil)
+
+type ReinstateMachine struct {
+	// [MachineAttestTx] is the txID of the [AttestMachine] record to reinstate.
+	// Only the attester that created it can reinstate it.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+}
+
+func (*ReinstateMachine) GetTypeID() uint8 {
+	return reinstateMachineID
+}
+
+func (m *ReinstateMachine) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.AttestMachineKey(m.MachineAttestTx)),
+	}
+}
+
+func (*ReinstateMachine) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.MachineCIDChunks}
+}
+
+func (*ReinstateMachine) OutputsWarpMessage() bool {
+	return false
+}
+
+func (m *ReinstateMachine) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	output := setMachineStatus(ctx, mu, m.MachineAttestTx, auth.Actor(), timestamp, func(machine *storage.AttestMachineData) []byte {
+		if machine.Status != storage.MachineSuspended {
+			return OutputMachineNotSuspended
+		}
+		machine.Status = storage.MachineActive
+		return nil
+	})
+	if output != nil {
+		return false, MachineStatusComputeUnits, output, nil, nil
+	}
+	return true, MachineStatusComputeUnits, nil, nil, nil
+}
+
+func (*ReinstateMachine) MaxComputeUnits(chain.Rules) uint64 {
+	return MachineStatusComputeUnits
+}
+
+func (*ReinstateMachine) Size() int {
+	return consts.IDLen
+}
+
+func (m *ReinstateMachine) Marshal(p *codec.Packer) {
+	p.PackID(m.MachineAttestTx)
+}
+
+func UnmarshalReinstateMachine(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var m ReinstateMachine
+	p.UnpackID(true, &m.MachineAttestTx)
+	return &m, p.Err()
+}
+
+func (*ReinstateMachine) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
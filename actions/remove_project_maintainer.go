@@ -0,0 +1,106 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*RemoveProjectMaintainer)(nil)
+
+type RemoveProjectMaintainer struct {
+	// [Project] is the txID of the [CreateProject] to modify. Only the
+	// project owner can remove maintainers.
+	Project    ids.ID        `json:"project"`
+	Maintainer codec.Address `json:"maintainer"`
+}
+
+func (*RemoveProjectMaintainer) GetTypeID() uint8 {
+	return removeProjectMaintainerID
+}
+
+func (r *RemoveProjectMaintainer) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ProjectKey(r.Project)),
+		string(storage.ProjectMaintainersKey(r.Project)),
+	}
+}
+
+func (*RemoveProjectMaintainer) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProjectDescriptionChunks, storage.ProjectMaintainersChunks}
+}
+
+func (*RemoveProjectMaintainer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RemoveProjectMaintainer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, _, owner, err := getProjectOwner(ctx, mu, r.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ManageProjectComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if owner != auth.Actor() {
+		return false, ManageProjectComputeUnits, OutputNotProjectOwner, nil, nil
+	}
+
+	maintainers, err := storage.GetProjectMaintainers(ctx, mu, r.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	i := maintainerIndex(maintainers, r.Maintainer)
+	if i < 0 {
+		return false, ManageProjectComputeUnits, OutputMaintainerNotFound, nil, nil
+	}
+	maintainers = append(maintainers[:i], maintainers[i+1:]...)
+	if err := storage.SetProjectMaintainers(ctx, mu, r.Project, maintainers); err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageProjectComputeUnits, nil, nil, nil
+}
+
+func (*RemoveProjectMaintainer) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageProjectComputeUnits
+}
+
+func (*RemoveProjectMaintainer) Size() int {
+	return consts.IDLen + codec.AddressLen
+}
+
+func (r *RemoveProjectMaintainer) Marshal(p *codec.Packer) {
+	p.PackID(r.Project)
+	p.PackAddress(r.Maintainer)
+}
+
+func UnmarshalRemoveProjectMaintainer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var remove RemoveProjectMaintainer
+	p.UnpackID(true, &remove.Project)
+	p.UnpackAddress(&remove.Maintainer)
+	return &remove, p.Err()
+}
+
+func (*RemoveProjectMaintainer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*ReportUpdateResult)(nil)
+
+type ReportUpdateResult struct {
+	// [UpdateTx] is the txID of the [CreateUpdate] that was applied.
+	UpdateTx ids.ID `json:"update_tx"`
+
+	// [MachineAttestTx] is the txID of the [AttestMachine] record of the
+	// reporting machine. The transaction must be signed by the attested
+	// machine address.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+
+	Success          bool            `json:"success"`
+	InstalledHash    []byte          `json:"installed_hash"`
+	InstalledVersion storage.Version `json:"installed_version"`
+}
+
+func (*ReportUpdateResult) GetTypeID() uint8 {
+	return reportUpdateResultID
+}
+
+func (r *ReportUpdateResult) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.AttestMachineKey(r.MachineAttestTx)),
+		string(storage.UpdateKey(r.UpdateTx)),
+		string(storage.UpdateReportKey(r.UpdateTx, r.MachineAttestTx)),
+		string(storage.UpdateStatsKey(r.UpdateTx)),
+	}
+}
+
+func (*ReportUpdateResult) StateKeysMaxChunks() []uint16 {
+	return []uint16{
+		storage.MachineCIDChunks,
+		storage.UpdateExecutableHashChunks,
+		storage.UpdateReportChunks,
+		storage.UpdateStatsChunks,
+	}
+}
+
+func (*ReportUpdateResult) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *ReportUpdateResult) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, machine, err := storage.GetAttestMachine(ctx, mu, r.MachineAttestTx)
+	if err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ReportUpdateResultComputeUnits, OutputMachineNotAttested, nil, nil
+	}
+	if machine.MachineAddress != auth.Actor() {
+		return false, ReportUpdateResultComputeUnits, OutputNotReportingMachine, nil, nil
+	}
+
+	exists, update, err := storage.GetUpdate(ctx, mu, r.UpdateTx)
+	if err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ReportUpdateResultComputeUnits, OutputUpdateNotFound, nil, nil
+	}
+	if r.Success && !bytes.Equal(r.InstalledHash, update.UpdateExecutableHash) {
+		return false, ReportUpdateResultComputeUnits, OutputInstalledHashMismatch, nil, nil
+	}
+
+	succeeded, failed, err := storage.GetUpdateStats(ctx, mu, r.UpdateTx)
+	if err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	// A machine may report again (e.g. after retrying a failed install), only
+	// its latest report is counted.
+	reported, previous, err := storage.GetUpdateReport(ctx, mu, r.UpdateTx, r.MachineAttestTx)
+	if err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if reported {
+		if previous.Success {
+			succeeded--
+		} else {
+			failed--
+		}
+	}
+	if r.Success {
+		succeeded++
+	} else {
+		failed++
+	}
+
+	report := storage.UpdateReportData{
+		Success:          r.Success,
+		InstalledHash:    r.InstalledHash,
+		InstalledVersion: r.InstalledVersion,
+		Timestamp:        timestamp,
+	}
+	if err := storage.SetUpdateReport(ctx, mu, r.UpdateTx, r.MachineAttestTx, report); err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetUpdateStats(ctx, mu, r.UpdateTx, succeeded, failed); err != nil {
+		return false, ReportUpdateResultComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ReportUpdateResultComputeUnits, nil, nil, nil
+}
+
+func (*ReportUpdateResult) MaxComputeUnits(chain.Rules) uint64 {
+	return ReportUpdateResultComputeUnits
+}
+
+func (r *ReportUpdateResult) Size() int {
+	return consts.IDLen*2 + consts.BoolLen + codec.BytesLen(r.InstalledHash) + storage.VersionLen
+}
+
+func (r *ReportUpdateResult) Marshal(p *codec.Packer) {
+	p.PackID(r.UpdateTx)
+	p.PackID(r.MachineAttestTx)
+	p.PackBool(r.Success)
+	p.PackBytes(r.InstalledHash)
+	storage.PackVersion(p, r.InstalledVersion)
+}
+
+func UnmarshalReportUpdateResult(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var report ReportUpdateResult
+	p.UnpackID(true, &report.UpdateTx)
+	p.UnpackID(true, &report.MachineAttestTx)
+	report.Success = p.UnpackBool()
+	p.UnpackBytes(UpdateExecutableHashUnits, false, &report.InstalledHash)
+	report.InstalledVersion = storage.UnpackVersion(p)
+	return &report, p.Err()
+}
+
+func (*ReportUpdateResult) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
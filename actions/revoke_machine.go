@@ -0,0 +1,103 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RevokeMachine)(nil)
+
+type RevokeMachine struct {
+	// [MachineAttestTx] is the txID of the [AttestMachine] record to revoke.
+	// Only the attester that created it can revoke it. Revocation can't be
+	// undone.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+
+	// [CompromisedSince] is the time (in ms) from which the machine should
+	// no longer be trusted, data it notarized from then on is reported as
+	// such. It defaults to the time of the revocation.
+	CompromisedSince int64 `json:"compromised_since"`
+}
+
+func (*RevokeMachine) GetTypeID() uint8 {
+	return revokeMachineID
+}
+
+func (m *RevokeMachine) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.AttestMachineKey(m.MachineAttestTx)),
+	}
+}
+
+func (*RevokeMachine) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.MachineCIDChunks}
+}
+
+func (*RevokeMachine) OutputsWarpMessage() bool {
+	return false
+}
+
+func (m *RevokeMachine) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if m.CompromisedSince < 0 || m.CompromisedSince > timestamp {
+		return false, MachineStatusComputeUnits, OutputInvalidCompromiseTime, nil, nil
+	}
+	output := setMachineStatus(ctx, mu, m.MachineAttestTx, auth.Actor(), timestamp, func(machine *storage.AttestMachineData) []byte {
+		if machine.Status == storage.MachineRevoked {
+			return OutputMachineRevoked
+		}
+		machine.Status = storage.MachineRevoked
+		machine.RevokedAt = timestamp
+		if m.CompromisedSince > 0 {
+			machine.RevokedAt = m.CompromisedSince
+		}
+		return nil
+	})
+	if output != nil {
+		return false, MachineStatusComputeUnits, output, nil, nil
+	}
+	return true, MachineStatusComputeUnits, nil, nil, nil
+}
+
+func (*RevokeMachine) MaxComputeUnits(chain.Rules) uint64 {
+	return MachineStatusComputeUnits
+}
+
+func (*RevokeMachine) Size() int {
+	return consts.IDLen + consts.Int64Len
+}
+
+func (m *RevokeMachine) Marshal(p *codec.Packer) {
+	p.PackID(m.MachineAttestTx)
+	p.PackInt64(m.CompromisedSince)
+}
+
+func UnmarshalRevokeMachine(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var m RevokeMachine
+	p.UnpackID(true, &m.MachineAttestTx)
+	m.CompromisedSince = p.UnpackInt64(false)
+	return &m, p.Err()
+}
+
+func (*RevokeMachine) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*RevokeManufacturerAttester)(nil)
+
+// RevokeManufacturerAttester stops [Attester] from attesting new machines as
+// made by [Manufacturer]. Machines it already attested keep their record.
+type RevokeManufacturerAttester struct {
+	// [Manufacturer] is the name of the manufacturer to modify. Only its
+	// owner can revoke attesters.
+	Manufacturer []byte        `json:"manufacturer"`
+	Attester     codec.Address `json:"attester"`
+}
+
+func (*RevokeManufacturerAttester) GetTypeID() uint8 {
+	return revokeManufacturerAttesterID
+}
+
+func (r *RevokeManufacturerAttester) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ManufacturerKey(r.Manufacturer)),
+	}
+}
+
+func (*RevokeManufacturerAttester) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ManufacturerChunks}
+}
+
+func (*RevokeManufacturerAttester) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RevokeManufacturerAttester) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	manufacturer, output := getOwnedManufacturer(ctx, mu, r.Manufacturer, auth.Actor())
+	if output != nil {
+		return false, ManageManufacturerComputeUnits, output, nil, nil
+	}
+	if !manufacturer.IsAttester(r.Attester) {
+		return false, ManageManufacturerComputeUnits, OutputAttesterNotFound, nil, nil
+	}
+	attesters := make([]codec.Address, 0, len(manufacturer.Attesters)-1)
+	for _, attester := range manufacturer.Attesters {
+		if attester != r.Attester {
+			attesters = append(attesters, attester)
+		}
+	}
+	manufacturer.Attesters = attesters
+	if err := storage.SetManufacturer(ctx, mu, manufacturer); err != nil {
+		return false, ManageManufacturerComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageManufacturerComputeUnits, nil, nil, nil
+}
+
+func (*RevokeManufacturerAttester) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageManufacturerComputeUnits
+}
+
+func (r *RevokeManufacturerAttester) Size() int {
+	return codec.BytesLen(r.Manufacturer) + codec.AddressLen
+}
+
+func (r *RevokeManufacturerAttester) Marshal(p *codec.Packer) {
+	p.PackBytes(r.Manufacturer)
+	p.PackAddress(r.Attester)
+}
+
+func UnmarshalRevokeManufacturerAttester(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var revoke RevokeManufacturerAttester
+	p.UnpackBytes(MachineManufacturerUnits, true, &revoke.Manufacturer)
+	p.UnpackAddress(&revoke.Attester)
+	return &revoke, p.Err()
+}
+
+func (*RevokeManufacturerAttester) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
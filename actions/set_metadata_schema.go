@@ -0,0 +1,122 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*SetMetadataSchema)(nil)
+
+// SetMetadataSchema registers the metadata schema of machines of [Category].
+// The first account to set the schema of a category owns it and is the only
+// one that can replace it. Machines attested before a schema change keep the
+// metadata they were attested with.
+type SetMetadataSchema struct {
+	Category []byte                  `json:"category"`
+	Fields   []storage.MetadataField `json:"fields"`
+}
+
+func (*SetMetadataSchema) GetTypeID() uint8 {
+	return setMetadataSchemaID
+}
+
+func (s *SetMetadataSchema) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.MetadataSchemaKey(s.Category)),
+	}
+}
+
+func (*SetMetadataSchema) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.MetadataSchemaChunks}
+}
+
+func (*SetMetadataSchema) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SetMetadataSchema) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(s.Category) == 0 || len(s.Category) > MachineCategoryUnits {
+		return false, SetMetadataSchemaComputeUnits, OutputInvalidMachineCategoryLen, nil, nil
+	}
+	if err := storage.ValidMetadataFields(s.Fields); err != nil {
+		return false, SetMetadataSchemaComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	exists, schema, err := storage.GetMetadataSchema(ctx, mu, s.Category)
+	if err != nil {
+		return false, SetMetadataSchemaComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if exists && schema.Owner != auth.Actor() {
+		return false, SetMetadataSchemaComputeUnits, OutputNotSchemaOwner, nil, nil
+	}
+	if err := storage.SetMetadataSchema(ctx, mu, storage.MetadataSchemaData{
+		Category: s.Category,
+		Owner:    auth.Actor(),
+		Fields:   s.Fields,
+	}); err != nil {
+		return false, SetMetadataSchemaComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, SetMetadataSchemaComputeUnits, nil, nil, nil
+}
+
+func (*SetMetadataSchema) MaxComputeUnits(chain.Rules) uint64 {
+	return SetMetadataSchemaComputeUnits
+}
+
+func (s *SetMetadataSchema) Size() int {
+	size := codec.BytesLen(s.Category) + consts.IntLen
+	for _, f := range s.Fields {
+		size += codec.StringLen(f.Name) + consts.ByteLen + consts.BoolLen
+	}
+	return size
+}
+
+func (s *SetMetadataSchema) Marshal(p *codec.Packer) {
+	p.PackBytes(s.Category)
+	p.PackInt(len(s.Fields))
+	for _, f := range s.Fields {
+		p.PackString(f.Name)
+		p.PackByte(byte(f.Type))
+		p.PackBool(f.Required)
+	}
+}
+
+func UnmarshalSetMetadataSchema(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var set SetMetadataSchema
+	p.UnpackBytes(MachineCategoryUnits, true, &set.Category)
+	count := p.UnpackInt(true)
+	if count > storage.MaxMetadataFields {
+		return nil, storage.ErrInvalidMetadataSchema
+	}
+	set.Fields = make([]storage.MetadataField, count)
+	for i := range set.Fields {
+		set.Fields[i].Name = p.UnpackString(true)
+		set.Fields[i].Type = storage.MetadataType(p.UnpackByte())
+		set.Fields[i].Required = p.UnpackBool()
+	}
+	return &set, p.Err()
+}
+
+func (*SetMetadataSchema) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*SetProjectReleaseKey)(nil)
+
+type SetProjectReleaseKey struct {
+	// [Project] is the txID of the [CreateProject] to set the release key of.
+	// Only the owner can set or rotate it.
+	Project ids.ID `json:"project"`
+
+	// [ReleaseKey] must sign the manifest of every update published for the
+	// project afterwards.
+	ReleaseKey ed25519.PublicKey `json:"release_key"`
+}
+
+func (*SetProjectReleaseKey) GetTypeID() uint8 {
+	return setProjectReleaseKeyID
+}
+
+func (s *SetProjectReleaseKey) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ProjectKey(s.Project)),
+		string(storage.ProjectReleaseKeyKey(s.Project)),
+	}
+}
+
+func (*SetProjectReleaseKey) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProjectDescriptionChunks, storage.ProjectReleaseKeyChunks}
+}
+
+func (*SetProjectReleaseKey) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SetProjectReleaseKey) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if s.ReleaseKey == ed25519.EmptyPublicKey {
+		return false, ManageProjectComputeUnits, OutputReleaseKeyNotProvided, nil, nil
+	}
+	exists, _, owner, err := getProjectOwner(ctx, mu, s.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ManageProjectComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if owner != auth.Actor() {
+		return false, ManageProjectComputeUnits, OutputNotProjectOwner, nil, nil
+	}
+	if err := storage.SetProjectReleaseKey(ctx, mu, s.Project, s.ReleaseKey); err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageProjectComputeUnits, nil, nil, nil
+}
+
+func (*SetProjectReleaseKey) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageProjectComputeUnits
+}
+
+func (*SetProjectReleaseKey) Size() int {
+	return consts.IDLen + ed25519.PublicKeyLen
+}
+
+func (s *SetProjectReleaseKey) Marshal(p *codec.Packer) {
+	p.PackID(s.Project)
+	p.PackFixedBytes(s.ReleaseKey[:])
+}
+
+func UnmarshalSetProjectReleaseKey(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var set SetProjectReleaseKey
+	p.UnpackID(true, &set.Project)
+	key := set.ReleaseKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &key)
+	return &set, p.Err()
+}
+
+func (*SetProjectReleaseKey) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
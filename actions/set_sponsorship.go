@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*SetSponsorship)(nil)
+
+type SetSponsorship struct {
+	// [MachineAttestTx] is the txID of the [AttestMachine] record of the
+	// machine to pay fees for. Only the attester that created it can sponsor
+	// it.
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+
+	// [Cap] is the most the attester will ever pay in fees for the machine,
+	// including what it has already paid. Lowering it below what was spent
+	// stops the sponsorship.
+	Cap uint64 `json:"cap"`
+}
+
+func (*SetSponsorship) GetTypeID() uint8 {
+	return setSponsorshipID
+}
+
+func (s *SetSponsorship) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.AttestMachineKey(s.MachineAttestTx)),
+		string(storage.SponsorshipKey(s.MachineAttestTx)),
+	}
+}
+
+func (*SetSponsorship) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.MachineCIDChunks, storage.SponsorshipChunks}
+}
+
+func (*SetSponsorship) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SetSponsorship) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	actor := auth.Actor()
+	if _, output := getAttestedMachine(ctx, mu, s.MachineAttestTx, actor); output != nil {
+		return false, SetSponsorshipComputeUnits, output, nil, nil
+	}
+	exists, sponsorship, err := storage.GetSponsorship(ctx, mu, s.MachineAttestTx)
+	if err != nil {
+		return false, SetSponsorshipComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	// What a previous attester paid doesn't count against the new one.
+	if !exists || sponsorship.Sponsor != actor {
+		sponsorship = storage.SponsorshipData{Sponsor: actor}
+	}
+	sponsorship.Cap = s.Cap
+	if err := storage.SetSponsorship(ctx, mu, s.MachineAttestTx, sponsorship); err != nil {
+		return false, SetSponsorshipComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, SetSponsorshipComputeUnits, nil, nil, nil
+}
+
+func (*SetSponsorship) MaxComputeUnits(chain.Rules) uint64 {
+	return SetSponsorshipComputeUnits
+}
+
+func (*SetSponsorship) Size() int {
+	return consts.IDLen + consts.Uint64Len
+}
+
+func (s *SetSponsorship) Marshal(p *codec.Packer) {
+	p.PackID(s.MachineAttestTx)
+	p.PackUint64(s.Cap)
+}
+
+func UnmarshalSetSponsorship(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var s SetSponsorship
+	p.UnpackID(true, &s.MachineAttestTx)
+	s.Cap = p.UnpackUint64(false)
+	return &s, p.Err()
+}
+
+func (*SetSponsorship) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	tconsts "dataverse/consts"
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*TransferProjectOwnership)(nil)
+
+type TransferProjectOwnership struct {
+	// [Project] is the txID of the [CreateProject] to hand over. Only the
+	// current owner can transfer it.
+	Project  ids.ID        `json:"project"`
+	NewOwner codec.Address `json:"new_owner"`
+}
+
+func (*TransferProjectOwnership) GetTypeID() uint8 {
+	return transferProjectOwnershipID
+}
+
+func (t *TransferProjectOwnership) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ProjectKey(t.Project)),
+	}
+}
+
+func (*TransferProjectOwnership) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProjectDescriptionChunks}
+}
+
+func (*TransferProjectOwnership) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *TransferProjectOwnership) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, project, owner, err := getProjectOwner(ctx, mu, t.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ManageProjectComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if owner != auth.Actor() {
+		return false, ManageProjectComputeUnits, OutputNotProjectOwner, nil, nil
+	}
+
+	newOwner, err := codec.AddressBech32(tconsts.HRP, t.NewOwner)
+	if err != nil {
+		return false, ManageProjectComputeUnits, OutputProjectInvalidOwner, nil, nil
+	}
+	if err := storage.SetProject(ctx, mu, t.Project, project.ProjectName, project.ProjectDescription, []byte(newOwner), project.Logo); err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageProjectComputeUnits, nil, nil, nil
+}
+
+func (*TransferProjectOwnership) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageProjectComputeUnits
+}
+
+func (*TransferProjectOwnership) Size() int {
+	return consts.IDLen + codec.AddressLen
+}
+
+func (t *TransferProjectOwnership) Marshal(p *codec.Packer) {
+	p.PackID(t.Project)
+	p.PackAddress(t.NewOwner)
+}
+
+func UnmarshalTransferProjectOwnership(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var transfer TransferProjectOwnership
+	p.UnpackID(true, &transfer.Project)
+	p.UnpackAddress(&transfer.NewOwner)
+	return &transfer, p.Err()
+}
+
+func (*TransferProjectOwnership) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
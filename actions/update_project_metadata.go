@@ -0,0 +1,108 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*UpdateProjectMetadata)(nil)
+
+type UpdateProjectMetadata struct {
+	// [Project] is the txID of the [CreateProject] to modify. The owner and
+	// the maintainers can update the description and logo.
+	Project            ids.ID `json:"project"`
+	ProjectDescription []byte `json:"description"`
+	Logo               []byte `json:"url"`
+}
+
+func (*UpdateProjectMetadata) GetTypeID() uint8 {
+	return updateProjectMetadataID
+}
+
+func (u *UpdateProjectMetadata) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.ProjectKey(u.Project)),
+		string(storage.ProjectMaintainersKey(u.Project)),
+	}
+}
+
+func (*UpdateProjectMetadata) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProjectDescriptionChunks, storage.ProjectMaintainersChunks}
+}
+
+func (*UpdateProjectMetadata) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateProjectMetadata) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(u.ProjectDescription) == 0 {
+		return false, ManageProjectComputeUnits, OutputProjectDescriptionNotGiven, nil, nil
+	}
+
+	exists, authorized, err := canPublishUpdates(ctx, mu, u.Project, auth.Actor())
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, ManageProjectComputeUnits, OutputProjectNotFound, nil, nil
+	}
+	if !authorized {
+		return false, ManageProjectComputeUnits, OutputNotProjectMaintainer, nil, nil
+	}
+
+	_, project, err := storage.GetProject(ctx, mu, u.Project)
+	if err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetProject(ctx, mu, u.Project, project.ProjectName, u.ProjectDescription, project.ProjectOwner, u.Logo); err != nil {
+		return false, ManageProjectComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ManageProjectComputeUnits, nil, nil, nil
+}
+
+func (*UpdateProjectMetadata) MaxComputeUnits(chain.Rules) uint64 {
+	return ManageProjectComputeUnits
+}
+
+func (u *UpdateProjectMetadata) Size() int {
+	return consts.IDLen + codec.BytesLen(u.ProjectDescription) + codec.BytesLen(u.Logo)
+}
+
+func (u *UpdateProjectMetadata) Marshal(p *codec.Packer) {
+	p.PackID(u.Project)
+	p.PackBytes(u.ProjectDescription)
+	p.PackBytes(u.Logo)
+}
+
+func UnmarshalUpdateProjectMetadata(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var update UpdateProjectMetadata
+	p.UnpackID(true, &update.Project)
+	p.UnpackBytes(ProjectDescriptionUnits, true, &update.ProjectDescription)
+	p.UnpackBytes(ProjectLogoUnits, false, &update.Logo)
+	return &update, p.Err()
+}
+
+func (*UpdateProjectMetadata) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*WithdrawLicenseOffer)(nil)
+
+type WithdrawLicenseOffer struct {
+	// [Offer] is the txID of the [OfferLicense] to close. Licenses already
+	// bought from it run until they expire.
+	Offer ids.ID `json:"offer"`
+}
+
+func (*WithdrawLicenseOffer) GetTypeID() uint8 {
+	return withdrawLicenseOfferID
+}
+
+func (w *WithdrawLicenseOffer) StateKeys(chain.Auth, ids.ID) []string {
+	return []string{
+		string(storage.LicenseOfferKey(w.Offer)),
+	}
+}
+
+func (*WithdrawLicenseOffer) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.LicenseOfferChunks}
+}
+
+func (*WithdrawLicenseOffer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (w *WithdrawLicenseOffer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	auth chain.Auth,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, offer, err := storage.GetLicenseOffer(ctx, mu, w.Offer)
+	if err != nil {
+		return false, LicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		return false, LicenseComputeUnits, OutputLicenseOfferNotFound, nil, nil
+	}
+	if offer.Licensor != auth.Actor() {
+		return false, LicenseComputeUnits, OutputWrongOwner, nil, nil
+	}
+	if offer.Closed {
+		return false, LicenseComputeUnits, OutputLicenseOfferClosed, nil, nil
+	}
+	offer.Closed = true
+	if err := storage.SetLicenseOffer(ctx, mu, w.Offer, offer); err != nil {
+		return false, LicenseComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, LicenseComputeUnits, nil, nil, nil
+}
+
+func (*WithdrawLicenseOffer) MaxComputeUnits(chain.Rules) uint64 {
+	return LicenseComputeUnits
+}
+
+func (*WithdrawLicenseOffer) Size() int {
+	return consts.IDLen
+}
+
+func (w *WithdrawLicenseOffer) Marshal(p *codec.Packer) {
+	p.PackID(w.Offer)
+}
+
+func UnmarshalWithdrawLicenseOffer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var withdraw WithdrawLicenseOffer
+	p.UnpackID(true, &withdraw.Offer)
+	return &withdraw, p.Err()
+}
+
+func (*WithdrawLicenseOffer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -7,11 +7,15 @@ import "github.com/ava-labs/hypersdk/vm"
 
 // Note: Registry will error during initialization if a duplicate ID is assigned. We explicitly assign IDs to avoid accidental remapping.
 const (
-	ed25519ID uint8 = 0
+	ed25519ID   uint8 = 0
+	secp256r1ID uint8 = 1
+	sponsoredID uint8 = 2
 )
 
 func Engines() map[uint8]vm.AuthEngine {
 	return map[uint8]vm.AuthEngine{
-		ed25519ID: &ED25519AuthEngine{},
+		ed25519ID:   &ED25519AuthEngine{},
+		secp256r1ID: &SECP256R1AuthEngine{},
+		sponsoredID: &SponsoredAuthEngine{},
 	}
 }
@@ -5,4 +5,15 @@ package auth
 
 import "errors"
 
-var ErrInvalidSignature = errors.New("invalid signature")
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrUnknownKeyType   = errors.New("unknown key type")
+	ErrInvalidKeyLength = errors.New("invalid key length")
+
+	ErrMachineNotAttested     = errors.New("machine not attested")
+	ErrNotAttestedMachine     = errors.New("signer is not the attested machine")
+	ErrNotMachineSponsor      = errors.New("sponsor is not the machine attester")
+	ErrMachineNotActive       = errors.New("machine is not active")
+	ErrNotSponsored           = errors.New("machine is not sponsored by the operator")
+	ErrSponsorshipCapExceeded = errors.New("sponsorship cap exceeded")
+)
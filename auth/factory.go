@@ -0,0 +1,35 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+// NewFactory returns the factory that signs for [addr] with [priv], picking
+// the signature scheme from the type of the address.
+func NewFactory(addr codec.Address, priv []byte) (chain.AuthFactory, error) {
+	switch addr[0] {
+	case ed25519ID:
+		if len(priv) != ed25519.PrivateKeyLen {
+			return nil, ErrInvalidKeyLength
+		}
+		return NewED25519Factory(ed25519.PrivateKey(priv)), nil
+	case secp256r1ID:
+		if len(priv) != secp256r1.PrivateKeyLen {
+			return nil, ErrInvalidKeyLength
+		}
+		return NewSECP256R1Factory(secp256r1.PrivateKey(priv)), nil
+	default:
+		return nil, ErrUnknownKeyType
+	}
+}
+
+// IsED25519Address returns true if [addr] is derived from an ed25519 key.
+func IsED25519Address(addr codec.Address) bool {
+	return addr[0] == ed25519ID
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+// Machine keys are identified by the auth type that signs with them, so a
+// machine key derives the same address it authorizes transactions from.
+const (
+	ED25519Key   = ed25519ID
+	SECP256R1Key = secp256r1ID
+
+	// MaxMachineKeyLen and MaxMachineSignatureLen bound the machine keys
+	// and signatures of any key type.
+	MaxMachineKeyLen       = secp256r1.PublicKeyLen
+	MaxMachineSignatureLen = secp256r1.SignatureLen
+)
+
+// VerifyMachineKey checks that [sig] is a signature of [msg] by the public
+// key [key] of type [typ] and returns the address of the key.
+func VerifyMachineKey(typ uint8, key []byte, msg []byte, sig []byte) (codec.Address, error) {
+	switch typ {
+	case ED25519Key:
+		if len(key) != ed25519.PublicKeyLen || len(sig) != ed25519.SignatureLen {
+			return codec.EmptyAddress, ErrInvalidKeyLength
+		}
+		pk := ed25519.PublicKey(key)
+		if !ed25519.Verify(msg, pk, ed25519.Signature(sig)) {
+			return codec.EmptyAddress, crypto.ErrInvalidSignature
+		}
+		return NewED25519Address(pk), nil
+	case SECP256R1Key:
+		if len(key) != secp256r1.PublicKeyLen || len(sig) != secp256r1.SignatureLen {
+			return codec.EmptyAddress, ErrInvalidKeyLength
+		}
+		pk := secp256r1.PublicKey(key)
+		if !secp256r1.Verify(msg, pk, secp256r1.Signature(sig)) {
+			return codec.EmptyAddress, crypto.ErrInvalidSignature
+		}
+		return NewSECP256R1Address(pk), nil
+	default:
+		return codec.EmptyAddress, ErrUnknownKeyType
+	}
+}
+
+// MachineKeyType returns the type of the public key [key], the key types
+// having public keys of different lengths.
+func MachineKeyType(key []byte) (uint8, error) {
+	switch len(key) {
+	case ed25519.PublicKeyLen:
+		return ED25519Key, nil
+	case secp256r1.PublicKeyLen:
+		return SECP256R1Key, nil
+	default:
+		return 0, ErrInvalidKeyLength
+	}
+}
+
+// MachineKeyAddress returns the address of the public key [key] of type
+// [typ], without checking that anyone holds it.
+func MachineKeyAddress(typ uint8, key []byte) (codec.Address, error) {
+	switch typ {
+	case ED25519Key:
+		if len(key) != ed25519.PublicKeyLen {
+			return codec.EmptyAddress, ErrInvalidKeyLength
+		}
+		return NewED25519Address(ed25519.PublicKey(key)), nil
+	case SECP256R1Key:
+		if len(key) != secp256r1.PublicKeyLen {
+			return codec.EmptyAddress, ErrInvalidKeyLength
+		}
+		return NewSECP256R1Address(secp256r1.PublicKey(key)), nil
+	default:
+		return codec.EmptyAddress, ErrUnknownKeyType
+	}
+}
+
+// SignMachineKey signs [msg] with the private key [priv] of type [typ] and
+// returns the public key and the signature.
+func SignMachineKey(typ uint8, priv []byte, msg []byte) ([]byte, []byte, error) {
+	switch typ {
+	case ED25519Key:
+		if len(priv) != ed25519.PrivateKeyLen {
+			return nil, nil, ErrInvalidKeyLength
+		}
+		pk := ed25519.PrivateKey(priv)
+		pub := pk.PublicKey()
+		sig := ed25519.Sign(msg, pk)
+		return pub[:], sig[:], nil
+	case SECP256R1Key:
+		if len(priv) != secp256r1.PrivateKeyLen {
+			return nil, nil, ErrInvalidKeyLength
+		}
+		pk := secp256r1.PrivateKey(priv)
+		sig, err := secp256r1.Sign(msg, pk)
+		if err != nil {
+			return nil, nil, err
+		}
+		pub := pk.PublicKey()
+		return pub[:], sig[:], nil
+	default:
+		return nil, nil, ErrUnknownKeyType
+	}
+}
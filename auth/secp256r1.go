@@ -0,0 +1,236 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Auth = (*SECP256R1)(nil)
+
+const (
+	SECP256R1ComputeUnits = 10 // can't be batched like ed25519
+	SECP256R1Size         = secp256r1.PublicKeyLen + secp256r1.SignatureLen
+
+	// secp256r1MinBatchSize is the fewest signatures a single verification
+	// job is given, so small blocks don't spawn a job per transaction.
+	secp256r1MinBatchSize = 16
+)
+
+// SECP256R1 authorizes transactions signed by a secp256r1 (P-256) key, the
+// curve secure elements and TPMs on devices can sign with.
+type SECP256R1 struct {
+	Signer    secp256r1.PublicKey `json:"signer"`
+	Signature secp256r1.Signature `json:"signature"`
+
+	addr codec.Address
+}
+
+func (d *SECP256R1) address() codec.Address {
+	if d.addr == codec.EmptyAddress {
+		d.addr = NewSECP256R1Address(d.Signer)
+	}
+	return d.addr
+}
+
+func (*SECP256R1) GetTypeID() uint8 {
+	return secp256r1ID
+}
+
+func (*SECP256R1) MaxComputeUnits(chain.Rules) uint64 {
+	return SECP256R1ComputeUnits
+}
+
+func (*SECP256R1) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (d *SECP256R1) StateKeys() []string {
+	return []string{
+		// We always pay fees with the native asset (which is [ids.Empty])
+		string(storage.BalanceKey(d.address(), ids.Empty)),
+	}
+}
+
+func (d *SECP256R1) AsyncVerify(msg []byte) error {
+	if !secp256r1.Verify(msg, d.Signer, d.Signature) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (d *SECP256R1) Verify(
+	_ context.Context,
+	r chain.Rules,
+	_ state.Immutable,
+	_ chain.Action,
+) (uint64, error) {
+	// We don't do anything during verify (there is no additional state to check
+	// to authorize the signer other than verifying the signature)
+	return d.MaxComputeUnits(r), nil
+}
+
+func (d *SECP256R1) Actor() codec.Address {
+	return d.address()
+}
+
+func (d *SECP256R1) Sponsor() codec.Address {
+	return d.address()
+}
+
+func (*SECP256R1) Size() int {
+	return SECP256R1Size
+}
+
+func (d *SECP256R1) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(d.Signer[:])
+	p.PackFixedBytes(d.Signature[:])
+}
+
+func UnmarshalSECP256R1(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var d SECP256R1
+	signer := d.Signer[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(secp256r1.PublicKeyLen, &signer)
+	signature := d.Signature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(secp256r1.SignatureLen, &signature)
+	return &d, p.Err()
+}
+
+func (d *SECP256R1) CanDeduct(
+	ctx context.Context,
+	im state.Immutable,
+	amount uint64,
+) error {
+	bal, err := storage.GetBalance(ctx, im, d.address(), ids.Empty)
+	if err != nil {
+		return err
+	}
+	if bal < amount {
+		return storage.ErrInvalidBalance
+	}
+	return nil
+}
+
+func (d *SECP256R1) Deduct(
+	ctx context.Context,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	return storage.SubBalance(ctx, mu, d.address(), ids.Empty, amount)
+}
+
+func (d *SECP256R1) Refund(
+	ctx context.Context,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	// Don't create account if it doesn't exist (may have sent all funds).
+	return storage.AddBalance(ctx, mu, d.address(), ids.Empty, amount, false)
+}
+
+var _ chain.AuthFactory = (*SECP256R1Factory)(nil)
+
+func NewSECP256R1Factory(priv secp256r1.PrivateKey) *SECP256R1Factory {
+	return &SECP256R1Factory{priv}
+}
+
+type SECP256R1Factory struct {
+	priv secp256r1.PrivateKey
+}
+
+func (d *SECP256R1Factory) Sign(msg []byte, _ chain.Action) (chain.Auth, error) {
+	sig, err := secp256r1.Sign(msg, d.priv)
+	if err != nil {
+		return nil, err
+	}
+	return &SECP256R1{Signer: d.priv.PublicKey(), Signature: sig}, nil
+}
+
+func (*SECP256R1Factory) MaxUnits() (uint64, uint64, []uint16) {
+	return SECP256R1Size, SECP256R1ComputeUnits, []uint16{storage.BalanceChunks}
+}
+
+type SECP256R1AuthEngine struct{}
+
+func (*SECP256R1AuthEngine) GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier {
+	batchSize := math.Max(count/cores, secp256r1MinBatchSize)
+	return &SECP256R1Batch{
+		batchSize: batchSize,
+		total:     count,
+	}
+}
+
+func (*SECP256R1AuthEngine) Cache(chain.Auth) {
+	// secp256r1 public keys are decompressed on every verification, there is
+	// nothing to cache.
+}
+
+type secp256r1Item struct {
+	msg  []byte
+	auth *SECP256R1
+}
+
+// SECP256R1Batch splits signatures into jobs of [batchSize] that are each
+// verified one after another. secp256r1 has no batch verification, so this
+// only amortizes scheduling across cores.
+type SECP256R1Batch struct {
+	batchSize int
+	total     int
+
+	totalCounter int
+	batch        []secp256r1Item
+}
+
+func (b *SECP256R1Batch) Add(msg []byte, rauth chain.Auth) func() error {
+	auth := rauth.(*SECP256R1)
+	if b.batch == nil {
+		b.batch = make([]secp256r1Item, 0, b.batchSize)
+	}
+	b.batch = append(b.batch, secp256r1Item{msg, auth})
+	b.totalCounter++
+	if len(b.batch) == b.batchSize {
+		last := b.batch
+		b.batch = nil
+		if b.totalCounter < b.total {
+			// don't create a new batch if we are done
+			b.batch = make([]secp256r1Item, 0, b.batchSize)
+		}
+		return verifySECP256R1Batch(last)
+	}
+	return nil
+}
+
+func (b *SECP256R1Batch) Done() []func() error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+	return []func() error{verifySECP256R1Batch(b.batch)}
+}
+
+func verifySECP256R1Batch(batch []secp256r1Item) func() error {
+	return func() error {
+		for _, item := range batch {
+			if err := item.auth.AsyncVerify(item.msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func NewSECP256R1Address(pk secp256r1.PublicKey) codec.Address {
+	return codec.CreateAddress(secp256r1ID, utils.ToID(pk[:]))
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+func newSECP256R1Auth(t *testing.T, msg []byte) *SECP256R1 {
+	t.Helper()
+	priv, err := secp256r1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewSECP256R1Factory(priv).Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.(*SECP256R1)
+}
+
+func TestSECP256R1Verify(t *testing.T) {
+	msg := []byte("tx")
+	auth := newSECP256R1Auth(t, msg)
+	if err := auth.AsyncVerify(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.AsyncVerify([]byte("other tx")); err == nil {
+		t.Fatal("signature verified another message")
+	}
+	if auth.Actor() != NewSECP256R1Address(auth.Signer) || auth.Sponsor() != auth.Actor() {
+		t.Fatal("unexpected actor")
+	}
+
+	p := codec.NewWriter(auth.Size(), auth.Size())
+	auth.Marshal(p)
+	parsed, err := UnmarshalSECP256R1(codec.NewReader(p.Bytes(), len(p.Bytes())), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.(*SECP256R1).AsyncVerify(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Machine keys are verified the same way
+	addr, err := VerifyMachineKey(SECP256R1Key, auth.Signer[:], msg, auth.Signature[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != auth.Actor() {
+		t.Fatal("machine key derived another address")
+	}
+}
+
+func TestSECP256R1Malformed(t *testing.T) {
+	msg := []byte("tx")
+	auth := newSECP256R1Auth(t, msg)
+
+	corrupted := *auth
+	corrupted.Signature[10] ^= 0x1
+	if err := corrupted.AsyncVerify(msg); err == nil {
+		t.Fatal("corrupted signature verified")
+	}
+
+	// Not a point on the curve
+	invalidKey := *auth
+	invalidKey.Signer = secp256r1.PublicKey{}
+	invalidKey.Signer[0] = 0x2
+	for i := 1; i < secp256r1.PublicKeyLen; i++ {
+		invalidKey.Signer[i] = 0xff
+	}
+	if err := invalidKey.AsyncVerify(msg); err == nil {
+		t.Fatal("signature verified with an invalid key")
+	}
+
+	zero := *auth
+	zero.Signature = secp256r1.Signature{}
+	if err := zero.AsyncVerify(msg); err == nil {
+		t.Fatal("zero signature verified")
+	}
+
+	p := codec.NewWriter(auth.Size(), auth.Size())
+	auth.Marshal(p)
+	truncated := p.Bytes()[:auth.Size()-1]
+	if _, err := UnmarshalSECP256R1(codec.NewReader(truncated, len(truncated)), nil); err == nil {
+		t.Fatal("truncated auth unmarshaled")
+	}
+
+	if _, err := VerifyMachineKey(SECP256R1Key, auth.Signer[:1], msg, auth.Signature[:]); err != ErrInvalidKeyLength {
+		t.Fatalf("expected %v, got %v", ErrInvalidKeyLength, err)
+	}
+}
+
+func TestSECP256R1HighS(t *testing.T) {
+	msg := []byte("tx")
+	auth := newSECP256R1Auth(t, msg)
+
+	// (r, n-s) is an equally valid ECDSA signature, only the low-S form is
+	// accepted so signatures can't be altered
+	n := elliptic.P256().Params().N
+	s := new(big.Int).SetBytes(auth.Signature[32:])
+	high := *auth
+	new(big.Int).Sub(n, s).FillBytes(high.Signature[32:])
+	if err := high.AsyncVerify(msg); err == nil {
+		t.Fatal("high-S signature verified")
+	}
+	if _, err := VerifyMachineKey(SECP256R1Key, high.Signer[:], msg, high.Signature[:]); err == nil {
+		t.Fatal("high-S machine key signature verified")
+	}
+}
+
+func TestSECP256R1BatchChunking(t *testing.T) {
+	msg := []byte("tx")
+	auths := make([]*SECP256R1, 40)
+	for i := range auths {
+		auths[i] = newSECP256R1Auth(t, msg)
+	}
+
+	for _, tt := range []struct {
+		cores int
+		count int
+		jobs  int  // full jobs returned by Add
+		rest  bool // whether Done returns a job for the remaining signatures
+	}{
+		// Small blocks are verified in jobs of at least secp256r1MinBatchSize
+		{cores: 4, count: 20, jobs: 1, rest: true},
+		{cores: 8, count: 10, rest: true},
+		// Larger ones are split evenly across cores
+		{cores: 2, count: 40, jobs: 2},
+		{cores: 2, count: 39, jobs: 2, rest: true},
+	} {
+		engine := &SECP256R1AuthEngine{}
+		verifier := engine.GetBatchVerifier(tt.cores, tt.count)
+		var jobs []func() error
+		for i := 0; i < tt.count; i++ {
+			if job := verifier.Add(msg, auths[i]); job != nil {
+				jobs = append(jobs, job)
+			}
+		}
+		if len(jobs) != tt.jobs {
+			t.Fatalf("%d cores, %d signatures: expected %d jobs, got %d", tt.cores, tt.count, tt.jobs, len(jobs))
+		}
+		done := verifier.Done()
+		if tt.rest != (len(done) == 1) || len(done) > 1 {
+			t.Fatalf("%d cores, %d signatures: unexpected %d final jobs", tt.cores, tt.count, len(done))
+		}
+		for _, job := range append(jobs, done...) {
+			if err := job(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := verifier.(*SECP256R1Batch).totalCounter; got != tt.count {
+			t.Fatalf("%d cores, %d signatures: %d were added", tt.cores, tt.count, got)
+		}
+	}
+
+	// A bad signature only fails the job it is in
+	verifier := (&SECP256R1AuthEngine{}).GetBatchVerifier(2, 40)
+	bad := *auths[25]
+	bad.Signature[0] ^= 0x1
+	var jobs []func() error
+	for i, auth := range auths {
+		if i == 25 {
+			auth = &bad
+		}
+		if job := verifier.Add(msg, auth); job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	if err := jobs[0](); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs[1](); err == nil {
+		t.Fatal("job with a bad signature verified")
+	}
+}
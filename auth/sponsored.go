@@ -0,0 +1,286 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Auth = (*Sponsored)(nil)
+
+const (
+	// SponsoredComputeUnits is charged on top of the machine signature for
+	// reading the attestation and the sponsorship.
+	SponsoredComputeUnits = 5
+	sponsoredHeaderSize   = codec.AddressLen + consts.IDLen + consts.ByteLen
+)
+
+// Sponsored authorizes a transaction signed by an attested machine whose fees
+// are paid by [Operator], the attester of the machine, instead of the machine
+// itself. The sponsor caps how much it pays for each machine with a
+// [storage.SponsorshipData] record.
+//
+// The machine signs the transaction digest followed by [Operator] and
+// [MachineAttestTx], so the signature can't be reused to charge someone else.
+type Sponsored struct {
+	Operator        codec.Address `json:"operator"`
+	MachineAttestTx ids.ID        `json:"machine_attest_tx"`
+
+	// [Machine] is the ED25519 or SECP256R1 signature of the machine.
+	Machine chain.Auth `json:"machine"`
+}
+
+func (*Sponsored) GetTypeID() uint8 {
+	return sponsoredID
+}
+
+func (d *Sponsored) MaxComputeUnits(r chain.Rules) uint64 {
+	return d.Machine.MaxComputeUnits(r) + SponsoredComputeUnits
+}
+
+func (*Sponsored) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (d *Sponsored) StateKeys() []string {
+	return []string{
+		// The sponsor pays fees with the native asset (which is [ids.Empty])
+		string(storage.BalanceKey(d.Operator, ids.Empty)),
+		string(storage.SponsorshipKey(d.MachineAttestTx)),
+		string(storage.AttestMachineKey(d.MachineAttestTx)),
+	}
+}
+
+func (d *Sponsored) AsyncVerify(msg []byte) error {
+	return d.Machine.AsyncVerify(sponsoredMessage(msg, d.Operator, d.MachineAttestTx))
+}
+
+func (d *Sponsored) Verify(
+	ctx context.Context,
+	r chain.Rules,
+	im state.Immutable,
+	_ chain.Action,
+) (uint64, error) {
+	exists, machine, err := storage.GetAttestMachine(ctx, im, d.MachineAttestTx)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrMachineNotAttested
+	}
+	if machine.MachineAddress != d.Machine.Actor() {
+		return 0, ErrNotAttestedMachine
+	}
+	if machine.Attester != d.Operator {
+		return 0, ErrNotMachineSponsor
+	}
+	if machine.Status != storage.MachineActive {
+		return 0, ErrMachineNotActive
+	}
+	exists, sponsorship, err := storage.GetSponsorship(ctx, im, d.MachineAttestTx)
+	if err != nil {
+		return 0, err
+	}
+	// The machine may have been re-attested by someone else since the
+	// sponsorship was set up.
+	if !exists || sponsorship.Sponsor != d.Operator {
+		return 0, ErrNotSponsored
+	}
+	return d.MaxComputeUnits(r), nil
+}
+
+func (d *Sponsored) Actor() codec.Address {
+	return d.Machine.Actor()
+}
+
+func (d *Sponsored) Sponsor() codec.Address {
+	return d.Operator
+}
+
+func (d *Sponsored) Size() int {
+	return sponsoredHeaderSize + d.Machine.Size()
+}
+
+func (d *Sponsored) Marshal(p *codec.Packer) {
+	p.PackAddress(d.Operator)
+	p.PackID(d.MachineAttestTx)
+	p.PackByte(d.Machine.GetTypeID())
+	d.Machine.Marshal(p)
+}
+
+func UnmarshalSponsored(p *codec.Packer, wm *warp.Message) (chain.Auth, error) {
+	var d Sponsored
+	p.UnpackAddress(&d.Operator)
+	p.UnpackID(true, &d.MachineAttestTx)
+	var err error
+	switch p.UnpackByte() {
+	case ed25519ID:
+		d.Machine, err = UnmarshalED25519(p, wm)
+	case secp256r1ID:
+		d.Machine, err = UnmarshalSECP256R1(p, wm)
+	default:
+		if p.Err() != nil {
+			return nil, p.Err()
+		}
+		return nil, ErrUnknownKeyType
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, p.Err()
+}
+
+func (d *Sponsored) CanDeduct(
+	ctx context.Context,
+	im state.Immutable,
+	amount uint64,
+) error {
+	_, sponsorship, err := storage.GetSponsorship(ctx, im, d.MachineAttestTx)
+	if err != nil {
+		return err
+	}
+	if sponsorship.Remaining() < amount {
+		return ErrSponsorshipCapExceeded
+	}
+	bal, err := storage.GetBalance(ctx, im, d.Operator, ids.Empty)
+	if err != nil {
+		return err
+	}
+	if bal < amount {
+		return storage.ErrInvalidBalance
+	}
+	return nil
+}
+
+func (d *Sponsored) Deduct(
+	ctx context.Context,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	_, sponsorship, err := storage.GetSponsorship(ctx, mu, d.MachineAttestTx)
+	if err != nil {
+		return err
+	}
+	if sponsorship.Remaining() < amount {
+		return ErrSponsorshipCapExceeded
+	}
+	sponsorship.Spent += amount
+	if err := storage.SetSponsorship(ctx, mu, d.MachineAttestTx, sponsorship); err != nil {
+		return err
+	}
+	return storage.SubBalance(ctx, mu, d.Operator, ids.Empty, amount)
+}
+
+func (d *Sponsored) Refund(
+	ctx context.Context,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	exists, sponsorship, err := storage.GetSponsorship(ctx, mu, d.MachineAttestTx)
+	if err != nil {
+		return err
+	}
+	// The action may have replaced the sponsorship, only give back what was
+	// charged against it.
+	if exists && sponsorship.Sponsor == d.Operator {
+		sponsorship.Spent, err = smath.Sub(sponsorship.Spent, amount)
+		if err != nil {
+			sponsorship.Spent = 0
+		}
+		if err := storage.SetSponsorship(ctx, mu, d.MachineAttestTx, sponsorship); err != nil {
+			return err
+		}
+	}
+	// Don't create account if it doesn't exist (may have sent all funds).
+	return storage.AddBalance(ctx, mu, d.Operator, ids.Empty, amount, false)
+}
+
+// sponsoredMessage is what the machine signs for a sponsored transaction with
+// digest [msg].
+func sponsoredMessage(msg []byte, sponsor codec.Address, attestTx ids.ID) []byte {
+	m := make([]byte, 0, len(msg)+codec.AddressLen+consts.IDLen)
+	m = append(m, msg...)
+	m = append(m, sponsor[:]...)
+	return append(m, attestTx[:]...)
+}
+
+var _ chain.AuthFactory = (*SponsoredFactory)(nil)
+
+// NewSponsoredFactory signs with [machine], the factory of the machine key,
+// and charges fees to [sponsor].
+func NewSponsoredFactory(sponsor codec.Address, attestTx ids.ID, machine chain.AuthFactory) *SponsoredFactory {
+	return &SponsoredFactory{sponsor, attestTx, machine}
+}
+
+type SponsoredFactory struct {
+	sponsor  codec.Address
+	attestTx ids.ID
+	machine  chain.AuthFactory
+}
+
+func (d *SponsoredFactory) Sign(msg []byte, action chain.Action) (chain.Auth, error) {
+	machine, err := d.machine.Sign(sponsoredMessage(msg, d.sponsor, d.attestTx), action)
+	if err != nil {
+		return nil, err
+	}
+	return &Sponsored{Operator: d.sponsor, MachineAttestTx: d.attestTx, Machine: machine}, nil
+}
+
+func (d *SponsoredFactory) MaxUnits() (uint64, uint64, []uint16) {
+	bandwidth, compute, _ := d.machine.MaxUnits()
+	return sponsoredHeaderSize + bandwidth, compute + SponsoredComputeUnits,
+		[]uint16{storage.BalanceChunks, storage.SponsorshipChunks, storage.MachineCIDChunks}
+}
+
+type SponsoredAuthEngine struct{}
+
+func (*SponsoredAuthEngine) GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier {
+	return &SponsoredBatch{
+		ed25519:   (&ED25519AuthEngine{}).GetBatchVerifier(cores, count),
+		secp256r1: (&SECP256R1AuthEngine{}).GetBatchVerifier(cores, count),
+	}
+}
+
+func (*SponsoredAuthEngine) Cache(auth chain.Auth) {
+	pauth, ok := auth.(*Sponsored)
+	if !ok {
+		return
+	}
+	switch pauth.Machine.GetTypeID() {
+	case ed25519ID:
+		(&ED25519AuthEngine{}).Cache(pauth.Machine)
+	case secp256r1ID:
+		(&SECP256R1AuthEngine{}).Cache(pauth.Machine)
+	}
+}
+
+// SponsoredBatch verifies the machine signatures of sponsored transactions
+// with the batch verifier of their key type.
+type SponsoredBatch struct {
+	ed25519   chain.AuthBatchVerifier
+	secp256r1 chain.AuthBatchVerifier
+}
+
+func (b *SponsoredBatch) Add(msg []byte, rauth chain.Auth) func() error {
+	auth := rauth.(*Sponsored)
+	msg = sponsoredMessage(msg, auth.Operator, auth.MachineAttestTx)
+	if auth.Machine.GetTypeID() == secp256r1ID {
+		return b.secp256r1.Add(msg, auth.Machine)
+	}
+	return b.ed25519.Add(msg, auth.Machine)
+}
+
+func (b *SponsoredBatch) Done() []func() error {
+	return append(b.ed25519.Done(), b.secp256r1.Done()...)
+}
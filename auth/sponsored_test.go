@@ -0,0 +1,301 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"dataverse/storage"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+// memState is an in-memory [state.Mutable].
+type memState map[string][]byte
+
+func (s memState) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	v, ok := s[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s memState) Insert(_ context.Context, key []byte, value []byte) error {
+	s[string(key)] = value
+	return nil
+}
+
+func (s memState) Remove(_ context.Context, key []byte) error {
+	delete(s, string(key))
+	return nil
+}
+
+// sponsoredMachine is a machine attested by [operator] and sponsored by it.
+type sponsoredMachine struct {
+	state    memState
+	operator codec.Address
+	attestTx ids.ID
+	factory  chain.AuthFactory
+}
+
+// newSponsoredMachine attests the machine signing with [machine] and sponsors
+// it up to [limit], with [balance] to pay fees from.
+func newSponsoredMachine(t *testing.T, machine chain.AuthFactory, limit uint64, balance uint64) *sponsoredMachine {
+	t.Helper()
+	ctx := context.Background()
+	m := &sponsoredMachine{
+		state:    memState{},
+		operator: codec.CreateAddress(ed25519ID, ids.GenerateTestID()),
+		attestTx: ids.GenerateTestID(),
+	}
+	m.factory = NewSponsoredFactory(m.operator, m.attestTx, machine)
+
+	// The machine address is that of the key [machine] signs with
+	unsigned, err := machine.Sign([]byte("address"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetAttestMachine(ctx, m.state, m.attestTx, storage.AttestMachineData{
+		MachineAddress: unsigned.Actor(),
+		Attester:       m.operator,
+		Status:         storage.MachineActive,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetSponsorship(ctx, m.state, m.attestTx, storage.SponsorshipData{
+		Sponsor: m.operator,
+		Cap:     limit,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.AddBalance(ctx, m.state, m.operator, ids.Empty, balance, true); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func (m *sponsoredMachine) sign(t *testing.T, msg []byte) *Sponsored {
+	t.Helper()
+	auth, err := m.factory.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.(*Sponsored)
+}
+
+func (m *sponsoredMachine) spent(t *testing.T) uint64 {
+	t.Helper()
+	_, sponsorship, err := storage.GetSponsorship(context.Background(), m.state, m.attestTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sponsorship.Spent
+}
+
+func (m *sponsoredMachine) balance(t *testing.T) uint64 {
+	t.Helper()
+	bal, err := storage.GetBalance(context.Background(), m.state, m.operator, ids.Empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bal
+}
+
+func machineFactories(t *testing.T) map[string]chain.AuthFactory {
+	t.Helper()
+	edPriv, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1Priv, err := secp256r1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]chain.AuthFactory{
+		"ed25519":   NewED25519Factory(edPriv),
+		"secp256r1": NewSECP256R1Factory(r1Priv),
+	}
+}
+
+func TestSponsoredVerify(t *testing.T) {
+	ctx := context.Background()
+	msg := []byte("tx")
+	for name, factory := range machineFactories(t) {
+		m := newSponsoredMachine(t, factory, 100, 1_000)
+		auth := m.sign(t, msg)
+		if err := auth.AsyncVerify(msg); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := auth.Verify(ctx, nil, m.state, nil); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if auth.Actor() != auth.Machine.Actor() || auth.Sponsor() != m.operator {
+			t.Fatalf("%s: unexpected actor or sponsor", name)
+		}
+
+		// The machine signature is bound to the sponsor and the attestation
+		plain, err := factory.Sign(msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := (&Sponsored{Operator: m.operator, MachineAttestTx: m.attestTx, Machine: plain}).AsyncVerify(msg); err == nil {
+			t.Fatalf("%s: unsponsored signature verified", name)
+		}
+		moved := *auth
+		moved.Operator = codec.CreateAddress(ed25519ID, ids.GenerateTestID())
+		if err := moved.AsyncVerify(msg); err == nil {
+			t.Fatalf("%s: signature verified for another sponsor", name)
+		}
+
+		p := codec.NewWriter(auth.Size(), auth.Size())
+		auth.Marshal(p)
+		parsed, err := UnmarshalSponsored(codec.NewReader(p.Bytes(), len(p.Bytes())), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := parsed.AsyncVerify(msg); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// Machine signatures are verified and cached by the engine of
+		// their key type
+		engine := &SponsoredAuthEngine{}
+		engine.Cache(auth)
+		verifier := engine.GetBatchVerifier(1, 1)
+		if job := verifier.Add(msg, auth); job != nil {
+			if err := job(); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		for _, job := range verifier.Done() {
+			if err := job(); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+	}
+}
+
+func TestSponsoredWrongParties(t *testing.T) {
+	ctx := context.Background()
+	msg := []byte("tx")
+	factories := machineFactories(t)
+	m := newSponsoredMachine(t, factories["ed25519"], 100, 1_000)
+
+	// Another operator can't charge itself for the machine
+	other := NewSponsoredFactory(codec.CreateAddress(ed25519ID, ids.GenerateTestID()), m.attestTx, factories["ed25519"])
+	auth, err := other.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Verify(ctx, nil, m.state, nil); !errors.Is(err, ErrNotMachineSponsor) {
+		t.Fatalf("expected %v, got %v", ErrNotMachineSponsor, err)
+	}
+
+	// Nor can another machine be sponsored through the attestation
+	impostor := NewSponsoredFactory(m.operator, m.attestTx, factories["secp256r1"])
+	auth, err = impostor.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Verify(ctx, nil, m.state, nil); !errors.Is(err, ErrNotAttestedMachine) {
+		t.Fatalf("expected %v, got %v", ErrNotAttestedMachine, err)
+	}
+
+	unknown := NewSponsoredFactory(m.operator, ids.GenerateTestID(), factories["ed25519"])
+	auth, err = unknown.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Verify(ctx, nil, m.state, nil); !errors.Is(err, ErrMachineNotAttested) {
+		t.Fatalf("expected %v, got %v", ErrMachineNotAttested, err)
+	}
+
+	// The sponsorship belongs to someone else, e.g. a previous attester
+	if err := storage.SetSponsorship(ctx, m.state, m.attestTx, storage.SponsorshipData{
+		Sponsor: codec.CreateAddress(ed25519ID, ids.GenerateTestID()),
+		Cap:     100,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.sign(t, msg).Verify(ctx, nil, m.state, nil); !errors.Is(err, ErrNotSponsored) {
+		t.Fatalf("expected %v, got %v", ErrNotSponsored, err)
+	}
+
+	exists, machine, err := storage.GetAttestMachine(ctx, m.state, m.attestTx)
+	if err != nil || !exists {
+		t.Fatal(err)
+	}
+	machine.Status = storage.MachineSuspended
+	if err := storage.SetAttestMachine(ctx, m.state, m.attestTx, machine); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.sign(t, msg).Verify(ctx, nil, m.state, nil); !errors.Is(err, ErrMachineNotActive) {
+		t.Fatalf("expected %v, got %v", ErrMachineNotActive, err)
+	}
+}
+
+func TestSponsoredCap(t *testing.T) {
+	ctx := context.Background()
+	m := newSponsoredMachine(t, machineFactories(t)["ed25519"], 100, 1_000)
+	auth := m.sign(t, []byte("tx"))
+
+	if err := auth.CanDeduct(ctx, m.state, 101); !errors.Is(err, ErrSponsorshipCapExceeded) {
+		t.Fatalf("expected %v, got %v", ErrSponsorshipCapExceeded, err)
+	}
+	if err := auth.CanDeduct(ctx, m.state, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Deduct(ctx, m.state, 60); err != nil {
+		t.Fatal(err)
+	}
+	if m.spent(t) != 60 || m.balance(t) != 940 {
+		t.Fatalf("spent %d with balance %d after deducting", m.spent(t), m.balance(t))
+	}
+	if err := auth.Deduct(ctx, m.state, 50); !errors.Is(err, ErrSponsorshipCapExceeded) {
+		t.Fatalf("expected %v, got %v", ErrSponsorshipCapExceeded, err)
+	}
+	if m.spent(t) != 60 || m.balance(t) != 940 {
+		t.Fatalf("spent %d with balance %d after exceeding the cap", m.spent(t), m.balance(t))
+	}
+
+	// Unused fees are given back to the sponsor and the sponsorship
+	if err := auth.Refund(ctx, m.state, 20); err != nil {
+		t.Fatal(err)
+	}
+	if m.spent(t) != 40 || m.balance(t) != 960 {
+		t.Fatalf("spent %d with balance %d after refunding", m.spent(t), m.balance(t))
+	}
+
+	// A sponsorship replaced by the action isn't credited for fees charged
+	// against the one it replaced
+	if err := auth.Deduct(ctx, m.state, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetSponsorship(ctx, m.state, m.attestTx, storage.SponsorshipData{
+		Sponsor: codec.CreateAddress(ed25519ID, ids.GenerateTestID()),
+		Cap:     100,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Refund(ctx, m.state, 40); err != nil {
+		t.Fatal(err)
+	}
+	if m.spent(t) != 0 || m.balance(t) != 960 {
+		t.Fatalf("spent %d with balance %d after refunding a replaced sponsorship", m.spent(t), m.balance(t))
+	}
+
+	// The sponsor must be able to pay within the cap
+	poor := newSponsoredMachine(t, machineFactories(t)["ed25519"], 100, 10)
+	if err := poor.sign(t, []byte("tx")).CanDeduct(ctx, poor.state, 50); !errors.Is(err, storage.ErrInvalidBalance) {
+		t.Fatalf("expected %v, got %v", storage.ErrInvalidBalance, err)
+	}
+}
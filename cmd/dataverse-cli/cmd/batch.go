@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"dataverse/actions"
+	"dataverse/content"
+	"dataverse/merkle"
+	trpc "dataverse/rpc"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+// readBatch reads the data CIDs of a batch from [path], one per line, and
+// builds the Merkle tree of their multihashes, which it returns in order.
+func readBatch(path string) ([][]byte, *merkle.Tree, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var multihashes [][]byte
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		cid := strings.TrimSpace(scanner.Text())
+		if len(cid) == 0 {
+			continue
+		}
+		multihash, err := content.Multihash(cid)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		multihashes = append(multihashes, multihash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	tree, err := merkle.New(multihashes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return multihashes, tree, nil
+}
+
+// batchProof returns the index of [cid] in the batch of [multihashes] and its
+// inclusion proof.
+func batchProof(multihashes [][]byte, tree *merkle.Tree, cid string) (int, [][]byte, error) {
+	multihash, err := content.Multihash(cid)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, m := range multihashes {
+		if !bytes.Equal(m, multihash) {
+			continue
+		}
+		proof, err := tree.Proof(i)
+		return i, proof, err
+	}
+	return 0, nil, ErrCIDNotInBatch
+}
+
+var notarizeBatchCmd = &cobra.Command{
+	Use: "notarize-batch",
+	RunE: func(*cobra.Command, []string) error {
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+		path, err := handler.Root().PromptString("Data CIDs File Path", 1, 500)
+		if err != nil {
+			return err
+		}
+		_, tree, err := readBatch(path)
+		if err != nil {
+			return err
+		}
+		start, err := handler.Root().PromptTime("first data produced at (unix ms)")
+		if err != nil {
+			return err
+		}
+		end, err := handler.Root().PromptTime("last data produced at (unix ms)")
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}root:{{/}} %s {{yellow}}count:{{/}} %d\n",
+			hex.EncodeToString(tree.Root()),
+			tree.Count(),
+		)
+		return confirmAndSend(context.Background(), &actions.NotarizeBatch{
+			MachineAttestTx: attestationTx,
+			Root:            tree.Root(),
+			Count:           uint32(tree.Count()),
+			Start:           start,
+			End:             end,
+			DataType:        []byte("/dataverse.asset.MsgNotarizedAsset"),
+		})
+	},
+}
+
+var batchProofCmd = &cobra.Command{
+	Use: "batch-proof [cids file] [cid]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		multihashes, tree, err := readBatch(args[0])
+		if err != nil {
+			return err
+		}
+		index, proof, err := batchProof(multihashes, tree, args[1])
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}root:{{/}} %s {{yellow}}count:{{/}} %d {{yellow}}index:{{/}} %d\n",
+			hex.EncodeToString(tree.Root()),
+			tree.Count(),
+			index,
+		)
+		for _, h := range proof {
+			utils.Outf("%s\n", hex.EncodeToString(h))
+		}
+		return nil
+	},
+}
+
+var getNotarizeBatchCmd = &cobra.Command{
+	Use: "get-notarize-batch",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		id, err := handler.Root().PromptID("batch txid")
+		if err != nil {
+			return err
+		}
+		batch, err := tcli.NotarizeBatch(ctx, id)
+		if err != nil {
+			return err
+		}
+		printBatch(batch)
+		return nil
+	},
+}
+
+var verifyInclusionCmd = &cobra.Command{
+	Use: "verify-inclusion",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		id, err := handler.Root().PromptID("batch txid")
+		if err != nil {
+			return err
+		}
+		path, err := handler.Root().PromptString("Data CIDs File Path", 1, 500)
+		if err != nil {
+			return err
+		}
+		cid, err := handler.Root().PromptString("Data CID", 1, actions.DataCIDUnits)
+		if err != nil {
+			return err
+		}
+		multihashes, tree, err := readBatch(path)
+		if err != nil {
+			return err
+		}
+		index, proof, err := batchProof(multihashes, tree, cid)
+		if err != nil {
+			return err
+		}
+		reply, err := tcli.VerifyInclusion(ctx, id, cid, uint32(index), proof)
+		if err != nil {
+			return err
+		}
+		printBatch(&reply.Notarization)
+		if !reply.Included {
+			utils.Outf("{{red}}%s is not included in batch %s{{/}}\n", cid, id)
+			return nil
+		}
+		utils.Outf("{{green}}%s is included in batch %s at index %d{{/}}\n", cid, id, index)
+		return nil
+	},
+}
+
+func printBatch(batch *trpc.NotarizeBatchReply) {
+	utils.Outf(
+		"{{yellow}}machine attestation:{{/}} %s {{yellow}}owner:{{/}} %s {{yellow}}root:{{/}} %s {{yellow}}count:{{/}} %d {{yellow}}from:{{/}} %s {{yellow}}to:{{/}} %s\n",
+		batch.AttestMachineTx,
+		batch.DataOwnerAddr,
+		hex.EncodeToString(batch.Root),
+		batch.Count,
+		time.UnixMilli(batch.Start),
+		time.UnixMilli(batch.End),
+	)
+	if batch.AfterRevocation {
+		utils.Outf("{{red}}notarized after the machine was revoked{{/}}\n")
+	}
+}
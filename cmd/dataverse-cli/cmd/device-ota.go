@@ -0,0 +1,298 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+
+	"dataverse/consts"
+	"dataverse/gateway"
+	"dataverse/ota"
+	trpc "dataverse/rpc"
+	"dataverse/storage"
+)
+
+// Paths of the routes devices pull their updates from. They authenticate
+// with their machine key, not with the auth of the gateway.
+const (
+	DeviceManifestPath = "/ota/manifest"
+	DeviceDownloadPath = "/ota/download"
+)
+
+// devicePaths are public unless the gateway config sets their auth.
+var devicePaths = []string{DeviceManifestPath, DeviceDownloadPath}
+
+// DeviceManifest is the update a device should install.
+type DeviceManifest struct {
+	UpdateTx       string `json:"update_tx"`
+	ProjectID      string `json:"project_id"`
+	DeviceName     string `json:"device_name"`
+	Version        string `json:"version"`
+	Size           uint64 `json:"size"`
+	ExecutableHash string `json:"executable_hash"`
+	Signature      string `json:"signature"`
+	ReleaseKey     string `json:"release_key"`
+	DownloadURL    string `json:"download_url"`
+}
+
+// resolveMachine returns the machine of the attestation [attestTx] on chain.
+func resolveMachine(ctx context.Context, attestTx ids.ID) (*ota.Machine, error) {
+	tcli, err := gatewayClient()
+	if err != nil {
+		return nil, err
+	}
+	attestation, err := tcli.AttestMachine(ctx, attestTx)
+	if err != nil {
+		if strings.Contains(err.Error(), trpc.ErrAttestMachineNotFound.Error()) {
+			return nil, ErrUnknownMachine
+		}
+		return nil, err
+	}
+	addr, err := codec.ParseAddressBech32(consts.HRP, attestation.MachineAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &ota.Machine{
+		AttestTx: attestTx,
+		Address:  addr,
+		Category: string(attestation.MachineCategory),
+		Active:   attestation.Status == storage.MachineActive.String(),
+	}, nil
+}
+
+// verifyDevice returns the machine that signed [r], replying with the
+// reason it can't be authenticated otherwise.
+func verifyDevice(w http.ResponseWriter, r *http.Request) (*ota.Machine, bool) {
+	machine, err := ota.VerifyRequest(r, time.Now(), resolveMachine)
+	switch {
+	case err == nil:
+		return machine, true
+	case errors.Is(err, ota.ErrMachineNotActive):
+		gateway.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ota.ErrUnsignedRequest), errors.Is(err, ota.ErrExpiredRequest),
+		errors.Is(err, ota.ErrInvalidRequestSig), errors.Is(err, ota.ErrNotMachineKey),
+		errors.Is(err, ErrUnknownMachine):
+		gateway.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		gateway.Error(w, "Cannot verify machine: "+err.Error(), http.StatusBadGateway)
+	}
+	return nil, false
+}
+
+// releasedUpdate returns the newest update of [project] for [device] that a
+// rollout offered to the machine [attestTx], nil if there is none.
+func releasedUpdate(
+	ctx context.Context,
+	tcli *trpc.JSONRPCClient,
+	rollouts *ota.Orchestrator,
+	attestTx ids.ID,
+	project ids.ID,
+	device string,
+) (*trpc.LatestUpdateReply, error) {
+	offered, err := rollouts.Offered(ctx, attestTx)
+	if err != nil {
+		return nil, err
+	}
+	var newest *trpc.LatestUpdateReply
+	for _, updateTx := range offered {
+		update, err := tcli.UpdateManifest(ctx, updateTx)
+		if err != nil {
+			return nil, err
+		}
+		if update.ProjectTxID != project || string(update.ForDeviceName) != device {
+			continue
+		}
+		if newest == nil || update.UpdateVersion.Compare(newest.UpdateVersion) > 0 {
+			newest = &trpc.LatestUpdateReply{UpdateTx: updateTx, UpdateReply: *update}
+		}
+	}
+	return newest, nil
+}
+
+// DeviceManifestHandler serves the latest update of a project released to
+// the device that signed the request, if it is newer than [current_version].
+// While the rollout of the latest update has not reached the device, the
+// newest update rolled out to it is served instead. It replies with no
+// content when there is nothing to install, and records the install of
+// updates offered by a rollout once the device reports their version.
+func DeviceManifestHandler(rollouts *ota.Orchestrator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		machine, ok := verifyDevice(w, r)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		projectID, err := ids.FromString(query.Get("project_id"))
+		if err != nil {
+			gateway.Error(w, "Invalid project id", http.StatusBadRequest)
+			return
+		}
+		device := query.Get("device_name")
+		if len(device) == 0 {
+			device = machine.Category
+		}
+		var current *storage.Version
+		if v := query.Get("current_version"); len(v) > 0 {
+			version, err := storage.ParseVersion(v)
+			if err != nil {
+				gateway.Error(w, "Invalid current version", http.StatusBadRequest)
+				return
+			}
+			current = &version
+		}
+
+		tcli, err := gatewayClient()
+		if err != nil {
+			gateway.Error(w, "Cannot reach chain: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		update, err := tcli.LatestUpdate(r.Context(), projectID, device)
+		if err != nil {
+			if strings.Contains(err.Error(), trpc.ErrUpdateNotFound.Error()) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			gateway.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		released, err := rollouts.Released(r.Context(), update.UpdateTx, machine.AttestTx)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !released {
+			// The rollout of the update has not reached the device
+			update, err = releasedUpdate(r.Context(), tcli, rollouts, machine.AttestTx, projectID, device)
+			if err != nil {
+				gateway.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			if update == nil {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		if current != nil {
+			switch update.UpdateVersion.Compare(*current) {
+			case 0:
+				if err := rollouts.Installed(r.Context(), update.UpdateTx, machine.AttestTx); err != nil {
+					gateway.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			case -1:
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		hasReleaseKey, releaseKey, err := tcli.ReleaseKey(r.Context(), projectID)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		if !hasReleaseKey {
+			gateway.Error(w, ErrNoReleaseKey.Error(), http.StatusConflict)
+			return
+		}
+
+		download := url.Values{"update_tx": {update.UpdateTx.String()}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&DeviceManifest{
+			UpdateTx:       update.UpdateTx.String(),
+			ProjectID:      update.ProjectTxID.String(),
+			DeviceName:     string(update.ForDeviceName),
+			Version:        update.UpdateVersion.String(),
+			Size:           update.UpdateSize,
+			ExecutableHash: hex.EncodeToString(update.UpdateExecutableHash),
+			Signature:      hex.EncodeToString(update.Signature),
+			ReleaseKey:     hex.EncodeToString(releaseKey[:]),
+			DownloadURL:    DeviceDownloadPath + "?" + download.Encode(),
+		})
+	}
+}
+
+// DeviceDownloadHandler streams the executable of [update_tx] to the device
+// that signed the request, if the update is released to it. Range requests
+// let devices resume interrupted downloads. Executables are never streamed
+// from the content store, which only detects a mismatch at the end, but
+// served from the firmware cache once fetched in full and checked.
+func DeviceDownloadHandler(rollouts *ota.Orchestrator, firmware *firmwareCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		machine, ok := verifyDevice(w, r)
+		if !ok {
+			return
+		}
+		updateTx, err := ids.FromString(r.URL.Query().Get("update_tx"))
+		if err != nil {
+			gateway.Error(w, "Invalid txid", http.StatusBadRequest)
+			return
+		}
+		released, err := rollouts.Released(r.Context(), updateTx, machine.AttestTx)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !released {
+			gateway.Error(w, "update is not released to the device", http.StatusForbidden)
+			return
+		}
+
+		tcli, err := gatewayClient()
+		if err != nil {
+			gateway.Error(w, "Cannot reach chain: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		update, err := tcli.UpdateManifest(r.Context(), updateTx)
+		if err != nil {
+			if strings.Contains(err.Error(), trpc.ErrUpdateNotFound.Error()) {
+				gateway.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			gateway.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		path, err := firmware.firmware(r.Context(), updateTx)
+		if err != nil {
+			gateway.Error(w, "Cannot fetch executable: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// The executable of an update never changes, its hash identifies it
+		// for conditional and If-Range requests
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("ETag", `"`+hex.EncodeToString(update.UpdateExecutableHash)+`"`)
+		http.ServeContent(w, r, updateTx.String()+".bin", info.ModTime(), f)
+	}
+}
@@ -11,4 +11,16 @@ var (
 	ErrNotMultiple        = errors.New("must be a multiple")
 	ErrInsufficientSupply = errors.New("insufficient supply")
 	ErrMustFill           = errors.New("must fill")
+	ErrNoReleaseKey       = errors.New("project has no release key")
+	ErrDigestMismatch     = errors.New("executable does not match the update digest")
+	ErrSizeMismatch       = errors.New("executable does not match the update size")
+	ErrInvalidSignature   = errors.New("update is not signed by the project release key")
+	ErrUnsignedUpdate     = errors.New("update was published before updates were signed, publish it again")
+	ErrNoBuyerKey         = errors.New("purchase receipt has no buyer key")
+	ErrCIDNotInBatch      = errors.New("cid is not in the batch")
+	ErrInvalidKeyType     = errors.New("invalid key type")
+	ErrNoRemoteSigner     = errors.New("no --remote-signer set")
+	ErrConflictingSigners = errors.New("--signer-key and --remote-signer are exclusive")
+	ErrNoDeviceEndpoint   = errors.New("device has no endpoint to push to")
+	ErrUnknownMachine     = errors.New("machine is not attested")
 )
@@ -0,0 +1,177 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"dataverse/gateway"
+	"dataverse/ota"
+	trpc "dataverse/rpc"
+)
+
+const defaultIndexDB = "index.db"
+
+var (
+	gatewayConfigPath    string
+	gatewaySignerKey     string
+	gatewaySignerKeyType string
+	gatewayIndexDB       string
+
+	// gatewaySigner signs the transactions of the gateway, the default key
+	// of the CLI (or the --remote-signer) is used if it is nil.
+	gatewaySigner *cli.PrivateKey
+
+	gatewayClientMu sync.Mutex
+	gatewayClientV  *trpc.JSONRPCClient
+)
+
+// gatewayActor returns the actor gateway handlers issue transactions with.
+func gatewayActor() (
+	ids.ID, *cli.PrivateKey, chain.AuthFactory,
+	*rpc.JSONRPCClient, *rpc.WebSocketClient, *trpc.JSONRPCClient, error,
+) {
+	if gatewaySigner != nil {
+		return handler.Actor(gatewaySigner.Address, gatewaySigner.Bytes)
+	}
+	return handler.DefaultActor()
+}
+
+// gatewayClient returns the client gateway handlers read the chain with. It
+// is built once, reads don't need the actor of [gatewayActor].
+func gatewayClient() (*trpc.JSONRPCClient, error) {
+	gatewayClientMu.Lock()
+	defer gatewayClientMu.Unlock()
+	if gatewayClientV == nil {
+		tcli, err := handler.Client()
+		if err != nil {
+			return nil, err
+		}
+		gatewayClientV = tcli
+	}
+	return gatewayClientV, nil
+}
+
+// gatewayRoutes are the handlers served by the gateway.
+func gatewayRoutes(ctx context.Context, rollouts *ota.Orchestrator, firmware *firmwareCache) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		// updates
+		"/":                  GetUpdateDataHandler(ctx),
+		"/create-repository": CreateRepositoryHandler(ctx),
+		"/create-update":     CreateUpdateHandler(ctx),
+		"/check-hash":        GetUpdateHash(ctx),
+		"/push-update":       PushUpdate(ctx),
+		"/get-update":        GetUpdate(ctx),
+		"/latest-update":     GetLatestUpdate(ctx),
+
+		// machines
+		"/register-machine":      RegisterMachineCID(ctx),
+		"/get-register-machine":  GetregisterMachineCID(ctx),
+		"/attest-machine":        AttestMachine(ctx),
+		"/notarize-data":         NotarizeDataView(ctx),
+		"/verify":                VerifyNotarizeDataView(ctx),
+		"/machine-notarizations": MachineNotarizationsView(ctx),
+
+		// rollouts
+		"/rollouts/create":   CreateRollout(rollouts),
+		"/rollouts/status":   RolloutStatus(rollouts),
+		"/rollouts/devices":  RolloutDevices(rollouts),
+		"/rollouts/start":    RolloutAction(rollouts, rollouts.Start),
+		"/rollouts/halt":     RolloutAction(rollouts, rollouts.Halt),
+		"/rollouts/rollback": RolloutAction(rollouts, rollouts.Rollback),
+
+		// devices pulling their updates
+		DeviceManifestPath: DeviceManifestHandler(rollouts),
+		DeviceDownloadPath: DeviceDownloadHandler(rollouts, firmware),
+	}
+}
+
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway",
+	Short: "Serve the update and machine APIs over HTTP",
+	RunE: func(*cobra.Command, []string) error {
+		cfg := gateway.DefaultConfig()
+		if len(gatewayConfigPath) > 0 {
+			var err error
+			cfg, err = gateway.LoadConfig(gatewayConfigPath)
+			if err != nil {
+				return err
+			}
+		}
+		if cfg.Routes == nil {
+			cfg.Routes = map[string]gateway.RouteConfig{}
+		}
+		for _, path := range devicePaths {
+			if _, ok := cfg.Routes[path]; !ok {
+				cfg.Routes[path] = gateway.RouteConfig{
+					Auth:      []string{gateway.AuthNone},
+					RateLimit: cfg.DefaultRoute.RateLimit,
+				}
+			}
+		}
+		g, err := gateway.New(cfg)
+		if err != nil {
+			return err
+		}
+		if len(gatewaySignerKey) > 0 {
+			if len(remoteSigner) > 0 {
+				return ErrConflictingSigners
+			}
+			gatewaySigner, err = loadPrivateKey(gatewaySignerKeyType, gatewaySignerKey)
+			if err != nil {
+				return err
+			}
+		}
+		db, err := gorm.Open(sqlite.Open(gatewayIndexDB), &gorm.Config{})
+		if err != nil {
+			return err
+		}
+		if err := db.AutoMigrate(&MachineEndpoint{}); err != nil {
+			return err
+		}
+		// Rollouts update devices concurrently, sqlite allows a single writer
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		DB = db
+
+		firmware, err := newFirmwareCache()
+		if err != nil {
+			return err
+		}
+		defer firmware.Close()
+		rollouts, err := ota.New(db, &attestationInventory{db: db}, &firmwarePusher{cache: firmware}, chainUpdates{})
+		if err != nil {
+			return err
+		}
+		defer rollouts.Close()
+		if err := rollouts.Resume(); err != nil {
+			return err
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		for path, h := range gatewayRoutes(ctx, rollouts, firmware) {
+			g.HandleFunc(path, h)
+		}
+		utils.Outf("{{green}}gateway listening on:{{/}} %s\n", cfg.ListenAddress)
+		return g.Run(ctx)
+	},
+}
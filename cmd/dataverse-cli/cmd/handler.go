@@ -5,17 +5,18 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"dataverse/auth"
 	"dataverse/consts"
 	trpc "dataverse/rpc"
+	"dataverse/signer"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/cli"
 	"github.com/ava-labs/hypersdk/codec"
 	hconsts "github.com/ava-labs/hypersdk/consts"
-	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	hutils "github.com/ava-labs/hypersdk/utils"
@@ -102,10 +103,70 @@ func (h *Handler) DefaultActor() (
 	ids.ID, *cli.PrivateKey, chain.AuthFactory,
 	*rpc.JSONRPCClient, *rpc.WebSocketClient, *trpc.JSONRPCClient, error,
 ) {
+	if len(remoteSigner) > 0 {
+		return h.RemoteActor(remoteSigner, remoteSignerAddress)
+	}
 	addr, priv, err := h.h.GetDefaultKey(true)
 	if err != nil {
 		return ids.Empty, nil, nil, nil, nil, nil, err
 	}
+	return h.Actor(addr, priv)
+}
+
+// Actor is [DefaultActor] signing with the key [addr] instead of the default
+// key.
+func (h *Handler) Actor(addr codec.Address, priv []byte) (
+	ids.ID, *cli.PrivateKey, chain.AuthFactory,
+	*rpc.JSONRPCClient, *rpc.WebSocketClient, *trpc.JSONRPCClient, error,
+) {
+	factory, err := auth.NewFactory(addr, priv)
+	if err != nil {
+		return ids.Empty, nil, nil, nil, nil, nil, err
+	}
+	return h.actor(&cli.PrivateKey{Address: addr, Bytes: priv}, factory)
+}
+
+// RemoteActor is [DefaultActor] signing with the key [address] held by the
+// signer at [uri]. [address] may be empty if the signer holds a single key.
+// The returned key has no private bytes.
+func (h *Handler) RemoteActor(uri string, address string) (
+	ids.ID, *cli.PrivateKey, chain.AuthFactory,
+	*rpc.JSONRPCClient, *rpc.WebSocketClient, *trpc.JSONRPCClient, error,
+) {
+	addr := codec.EmptyAddress
+	if len(address) > 0 {
+		var err error
+		addr, err = codec.ParseAddressBech32(consts.HRP, address)
+		if err != nil {
+			return ids.Empty, nil, nil, nil, nil, nil, err
+		}
+	}
+	client := signer.NewClient(uri, os.Getenv(signerAPIKeyEnv))
+	factory, err := signer.NewFactory(context.TODO(), client, addr)
+	if err != nil {
+		return ids.Empty, nil, nil, nil, nil, nil, err
+	}
+	return h.actor(&cli.PrivateKey{Address: factory.Address()}, factory)
+}
+
+// Client returns a client of the default chain, for reads that need no
+// actor.
+func (h *Handler) Client() (*trpc.JSONRPCClient, error) {
+	chainID, uris, err := h.h.GetDefaultChain(false)
+	if err != nil {
+		return nil, err
+	}
+	networkID, _, _, err := rpc.NewJSONRPCClient(uris[0]).Network(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return trpc.NewJSONRPCClient(uris[0], networkID, chainID), nil
+}
+
+func (h *Handler) actor(key *cli.PrivateKey, factory chain.AuthFactory) (
+	ids.ID, *cli.PrivateKey, chain.AuthFactory,
+	*rpc.JSONRPCClient, *rpc.WebSocketClient, *trpc.JSONRPCClient, error,
+) {
 	chainID, uris, err := h.h.GetDefaultChain(true)
 	if err != nil {
 		return ids.Empty, nil, nil, nil, nil, nil, err
@@ -125,12 +186,33 @@ func (h *Handler) DefaultActor() (
 	if err != nil {
 		return ids.Empty, nil, nil, nil, nil, nil, err
 	}
-	return chainID, &cli.PrivateKey{Address: addr, Bytes: priv}, auth.NewED25519Factory(ed25519.PrivateKey(priv)), jcli, scli,
-		trpc.NewJSONRPCClient(
-			uris[0],
-			networkID,
-			chainID,
-		), nil
+	tcli := trpc.NewJSONRPCClient(uris[0], networkID, chainID)
+	factory, err = h.sponsoredFactory(tcli, factory)
+	if err != nil {
+		return ids.Empty, nil, nil, nil, nil, nil, err
+	}
+	return chainID, key, factory, jcli, scli, tcli, nil
+}
+
+// sponsoredFactory wraps [factory] with --sponsored-by, so fees are charged
+// to the attester sponsoring the machine instead.
+func (*Handler) sponsoredFactory(tcli *trpc.JSONRPCClient, factory chain.AuthFactory) (chain.AuthFactory, error) {
+	if len(sponsoredBy) == 0 {
+		return factory, nil
+	}
+	attestTx, err := ids.FromString(sponsoredBy)
+	if err != nil {
+		return nil, err
+	}
+	sponsorship, err := tcli.Sponsorship(context.TODO(), attestTx)
+	if err != nil {
+		return nil, err
+	}
+	sponsor, err := codec.ParseAddressBech32(consts.HRP, sponsorship.Sponsor)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewSponsoredFactory(sponsor, attestTx, factory), nil
 }
 
 type Controller struct {
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 
@@ -29,16 +30,69 @@ var keyCmd = &cobra.Command{
 	},
 }
 
-var genKeyCmd = &cobra.Command{
-	Use: "generate",
-	RunE: func(*cobra.Command, []string) error {
+const (
+	ed25519Key   = "ed25519"
+	secp256r1Key = "secp256r1"
+)
+
+func generatePrivateKey(k string) (*cli.PrivateKey, error) {
+	switch k {
+	case ed25519Key:
 		p, err := ed25519.GeneratePrivateKey()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		priv := &cli.PrivateKey{
+		return &cli.PrivateKey{
 			Address: auth.NewED25519Address(p.PublicKey()),
 			Bytes:   p[:],
+		}, nil
+	case secp256r1Key:
+		p, err := secp256r1.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return &cli.PrivateKey{
+			Address: auth.NewSECP256R1Address(p.PublicKey()),
+			Bytes:   p[:],
+		}, nil
+	default:
+		return nil, ErrInvalidKeyType
+	}
+}
+
+func loadPrivateKey(k string, path string) (*cli.PrivateKey, error) {
+	switch k {
+	case ed25519Key:
+		p, err := utils.LoadBytes(path, ed25519.PrivateKeyLen)
+		if err != nil {
+			return nil, err
+		}
+		pk := ed25519.PrivateKey(p)
+		return &cli.PrivateKey{
+			Address: auth.NewED25519Address(pk.PublicKey()),
+			Bytes:   p,
+		}, nil
+	case secp256r1Key:
+		p, err := utils.LoadBytes(path, secp256r1.PrivateKeyLen)
+		if err != nil {
+			return nil, err
+		}
+		pk := secp256r1.PrivateKey(p)
+		return &cli.PrivateKey{
+			Address: auth.NewSECP256R1Address(pk.PublicKey()),
+			Bytes:   p,
+		}, nil
+	default:
+		return nil, ErrInvalidKeyType
+	}
+}
+
+var genKeyCmd = &cobra.Command{
+	Use: "generate",
+	RunE: func(*cobra.Command, []string) error {
+		priv, err := generatePrivateKey(keyType)
+		if err != nil {
+			return err
 		}
 		if err := handler.h.StoreKey(priv); err != nil {
 			return err
@@ -47,7 +101,8 @@ var genKeyCmd = &cobra.Command{
 			return err
 		}
 		utils.Outf(
-			"{{green}}created address:{{/}} %s",
+			"{{green}}created %s address:{{/}} %s",
+			keyType,
 			codec.MustAddressBech32(tconsts.HRP, priv.Address),
 		)
 		return nil
@@ -63,15 +118,10 @@ var importKeyCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(_ *cobra.Command, args []string) error {
-		p, err := utils.LoadBytes(args[0], ed25519.PrivateKeyLen)
+		priv, err := loadPrivateKey(keyType, args[0])
 		if err != nil {
 			return err
 		}
-		pk := ed25519.PrivateKey(p)
-		priv := &cli.PrivateKey{
-			Address: auth.NewED25519Address(pk.PublicKey()),
-			Bytes:   p,
-		}
 		if err := handler.h.StoreKey(priv); err != nil {
 			return err
 		}
@@ -79,7 +129,8 @@ var importKeyCmd = &cobra.Command{
 			return err
 		}
 		utils.Outf(
-			"{{green}}imported address:{{/}} %s",
+			"{{green}}imported %s address:{{/}} %s",
+			keyType,
 			codec.MustAddressBech32(tconsts.HRP, priv.Address),
 		)
 		return nil
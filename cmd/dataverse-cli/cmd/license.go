@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	"dataverse/consts"
+	"dataverse/storage"
+	"encoding/hex"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var offerLicenseCmd = &cobra.Command{
+	Use: "offer-license",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}0:{{/}} %s {{yellow}}1:{{/}} %s\n", storage.LicenseDataset, storage.LicenseMachine)
+		kind, err := handler.Root().PromptChoice("License Kind", 2)
+		if err != nil {
+			return err
+		}
+		subject, err := handler.Root().PromptID("Notarize Data or Attest Machine txid")
+		if err != nil {
+			return err
+		}
+		assetID, err := handler.Root().PromptAsset("price assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, _, _, err := handler.GetAssetInfo(ctx, tcli, codec.EmptyAddress, assetID, false)
+		if err != nil {
+			return err
+		}
+		price, err := handler.Root().PromptAmount("price per period", decimals, hconsts.MaxUint64, nil)
+		if err != nil {
+			return err
+		}
+		hours, err := handler.Root().PromptInt("period (hours)", hconsts.MaxInt)
+		if err != nil {
+			return err
+		}
+		termsPath, err := handler.Root().PromptString("Terms File Path", 1, 500)
+		if err != nil {
+			return err
+		}
+		termsHash, _, err := CalculateSHA256(termsPath)
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}terms hash:{{/}} %s\n", hex.EncodeToString(termsHash))
+		return confirmAndSend(ctx, &actions.OfferLicense{
+			Kind:      storage.LicenseKind(kind),
+			Subject:   subject,
+			Asset:     assetID,
+			Price:     price,
+			Period:    (time.Duration(hours) * time.Hour).Milliseconds(),
+			TermsHash: termsHash,
+		})
+	},
+}
+
+var purchaseLicenseCmd = &cobra.Command{
+	Use: "purchase-license",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		offerID, err := handler.Root().PromptID("License Offer txid")
+		if err != nil {
+			return err
+		}
+		offer, err := tcli.LicenseOffer(ctx, offerID)
+		if err != nil {
+			return err
+		}
+		if offer.Closed {
+			utils.Outf("{{red}}license offer is closed{{/}}\n")
+			return nil
+		}
+		licensor, err := codec.ParseAddressBech32(consts.HRP, offer.Licensor)
+		if err != nil {
+			return err
+		}
+		symbol, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, offer.Asset, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}%s:{{/}} %s {{yellow}}licensor:{{/}} %s {{yellow}}price:{{/}} %s %s {{yellow}}per:{{/}} %s {{yellow}}terms hash:{{/}} %s\n",
+			offer.Kind,
+			offer.Subject,
+			offer.Licensor,
+			utils.FormatBalance(offer.Price, decimals),
+			symbol,
+			time.Duration(offer.Period)*time.Millisecond,
+			hex.EncodeToString(offer.TermsHash),
+		)
+		periods, err := handler.Root().PromptInt("periods", hconsts.MaxInt)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(ctx, &actions.PurchaseLicense{
+			Offer:    offerID,
+			Subject:  offer.Subject,
+			Licensor: licensor,
+			Asset:    offer.Asset,
+			Price:    offer.Price,
+			Periods:  uint64(periods),
+		})
+	},
+}
+
+var withdrawLicenseOfferCmd = &cobra.Command{
+	Use: "withdraw-license-offer",
+	RunE: func(*cobra.Command, []string) error {
+		offer, err := handler.Root().PromptID("License Offer txid")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.WithdrawLicenseOffer{
+			Offer: offer,
+		})
+	},
+}
+
+var getLicenseCmd = &cobra.Command{
+	Use: "get-license",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		subject, err := handler.Root().PromptID("Notarize Data or Attest Machine txid")
+		if err != nil {
+			return err
+		}
+		licensee, err := handler.Root().PromptAddress("Licensee Address")
+		if err != nil {
+			return err
+		}
+		license, err := tcli.License(ctx, subject, codec.MustAddressBech32(consts.HRP, licensee))
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}offer:{{/}} %s {{yellow}}kind:{{/}} %s {{yellow}}licensor:{{/}} %s {{yellow}}expiry:{{/}} %s {{yellow}}terms hash:{{/}} %s\n",
+			license.Offer,
+			license.Kind,
+			license.Licensor,
+			time.UnixMilli(license.Expiry),
+			hex.EncodeToString(license.TermsHash),
+		)
+		return nil
+	},
+}
+
+var hasAccessCmd = &cobra.Command{
+	Use: "has-access",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		subject, err := handler.Root().PromptID("Notarize Data or Attest Machine txid")
+		if err != nil {
+			return err
+		}
+		licensee, err := handler.Root().PromptAddress("Licensee Address")
+		if err != nil {
+			return err
+		}
+		access, err := tcli.HasAccess(ctx, subject, codec.MustAddressBech32(consts.HRP, licensee))
+		if err != nil {
+			return err
+		}
+		if !access.HasAccess {
+			utils.Outf("{{red}}no access{{/}} {{yellow}}as of:{{/}} %s\n", time.UnixMilli(access.Timestamp))
+			return nil
+		}
+		utils.Outf(
+			"{{green}}access granted{{/}} {{yellow}}license:{{/}} %s {{yellow}}expiry:{{/}} %s {{yellow}}as of:{{/}} %s\n",
+			access.License,
+			time.UnixMilli(access.Expiry),
+			time.UnixMilli(access.Timestamp),
+		)
+		return nil
+	},
+}
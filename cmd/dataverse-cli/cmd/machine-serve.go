@@ -3,37 +3,36 @@ package cmd
 import (
 	"context"
 	"dataverse/actions"
+	"dataverse/consts"
+	"dataverse/gateway"
+	trpc "dataverse/rpc"
+	"dataverse/storage"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/ipfs/go-cid"
-	"github.com/spf13/cobra"
-	"gorm.io/driver/sqlite"
+	"github.com/ava-labs/hypersdk/codec"
 	"gorm.io/gorm"
-
-	"github.com/multiformats/go-multibase"
-	mh "github.com/multiformats/go-multihash"
+	"gorm.io/gorm/clause"
 )
 
-type RegisteredMachine struct {
-	ID         uint   `gorm:"primaryKey;autoIncrement"`
-	MachineCID string `gorm:"unique"`
-	Txid       string
-}
-
-type AttestedMachine struct {
+// MachineEndpoint is a machine attested through the gateway. Its
+// attestation is on chain, [Endpoint] is the host (and port) the gateway
+// reaches the machine at to push updates, empty if it can't.
+type MachineEndpoint struct {
 	ID             uint   `gorm:"primaryKey;autoIncrement"`
 	MachineAddress string `gorm:"unique"`
-	Txid           string
+	Endpoint       string
 }
 
-type NotarizeData struct {
-	ID      uint `gorm:"primaryKey;autoIncrement"`
-	Owner   string
-	DataCid string
-	Txid    string `gorm:"unique"`
+// TableName keeps the machines indexed when the table also held their
+// attestation txid, the column is no longer read.
+func (MachineEndpoint) TableName() string {
+	return "attested_machines"
 }
 
 var DB *gorm.DB
@@ -42,40 +41,55 @@ func RegisterMachineCID(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, factory, cli, scli, tcli, _ := handler.DefaultActor()
+		_, _, factory, cli, scli, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		machineCID := r.URL.Query().Get("machinecid")
 
-		var registerMachine RegisteredMachine
-		machinecid := r.URL.Query().Get("machinecid")
-		result := DB.Where("machine_c_id = ?", machinecid)
-
-		fmt.Println(result.RowsAffected > 0, registerMachine.MachineCID, machineCID)
+		// The machine proves possession of its key by signing
+		// [actions.RegisterMachineMessage] with it.
+		keyType, ok := machineKeyTypeIDs[r.URL.Query().Get("keytype")]
+		if !ok {
+			gateway.Error(w, "Invalid key type", http.StatusBadRequest)
+			return
+		}
+		machineKey, err := hex.DecodeString(r.URL.Query().Get("machinekey"))
+		if err != nil {
+			gateway.Error(w, "Invalid machine key", http.StatusBadRequest)
+			return
+		}
+		signature, err := hex.DecodeString(r.URL.Query().Get("signature"))
+		if err != nil {
+			gateway.Error(w, "Invalid signature", http.StatusBadRequest)
+			return
+		}
 
-		if result.Error != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			http.Error(w, "Machine Already exists", http.StatusBadRequest)
+		// Uniqueness is enforced on-chain, this only saves a failed tx.
+		if _, err := tcli.MachineRegistration(ctx, machineCID); err == nil {
+			gateway.Error(w, "Machine Already exists", http.StatusBadRequest)
 			return
 		}
 
 		project := &actions.RegisterMachine{
 			MachineCID: []byte(machineCID),
+			KeyType:    keyType,
+			MachineKey: machineKey,
+			Signature:  signature,
 		}
 
 		// Generate transaction
 		te, tx, _ := sendAndWait(ctx, nil, project, cli, scli, tcli, factory, true)
 
 		if !te {
-			http.Error(w, "Tx failed", http.StatusBadRequest)
+			gateway.Error(w, "Tx failed", http.StatusBadRequest)
 			return
 		}
 
-		registerMahcine := RegisteredMachine{MachineCID: machineCID, Txid: tx.String()}
-		DB.Create(&registerMahcine)
-		DB.Commit()
-
 		response := map[string]interface{}{
-			"MachineRegisterTx": trimNullChars(tx.String()),
+			"MachineRegisterTx": tx.String(),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -90,19 +104,23 @@ func GetregisterMachineCID(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		var registerMachine RegisteredMachine
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		machinecid := r.URL.Query().Get("machinecid")
-		result := DB.Where("machine_c_id = ?", machinecid).First(&registerMachine)
-		if result.Error != nil {
-			w.WriteHeader(http.StatusNotFound)
-			http.Error(w, "Machine not registered", http.StatusBadRequest)
+		registration, err := tcli.MachineRegistration(ctx, machinecid)
+		if err != nil {
+			gateway.Error(w, "Machine not registered", http.StatusNotFound)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(registerMachine.Txid)
+		json.NewEncoder(w).Encode(registration.Tx.String())
 	}
 
 }
@@ -112,42 +130,82 @@ type AttestedMachineInfo struct {
 	MachineManufacturer string `json:"machine_manufacturer"`
 	MachineCID          string `json:"machine_cid"`
 	MachineCategory     string `json:"machine_category"`
+
+	// [Metadata] maps the fields of the metadata schema of the category to
+	// their value.
+	Metadata json.RawMessage `json:"metadata"`
+	Endpoint string          `json:"endpoint"`
 }
 
 func AttestMachine(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, factory, cli, scli, tcli, _ := handler.DefaultActor()
+		_, _, factory, cli, scli, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		body, err := io.ReadAll(r.Body)
 
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			gateway.Error(w, "Error reading request body", http.StatusBadRequest)
 			return
 		}
 
 		var attestMachine AttestedMachineInfo
 		if err := json.Unmarshal(body, &attestMachine); err != nil {
-			http.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
 			return
 		}
 
-		// check if tx exists onchain
-		var exists bool
-		var registerMachine RegisteredMachine
-		_ = DB.Model(registerMachine).Select("count(*) > 0").Where("machine_c_id = ?", attestMachine.MachineCID).Find(&exists).Error
+		// check if the machine is registered onchain
+		if _, err := tcli.MachineRegistration(ctx, attestMachine.MachineCID); err != nil {
+			gateway.Error(w, "Machine not registered", http.StatusBadRequest)
+			return
+		}
 
-		if !exists {
-			http.Error(w, "Machine not registered", http.StatusBadRequest)
+		machineAddress, err := codec.ParseAddressBech32(consts.HRP, attestMachine.MachineAddress)
+		if err != nil {
+			gateway.Error(w, "Invalid machine address", http.StatusBadRequest)
+			return
+		}
+		if len(attestMachine.Endpoint) > 0 && !validDeviceEndpoint(attestMachine.Endpoint) {
+			gateway.Error(w, "Invalid machine endpoint", http.StatusBadRequest)
+			return
+		}
+
+		schema, err := tcli.MetadataSchema(ctx, attestMachine.MachineCategory)
+		if err != nil {
+			gateway.Error(w, "No metadata schema for the machine category", http.StatusBadRequest)
+			return
+		}
+		var metadata storage.Metadata
+		schemaData := storage.MetadataSchemaData{Fields: schema.Fields}
+		if len(attestMachine.Metadata) > 0 {
+			metadata, err = schemaData.ParseMetadata(attestMachine.Metadata)
+		} else {
+			err = schemaData.Validate(nil)
+		}
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		previous, err := latestAttestation(ctx, tcli, machineAddress)
+		if err != nil {
+			gateway.Error(w, "Cannot load the machine attestation", http.StatusInternalServerError)
 			return
 		}
 
 		project := &actions.AttestMachine{
-			MachineAddress:      []byte(attestMachine.MachineAddress),
+			MachineAddress:      machineAddress,
 			MachineCategory:     []byte(attestMachine.MachineCategory),
 			MachineManufacturer: []byte(attestMachine.MachineManufacturer),
 			MachineCID:          []byte(attestMachine.MachineCID),
+			Metadata:            metadata,
+			PreviousAttestTx:    previous,
 		}
 
 		// Generate transaction
@@ -155,12 +213,18 @@ func AttestMachine(ctx context.Context) http.HandlerFunc {
 
 		response := ""
 		if err != nil {
-			http.Error(w, "Error while Attestation", http.StatusInternalServerError)
+			gateway.Error(w, "Error while Attestation", http.StatusInternalServerError)
+			return
 		}
 
-		registerMahcine := AttestedMachine{MachineAddress: string(project.MachineAddress), Txid: id.String()}
-		DB.Create(&registerMahcine)
-		DB.Commit()
+		endpoint := MachineEndpoint{
+			MachineAddress: attestMachine.MachineAddress,
+			Endpoint:       attestMachine.Endpoint,
+		}
+		DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "machine_address"}},
+			DoUpdates: clause.AssignmentColumns([]string{"endpoint"}),
+		}).Create(&endpoint)
 
 		response = id.String()
 
@@ -183,40 +247,37 @@ func NotarizeDataView(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, factory, cli, scli, tcli, _ := handler.DefaultActor()
+		_, _, factory, cli, scli, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		body, err := io.ReadAll(r.Body)
 
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			gateway.Error(w, "Error reading request body", http.StatusBadRequest)
 			return
 		}
 
 		var attestMachine NotarizeDataArgs
 		if err := json.Unmarshal(body, &attestMachine); err != nil {
-			http.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
 			return
 		}
 
-		// check if tx exists onchain
-		// var exists bool
-		var attestedMachineDB AttestedMachine
-		// _ = DB.Model(attestedMachineDB).Select("count(*) > 0").Where("machine_address = ?", attestMachine.Owner).Find(&exists).Error
-		// fmt.Println(exists)
-		// if !exists {
-		// 	http.Error(w, "Machine not registered", http.StatusBadRequest)
-		// 	return
-		// }
-
-		_ = DB.First(&attestedMachineDB, "machine_address = ?", attestMachine.Owner)
-
-		fmt.Println(attestedMachineDB.Txid, attestMachine.Owner)
+		attestation, err := tcli.MachineAttestation(ctx, attestMachine.Owner)
+		if err != nil {
+			gateway.Error(w, "Machine not attested", http.StatusBadRequest)
+			return
+		}
 
+		// The transaction must be signed by the attested machine, so the
+		// default key of this server has to be the machine key.
 		notarizedata := &actions.NotarizeData{
-			MachineAttestTx: []byte("plmnt1qh05ghszrxfh8taksfqhvyfgewleq6u5ru9xlg"),
+			MachineAttestTx: attestation.Tx,
 			DataCID:         []byte(attestMachine.DataCid),
 			DataType:        []byte("/dataverse.asset.MsgNotarizedAsset"),
-			DataOwnerAddr:   []byte(attestMachine.Owner),
 		}
 
 		// Generate transaction
@@ -225,14 +286,10 @@ func NotarizeDataView(ctx context.Context) http.HandlerFunc {
 		response := ""
 		if err != nil {
 			fmt.Println(err)
-			http.Error(w, "Error while Attestation", http.StatusInternalServerError)
+			gateway.Error(w, "Error while Attestation", http.StatusInternalServerError)
 			return
 		}
 
-		registerMahcine := NotarizeData{DataCid: attestMachine.DataCid, Owner: attestMachine.Owner, Txid: id.String()}
-		DB.Create(&registerMahcine)
-		DB.Commit()
-
 		response = id.String()
 
 		w.Header().Set("Content-Type", "application/json")
@@ -244,6 +301,47 @@ func NotarizeDataView(ctx context.Context) http.HandlerFunc {
 
 }
 
+func MachineNotarizationsView(ctx context.Context) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Error loading default key", http.StatusInternalServerError)
+			return
+		}
+
+		machine := r.URL.Query().Get("machine_address")
+
+		var cursor []byte
+		if c := r.URL.Query().Get("cursor"); c != "" {
+			cursor, err = hex.DecodeString(c)
+			if err != nil {
+				gateway.Error(w, "Invalid cursor", http.StatusBadRequest)
+				return
+			}
+		}
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		notarizations, next, err := tcli.NotarizationsByMachine(ctx, machine, cursor, limit)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]interface{}{
+			"notarizations": notarizations,
+			"cursor":        hex.EncodeToString(next),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	}
+
+}
+
 type VerifyNotarizeDataArgs struct {
 	Owner string `json:"machine_address"`
 	Data  string `json:"data"`
@@ -256,29 +354,35 @@ func VerifyNotarizeDataView(ctx context.Context) http.HandlerFunc {
 		body, err := io.ReadAll(r.Body)
 
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			gateway.Error(w, "Error reading request body", http.StatusBadRequest)
 			return
 		}
 
 		var verifyArgs VerifyNotarizeDataArgs
 		if err := json.Unmarshal(body, &verifyArgs); err != nil {
-			http.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
 			return
 		}
 
-		bytes := []byte(verifyArgs.Data)
-		hash, _ := mh.Sum(bytes, mh.SHA2_256, -1)
-
-		c := cid.NewCidV1(cid.Raw, hash)
-		encodedCID, _ := c.StringOfBase(multibase.Base32)
-
-		fmt.Println(encodedCID)
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Error loading default key", http.StatusInternalServerError)
+			return
+		}
 
-		var exists bool
-		var notarizeData NotarizeData
-		_ = DB.Model(&notarizeData).Select("count(*) > 0").Where("data_cid = ?", encodedCID).Find(&exists).Error
+		// Provenance is checked against the chain so the answer does not
+		// depend on this server's state.
+		dataCID, notarizations, err := tcli.VerifyData(ctx, []byte(verifyArgs.Data), "")
+		if err != nil && !strings.Contains(err.Error(), trpc.ErrDataNotNotarized.Error()) {
+			gateway.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		response := exists
+		response := map[string]interface{}{
+			"notarized":     len(notarizations) > 0,
+			"cid":           dataCID,
+			"notarizations": notarizations,
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 
@@ -288,37 +392,3 @@ func VerifyNotarizeDataView(ctx context.Context) http.HandlerFunc {
 	}
 
 }
-
-var serverDataverseCmd = &cobra.Command{
-	Use: "serveDataverse",
-	RunE: func(*cobra.Command, []string) error {
-
-		ctx := context.Background()
-
-		db, err := gorm.Open(sqlite.Open("index.db"), &gorm.Config{})
-		if err != nil {
-			fmt.Println("failed to connect database:", err)
-		}
-		DB = db
-		DB.AutoMigrate(&RegisteredMachine{})
-		DB.AutoMigrate(&AttestedMachine{})
-		DB.AutoMigrate(&NotarizeData{})
-
-		http.HandleFunc("/register-machine", RegisterMachineCID(ctx)) //machinecid
-		http.HandleFunc("/get-register-machine", GetregisterMachineCID(ctx))
-		http.HandleFunc("/attest-machine", AttestMachine(ctx))
-		http.HandleFunc("/notarize-data", NotarizeDataView(ctx))
-		http.HandleFunc("/verify", VerifyNotarizeDataView(ctx))
-
-		// Start the HTTP server on port 8080
-		fmt.Println("Server is listening on port 8080...")
-		// err_http := http.ListenAndServe(":8080", nil)
-		// fmt.Println("Server Ended")
-
-		// if err_http != nil {
-		// 	return err_http
-		// }
-
-		return http.ListenAndServe(":8080", nil)
-	},
-}
@@ -3,12 +3,19 @@ package cmd
 import (
 	"context"
 	"dataverse/actions"
+	"dataverse/auth"
 	"dataverse/consts"
+	trpc "dataverse/rpc"
 	"dataverse/storage"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +26,81 @@ var machineCmd = &cobra.Command{
 	},
 }
 
+// machineKeyTypes are the key types a machine can prove possession of.
+var machineKeyTypes = []string{ed25519Key, secp256r1Key}
+
+var machineKeyTypeIDs = map[string]uint8{
+	ed25519Key:   auth.ED25519Key,
+	secp256r1Key: auth.SECP256R1Key,
+}
+
+// latestAttestation returns the txID of the latest attestation of [machine],
+// empty if it was never attested.
+func latestAttestation(ctx context.Context, tcli *trpc.JSONRPCClient, machine codec.Address) (ids.ID, error) {
+	attestation, err := tcli.MachineAttestation(ctx, codec.MustAddressBech32(consts.HRP, machine))
+	if err != nil {
+		if strings.Contains(err.Error(), trpc.ErrAttestMachineNotFound.Error()) {
+			return ids.Empty, nil
+		}
+		return ids.Empty, err
+	}
+	return attestation.Tx, nil
+}
+
+// promptMachineKeyProof returns the machine key and its signature over the
+// registration of [machineCID] on [chainID]. The signature is either made
+// here with the machine key file or pasted from the machine.
+func promptMachineKeyProof(chainID ids.ID, machineCID string) (uint8, []byte, []byte, error) {
+	choice, err := handler.Root().PromptChoice("machine key type (0: ed25519, 1: secp256r1)", len(machineKeyTypes))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	name := machineKeyTypes[choice]
+	keyType := machineKeyTypeIDs[name]
+	msg := actions.RegisterMachineMessage(chainID, []byte(machineCID))
+	utils.Outf("{{yellow}}registration message:{{/}} %s\n", hex.EncodeToString(msg))
+
+	local, err := handler.Root().PromptBool("sign with machine key file")
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if local {
+		path, err := handler.Root().PromptString("machine key path", 1, 500)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		priv, err := loadPrivateKey(name, path)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		key, sig, err := auth.SignMachineKey(keyType, priv.Bytes, msg)
+		return keyType, key, sig, err
+	}
+	keyHex, err := handler.Root().PromptString("machine public key (hex)", 1, auth.MaxMachineKeyLen*2)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	sigHex, err := handler.Root().PromptString("signature (hex)", 1, auth.MaxMachineSignatureLen*2)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return keyType, key, sig, nil
+}
+
 var registerMachineCID = &cobra.Command{
 	Use: "register-machine",
 	RunE: func(*cobra.Command, []string) error {
 
 		ctx := context.Background()
-		_, _, factory, cli, scli, tcli, err := handler.DefaultActor()
+		chainID, _, factory, cli, scli, tcli, err := handler.DefaultActor()
 		if err != nil {
 			return err
 		}
@@ -33,6 +109,10 @@ var registerMachineCID = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		keyType, key, sig, err := promptMachineKeyProof(chainID, machineCID)
+		if err != nil {
+			return err
+		}
 
 		// Confirm action
 		cont, err := handler.Root().PromptContinue()
@@ -42,6 +122,9 @@ var registerMachineCID = &cobra.Command{
 
 		project := &actions.RegisterMachine{
 			MachineCID: []byte(machineCID),
+			KeyType:    keyType,
+			MachineKey: key,
+			Signature:  sig,
 		}
 
 		// Generate transaction
@@ -96,7 +179,7 @@ var attestMachine = &cobra.Command{
 			return err
 		}
 
-		address, err := handler.Root().PromptString("Machine Address", 44, 44)
+		address, err := handler.Root().PromptAddress("Machine Address")
 		if err != nil {
 			return err
 		}
@@ -116,6 +199,16 @@ var attestMachine = &cobra.Command{
 			return err
 		}
 
+		metadata, err := promptMetadata(ctx, tcli, machine_category)
+		if err != nil {
+			return err
+		}
+
+		previous, err := latestAttestation(ctx, tcli, address)
+		if err != nil {
+			return err
+		}
+
 		// Confirm action
 		cont, err := handler.Root().PromptContinue()
 		if !cont || err != nil {
@@ -123,10 +216,12 @@ var attestMachine = &cobra.Command{
 		}
 
 		project := &actions.AttestMachine{
-			MachineAddress:      []byte(address),
+			MachineAddress:      address,
 			MachineCategory:     []byte(machine_category),
 			MachineManufacturer: []byte(machine_manufacturer),
 			MachineCID:          []byte(machineCID),
+			Metadata:            metadata,
+			PreviousAttestTx:    previous,
 		}
 
 		// Generate transaction
@@ -155,15 +250,19 @@ var getAttestedachineCID = &cobra.Command{
 
 		id, _ := handler.Root().PromptID("attestation txid")
 
-		ID, MachineAddress, MachineCategory, MachineManufacturer, MachineCID, err := tcli.AttestMachine(ctx, id, false)
+		machine, err := tcli.AttestMachine(ctx, id)
 
 		if err != nil {
 			return err
 		}
 
-		addr, err := codec.AddressBech32(consts.HRP, codec.Address(ID))
+		addr, err := codec.AddressBech32(consts.HRP, codec.Address(machine.ID))
+		if err != nil {
+			return err
+		}
+		metadata, err := json.Marshal(machine.Metadata)
 
-		fmt.Println("ID", addr, ", MachineAddress: ", string(MachineAddress), ", MachineCategory: ", string(MachineCategory), ", MachineManufacturer: ", string(MachineManufacturer), ", MachineCID: ", string(MachineCID))
+		fmt.Println("ID", addr, ", MachineAddress: ", machine.MachineAddress, ", MachineCategory: ", string(machine.MachineCategory), ", MachineManufacturer: ", string(machine.MachineManufacturer), ", MachineCID: ", string(machine.MachineCID), ", Attester: ", machine.Attester, ", Status: ", machine.Status, ", Metadata: ", string(metadata))
 
 		return err
 
@@ -185,11 +284,6 @@ var notarizeData = &cobra.Command{
 			return err
 		}
 
-		creator, err := handler.Root().PromptString("Machine Address", 44, 44)
-		if err != nil {
-			return err
-		}
-
 		notarizeType := "/dataverse.asset.MsgNotarizedAsset"
 
 		dataCid, err := handler.Root().PromptString("Data CID", 59, 59)
@@ -204,10 +298,9 @@ var notarizeData = &cobra.Command{
 		}
 
 		project := &actions.NotarizeData{
-			MachineAttestTx: storage.NotarizeDataKey(attestationTx),
+			MachineAttestTx: attestationTx,
 			DataCID:         []byte(dataCid),
 			DataType:        []byte(notarizeType),
-			DataOwnerAddr:   []byte(creator),
 		}
 
 		// Generate transaction
@@ -236,7 +329,7 @@ var getNotarizeData = &cobra.Command{
 
 		id, _ := handler.Root().PromptID("notarized txid")
 
-		ID, MachineAttestTx, DataOwnerAddr, DataCID, DataType, err := tcli.NotarizeData(ctx, id, false)
+		ID, MachineAttestTx, DataOwnerAddr, DataCID, DataType, AfterRevocation, err := tcli.NotarizeData(ctx, id, false)
 
 		if err != nil {
 			return err
@@ -244,9 +337,134 @@ var getNotarizeData = &cobra.Command{
 
 		addr, err := codec.AddressBech32(consts.HRP, codec.Address(ID))
 
-		fmt.Println("ID", addr, ", MachineAttestTx: ", ids.ID(MachineAttestTx), ", DataCID: ", string(DataCID), ", DataType: ", string(DataType), ", DataOwnerAddr: ", string(DataOwnerAddr))
+		fmt.Println("ID", addr, ", MachineAttestTx: ", MachineAttestTx, ", DataCID: ", string(DataCID), ", DataType: ", string(DataType), ", DataOwnerAddr: ", DataOwnerAddr, ", AfterRevocation: ", AfterRevocation)
+
+		return err
+
+	},
+}
+
+var reportUpdateResult = &cobra.Command{
+	Use: "report-update",
+	RunE: func(*cobra.Command, []string) error {
+
+		ctx := context.Background()
+		_, _, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		updateTx, err := handler.Root().PromptID("update txid")
+		if err != nil {
+			return err
+		}
+
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+
+		success, err := handler.Root().PromptBool("update installed successfully")
+		if err != nil {
+			return err
+		}
+
+		installedHashStr, err := handler.Root().PromptString("Installed SHA-256 (hex)", 0, actions.UpdateExecutableHashUnits)
+		if err != nil {
+			return err
+		}
+		installedHash, err := hex.DecodeString(installedHashStr)
+		if err != nil {
+			return err
+		}
+
+		installedVersionStr, err := handler.Root().PromptString("Installed Version (major.minor.patch)", 5, 32)
+		if err != nil {
+			return err
+		}
+		installedVersion, err := storage.ParseVersion(installedVersionStr)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		report := &actions.ReportUpdateResult{
+			UpdateTx:         updateTx,
+			MachineAttestTx:  attestationTx,
+			Success:          success,
+			InstalledHash:    installedHash,
+			InstalledVersion: installedVersion,
+		}
+
+		// Generate transaction
+		te, _, err := sendAndWait(ctx, nil, report, cli, scli, tcli, factory, true)
+
+		if err != nil {
+			fmt.Println("Error occured")
+		}
+
+		fmt.Println(te)
 
 		return err
 
 	},
 }
+
+var revokeMachine = &cobra.Command{
+	Use: "revoke-machine",
+	RunE: func(*cobra.Command, []string) error {
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+
+		// Data notarized since the compromise is flagged in RPC responses
+		var compromisedSince int
+		backdate, err := handler.Root().PromptBool("machine was compromised before now")
+		if err != nil {
+			return err
+		}
+		if backdate {
+			compromisedSince, err = handler.Root().PromptInt("compromised since (unix ms)", hconsts.MaxInt)
+			if err != nil {
+				return err
+			}
+		}
+
+		return confirmAndSend(context.Background(), &actions.RevokeMachine{
+			MachineAttestTx:  attestationTx,
+			CompromisedSince: int64(compromisedSince),
+		})
+	},
+}
+
+var suspendMachine = &cobra.Command{
+	Use: "suspend-machine",
+	RunE: func(*cobra.Command, []string) error {
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.SuspendMachine{
+			MachineAttestTx: attestationTx,
+		})
+	},
+}
+
+var reinstateMachine = &cobra.Command{
+	Use: "reinstate-machine",
+	RunE: func(*cobra.Command, []string) error {
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.ReinstateMachine{
+			MachineAttestTx: attestationTx,
+		})
+	},
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	"strings"
+
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var createManufacturerCmd = &cobra.Command{
+	Use: "create-manufacturer",
+	RunE: func(*cobra.Command, []string) error {
+		name, err := handler.Root().PromptString("Manufacturer", 1, actions.MachineManufacturerUnits)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.CreateManufacturer{
+			Name: []byte(name),
+		})
+	},
+}
+
+var addManufacturerAttesterCmd = &cobra.Command{
+	Use: "add-manufacturer-attester",
+	RunE: func(*cobra.Command, []string) error {
+		name, err := handler.Root().PromptString("Manufacturer", 1, actions.MachineManufacturerUnits)
+		if err != nil {
+			return err
+		}
+		attester, err := handler.Root().PromptAddress("Attester")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.AddManufacturerAttester{
+			Manufacturer: []byte(name),
+			Attester:     attester,
+		})
+	},
+}
+
+var revokeManufacturerAttesterCmd = &cobra.Command{
+	Use: "revoke-manufacturer-attester",
+	RunE: func(*cobra.Command, []string) error {
+		name, err := handler.Root().PromptString("Manufacturer", 1, actions.MachineManufacturerUnits)
+		if err != nil {
+			return err
+		}
+		attester, err := handler.Root().PromptAddress("Attester")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.RevokeManufacturerAttester{
+			Manufacturer: []byte(name),
+			Attester:     attester,
+		})
+	},
+}
+
+var getManufacturerCmd = &cobra.Command{
+	Use: "get-manufacturer",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		name, err := handler.Root().PromptString("Manufacturer", 1, actions.MachineManufacturerUnits)
+		if err != nil {
+			return err
+		}
+		manufacturer, err := tcli.Manufacturer(ctx, name)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}manufacturer:{{/}} %s {{yellow}}owner:{{/}} %s {{yellow}}attesters:{{/}} [%s]\n",
+			manufacturer.Name,
+			manufacturer.Owner,
+			strings.Join(manufacturer.Attesters, ", "),
+		)
+		return nil
+	},
+}
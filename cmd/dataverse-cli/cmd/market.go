@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	"dataverse/auth"
+	"dataverse/consts"
+	"dataverse/content"
+	"encoding/hex"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var listDataCmd = &cobra.Command{
+	Use: "list-data",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		notarization, err := handler.Root().PromptID("Notarize Data txid")
+		if err != nil {
+			return err
+		}
+		assetID, err := handler.Root().PromptAsset("price assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, _, _, err := handler.GetAssetInfo(ctx, tcli, codec.EmptyAddress, assetID, false)
+		if err != nil {
+			return err
+		}
+		price, err := handler.Root().PromptAmount("price", decimals, hconsts.MaxUint64, nil)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(ctx, &actions.ListData{
+			Notarization: notarization,
+			Asset:        assetID,
+			Price:        price,
+		})
+	},
+}
+
+// sealingKey returns the ed25519 key content keys are sealed to for the
+// default key: the key itself if it is a local ed25519 key, otherwise one
+// loaded from a key file.
+func sealingKey(priv *cli.PrivateKey) (ed25519.PrivateKey, error) {
+	if auth.IsED25519Address(priv.Address) && len(priv.Bytes) > 0 {
+		return ed25519.PrivateKey(priv.Bytes), nil
+	}
+	path, err := handler.Root().PromptString("sealing key path (ed25519)", 1, 500)
+	if err != nil {
+		return ed25519.EmptyPrivateKey, err
+	}
+	key, err := loadPrivateKey(ed25519Key, path)
+	if err != nil {
+		return ed25519.EmptyPrivateKey, err
+	}
+	return ed25519.PrivateKey(key.Bytes), nil
+}
+
+var purchaseDataCmd = &cobra.Command{
+	Use: "purchase-data",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		listingID, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		listing, err := tcli.Listing(ctx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.Closed {
+			utils.Outf("{{red}}listing is closed{{/}}\n")
+			return nil
+		}
+		seller, err := codec.ParseAddressBech32(consts.HRP, listing.Seller)
+		if err != nil {
+			return err
+		}
+		symbol, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, listing.Asset, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+		if balance < listing.Price {
+			utils.Outf("{{red}}insufficient balance to pay %s %s{{/}}\n", utils.FormatBalance(listing.Price, decimals), symbol)
+			return nil
+		}
+		utils.Outf(
+			"{{yellow}}seller:{{/}} %s {{yellow}}price:{{/}} %s %s\n",
+			listing.Seller,
+			utils.FormatBalance(listing.Price, decimals),
+			symbol,
+		)
+		key, err := sealingKey(priv)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(ctx, &actions.PurchaseData{
+			Listing:  listingID,
+			Seller:   seller,
+			Asset:    listing.Asset,
+			Price:    listing.Price,
+			BuyerKey: key.PublicKey(),
+		})
+	},
+}
+
+var delistDataCmd = &cobra.Command{
+	Use: "delist-data",
+	RunE: func(*cobra.Command, []string) error {
+		listing, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.DelistData{
+			Listing: listing,
+		})
+	},
+}
+
+var getListingCmd = &cobra.Command{
+	Use: "get-listing",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		listingID, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		listing, err := tcli.Listing(ctx, listingID)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}notarization:{{/}} %s {{yellow}}seller:{{/}} %s {{yellow}}asset:{{/}} %s {{yellow}}price:{{/}} %d {{yellow}}closed:{{/}} %t\n",
+			listing.Notarization,
+			listing.Seller,
+			listing.Asset,
+			listing.Price,
+			listing.Closed,
+		)
+		return nil
+	},
+}
+
+var getReceiptCmd = &cobra.Command{
+	Use: "get-receipt",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		listingID, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		buyer, err := handler.Root().PromptAddress("Buyer Address")
+		if err != nil {
+			return err
+		}
+		receipt, err := tcli.PurchaseReceipt(ctx, listingID, codec.MustAddressBech32(consts.HRP, buyer))
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}purchase tx:{{/}} %s {{yellow}}asset:{{/}} %s {{yellow}}price:{{/}} %d {{yellow}}timestamp:{{/}} %d {{yellow}}buyer key:{{/}} %s\n",
+			receipt.PurchaseTx,
+			receipt.Asset,
+			receipt.Price,
+			receipt.Timestamp,
+			hex.EncodeToString(receipt.BuyerKey),
+		)
+		return nil
+	},
+}
+
+var deliverDataKeyCmd = &cobra.Command{
+	Use: "deliver-data-key",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		listing, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		buyer, err := handler.Root().PromptAddress("Buyer Address")
+		if err != nil {
+			return err
+		}
+		receipt, err := tcli.PurchaseReceipt(ctx, listing, codec.MustAddressBech32(consts.HRP, buyer))
+		if err != nil {
+			return err
+		}
+		if len(receipt.BuyerKey) != ed25519.PublicKeyLen {
+			return ErrNoBuyerKey
+		}
+		keyHex, err := handler.Root().PromptString("Content Key (hex)", 2, content.MaxContentKeyLen*2)
+		if err != nil {
+			return err
+		}
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return err
+		}
+		envelope, err := content.SealKey(ed25519.PublicKey(receipt.BuyerKey), listing[:], key)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(ctx, &actions.DeliverDataKey{
+			Listing:  listing,
+			Buyer:    buyer,
+			Envelope: envelope,
+		})
+	},
+}
+
+var getDataKeyCmd = &cobra.Command{
+	Use: "get-data-key",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		listing, err := handler.Root().PromptID("Listing txid")
+		if err != nil {
+			return err
+		}
+		key, err := sealingKey(priv)
+		if err != nil {
+			return err
+		}
+		envelope, err := tcli.KeyEnvelope(ctx, listing, codec.MustAddressBech32(consts.HRP, priv.Address))
+		if err != nil {
+			return err
+		}
+		contentKey, err := content.OpenKey(key, listing[:], envelope.Envelope)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}content key:{{/}} %s {{yellow}}delivered in:{{/}} %s\n",
+			hex.EncodeToString(contentKey),
+			envelope.DeliverTx,
+		)
+		return nil
+	},
+}
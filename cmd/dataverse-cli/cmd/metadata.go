@@ -0,0 +1,103 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	trpc "dataverse/rpc"
+	"dataverse/storage"
+	"encoding/json"
+	"os"
+
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+// set-metadata-schema reads the schema fields from a JSON file, e.g.
+//
+//	[
+//	  {"name": "model", "type": "string", "required": true},
+//	  {"name": "firmware_version", "type": "version", "required": true},
+//	  {"name": "location", "type": "geohash"},
+//	  {"name": "sensors", "type": "sensors", "required": true},
+//	  {"name": "calibration_date", "type": "date"}
+//	]
+var setMetadataSchemaCmd = &cobra.Command{
+	Use: "set-metadata-schema [schema file]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		b, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		var fields []storage.MetadataField
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return err
+		}
+		if err := storage.ValidMetadataFields(fields); err != nil {
+			return err
+		}
+		category, err := handler.Root().PromptString("Machine Category", 1, actions.MachineCategoryUnits)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.SetMetadataSchema{
+			Category: []byte(category),
+			Fields:   fields,
+		})
+	},
+}
+
+var getMetadataSchemaCmd = &cobra.Command{
+	Use: "get-metadata-schema",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		category, err := handler.Root().PromptString("Machine Category", 1, actions.MachineCategoryUnits)
+		if err != nil {
+			return err
+		}
+		schema, err := tcli.MetadataSchema(ctx, category)
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}category:{{/}} %s {{yellow}}owner:{{/}} %s\n", schema.Category, schema.Owner)
+		for _, f := range schema.Fields {
+			utils.Outf("{{yellow}}%s:{{/}} %s required=%t\n", f.Name, f.Type, f.Required)
+		}
+		return nil
+	},
+}
+
+// promptMetadata reads the metadata of a machine of [category] from a JSON
+// file and checks it against the schema of the category, so it isn't only
+// rejected once the attestation is sent.
+func promptMetadata(ctx context.Context, tcli *trpc.JSONRPCClient, category string) (storage.Metadata, error) {
+	reply, err := tcli.MetadataSchema(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	schema := storage.MetadataSchemaData{Fields: reply.Fields}
+	path, err := handler.Root().PromptString("metadata file (empty for none)", 0, 500)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, schema.Validate(nil)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return schema.ParseMetadata(b)
+}
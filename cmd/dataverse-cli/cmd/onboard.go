@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+
+	"github.com/spf13/cobra"
+)
+
+var onboardMachineCmd = &cobra.Command{
+	Use: "onboard-machine",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		chainID, priv, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		machineCID, err := handler.Root().PromptString("Machine CID", 66, 66)
+		if err != nil {
+			return err
+		}
+		keyType, key, sig, err := promptMachineKeyProof(chainID, machineCID)
+		if err != nil {
+			return err
+		}
+		category, err := handler.Root().PromptString("Machine Category", 1, actions.MachineCategoryUnits)
+		if err != nil {
+			return err
+		}
+		manufacturer, err := handler.Root().PromptString("Machine Manufacturer", 1, actions.MachineManufacturerUnits)
+		if err != nil {
+			return err
+		}
+		metadata, err := promptMetadata(ctx, tcli, category)
+		if err != nil {
+			return err
+		}
+
+		action := &actions.OnboardMachine{
+			MachineCategory:     []byte(category),
+			MachineManufacturer: []byte(manufacturer),
+			MachineCID:          []byte(machineCID),
+			Metadata:            metadata,
+			KeyType:             keyType,
+			MachineKey:          key,
+			Signature:           sig,
+		}
+		fund, err := handler.Root().PromptBool("fund machine")
+		if err != nil {
+			return err
+		}
+		if fund {
+			assetID, err := handler.Root().PromptAsset("assetID", true)
+			if err != nil {
+				return err
+			}
+			_, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, assetID, true)
+			if balance == 0 || err != nil {
+				return err
+			}
+			amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+			if err != nil {
+				return err
+			}
+			action.Asset = assetID
+			action.Value = amount
+		}
+		return confirmAndSend(ctx, action)
+	},
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	"encoding/hex"
+	"os"
+
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var addMaintainerCmd = &cobra.Command{
+	Use: "add-maintainer",
+	RunE: func(*cobra.Command, []string) error {
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+		maintainer, err := handler.Root().PromptAddress("Maintainer Address")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.AddProjectMaintainer{
+			Project:    project,
+			Maintainer: maintainer,
+		})
+	},
+}
+
+var removeMaintainerCmd = &cobra.Command{
+	Use: "remove-maintainer",
+	RunE: func(*cobra.Command, []string) error {
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+		maintainer, err := handler.Root().PromptAddress("Maintainer Address")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.RemoveProjectMaintainer{
+			Project:    project,
+			Maintainer: maintainer,
+		})
+	},
+}
+
+var transferProjectCmd = &cobra.Command{
+	Use: "transfer-repository",
+	RunE: func(*cobra.Command, []string) error {
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+		owner, err := handler.Root().PromptAddress("New Owner Address")
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.TransferProjectOwnership{
+			Project:  project,
+			NewOwner: owner,
+		})
+	},
+}
+
+var updateProjectCmd = &cobra.Command{
+	Use: "update-repository",
+	RunE: func(*cobra.Command, []string) error {
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+		URL, err := handler.Root().PromptString("Project Logo URL", 1, actions.ProjectLogoUnits)
+		if err != nil {
+			return err
+		}
+		description, err := handler.Root().PromptString("Project Description", 1, actions.ProjectDescriptionUnits)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.UpdateProjectMetadata{
+			Project:            project,
+			ProjectDescription: []byte(description),
+			Logo:               []byte(URL),
+		})
+	},
+}
+
+var genReleaseKeyCmd = &cobra.Command{
+	Use: "gen-release-key [path]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		p, err := ed25519.GeneratePrivateKey()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[0], p[:], 0o600); err != nil {
+			return err
+		}
+		pub := p.PublicKey()
+		utils.Outf("{{green}}created release key:{{/}} %s\n", hex.EncodeToString(pub[:]))
+		return nil
+	},
+}
+
+var setReleaseKeyCmd = &cobra.Command{
+	Use: "set-release-key",
+	RunE: func(*cobra.Command, []string) error {
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+		path, err := handler.Root().PromptString("Release Key Path", 1, 500)
+		if err != nil {
+			return err
+		}
+		p, err := utils.LoadBytes(path, ed25519.PrivateKeyLen)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.SetProjectReleaseKey{
+			Project:    project,
+			ReleaseKey: ed25519.PrivateKey(p).PublicKey(),
+		})
+	},
+}
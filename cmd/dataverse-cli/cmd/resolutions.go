@@ -21,6 +21,28 @@ import (
 	"github.com/ava-labs/hypersdk/utils"
 )
 
+// confirmAndSend confirms and submits an action with the default key.
+func confirmAndSend(ctx context.Context, action chain.Action) error {
+	_, _, factory, cli, scli, tcli, err := handler.DefaultActor()
+	if err != nil {
+		return err
+	}
+
+	// Confirm action
+	cont, err := handler.Root().PromptContinue()
+	if !cont || err != nil {
+		return err
+	}
+
+	success, id, err := sendAndWait(ctx, nil, action, cli, scli, tcli, factory, true)
+	if err != nil {
+		return err
+	}
+	fmt.Println(id)
+	fmt.Println(success)
+	return nil
+}
+
 // sendAndWait may not be used concurrently
 func sendAndWait(
 	ctx context.Context, warpMsg *warp.Message, action chain.Action, cli *rpc.JSONRPCClient,
@@ -185,12 +207,107 @@ func handleTx(c *trpc.JSONRPCClient, tx *chain.Transaction, result *chain.Result
 			utils.Outf(summaryStr)
 
 		case *actions.AttestMachine:
-			summaryStr += fmt.Sprintf("New Machine attested with tx: %s for Machine address: %s", tx.ID(), action.MachineAddress)
+			summaryStr += fmt.Sprintf("New Machine attested with tx: %s for Machine address: %s", tx.ID(), codec.MustAddressBech32(tconsts.HRP, action.MachineAddress))
+			utils.Outf(summaryStr)
+
+		case *actions.OnboardMachine:
+			summaryStr += fmt.Sprintf("Machine %s onboarded with tx: %s for Machine CID: %s", codec.MustAddressBech32(tconsts.HRP, action.MachineAddress()), tx.ID(), action.MachineCID)
+			if action.Value > 0 {
+				summaryStr += fmt.Sprintf(" (funded with %d of asset %s)", action.Value, action.Asset)
+			}
 			utils.Outf(summaryStr)
 
 		case *actions.NotarizeData:
 			summaryStr += fmt.Sprintf("Data Notarized with tx: %s for Data CID: %s", tx.ID(), action.DataCID)
 			utils.Outf(summaryStr)
+
+		case *actions.NotarizeBatch:
+			summaryStr += fmt.Sprintf("Batch of %d data notarized with tx: %s root: %x", action.Count, tx.ID(), action.Root)
+			utils.Outf(summaryStr)
+
+		case *actions.CreateManufacturer:
+			summaryStr += fmt.Sprintf("Manufacturer %s created", action.Name)
+			utils.Outf(summaryStr)
+
+		case *actions.AddManufacturerAttester:
+			summaryStr += fmt.Sprintf("Attester %s added to manufacturer %s", codec.MustAddressBech32(tconsts.HRP, action.Attester), action.Manufacturer)
+			utils.Outf(summaryStr)
+
+		case *actions.RevokeManufacturerAttester:
+			summaryStr += fmt.Sprintf("Attester %s revoked from manufacturer %s", codec.MustAddressBech32(tconsts.HRP, action.Attester), action.Manufacturer)
+			utils.Outf(summaryStr)
+
+		case *actions.SetMetadataSchema:
+			summaryStr += fmt.Sprintf("Metadata schema of category %s set with %d fields", action.Category, len(action.Fields))
+			utils.Outf(summaryStr)
+
+		case *actions.SetSponsorship:
+			summaryStr += fmt.Sprintf("Fees of machine %s sponsored up to %s %s", action.MachineAttestTx, utils.FormatBalance(action.Cap, tconsts.Decimals), tconsts.Symbol)
+			utils.Outf(summaryStr)
+
+		case *actions.ReportUpdateResult:
+			summaryStr += fmt.Sprintf("Update %s reported by machine %s, success: %t", action.UpdateTx, action.MachineAttestTx, action.Success)
+			utils.Outf(summaryStr)
+
+		case *actions.AddProjectMaintainer:
+			summaryStr += fmt.Sprintf("Maintainer %s added to Project: %s", codec.MustAddressBech32(tconsts.HRP, action.Maintainer), action.Project)
+			utils.Outf(summaryStr)
+
+		case *actions.RemoveProjectMaintainer:
+			summaryStr += fmt.Sprintf("Maintainer %s removed from Project: %s", codec.MustAddressBech32(tconsts.HRP, action.Maintainer), action.Project)
+			utils.Outf(summaryStr)
+
+		case *actions.TransferProjectOwnership:
+			summaryStr += fmt.Sprintf("Project %s transferred to: %s", action.Project, codec.MustAddressBech32(tconsts.HRP, action.NewOwner))
+			utils.Outf(summaryStr)
+
+		case *actions.UpdateProjectMetadata:
+			summaryStr += fmt.Sprintf("Project %s metadata updated", action.Project)
+			utils.Outf(summaryStr)
+
+		case *actions.RevokeMachine:
+			summaryStr += fmt.Sprintf("Machine %s revoked", action.MachineAttestTx)
+			utils.Outf(summaryStr)
+
+		case *actions.SuspendMachine:
+			summaryStr += fmt.Sprintf("Machine %s suspended", action.MachineAttestTx)
+			utils.Outf(summaryStr)
+
+		case *actions.ReinstateMachine:
+			summaryStr += fmt.Sprintf("Machine %s reinstated", action.MachineAttestTx)
+			utils.Outf(summaryStr)
+
+		case *actions.SetProjectReleaseKey:
+			summaryStr += fmt.Sprintf("Project %s release key set to %x", action.Project, action.ReleaseKey[:])
+			utils.Outf(summaryStr)
+
+		case *actions.ListData:
+			summaryStr += fmt.Sprintf("Data %s listed with tx: %s for %d of asset %s", action.Notarization, tx.ID(), action.Price, action.Asset)
+			utils.Outf(summaryStr)
+
+		case *actions.PurchaseData:
+			summaryStr += fmt.Sprintf("Listing %s purchased for %d of asset %s -> %s", action.Listing, action.Price, action.Asset, codec.MustAddressBech32(tconsts.HRP, action.Seller))
+			utils.Outf(summaryStr)
+
+		case *actions.DelistData:
+			summaryStr += fmt.Sprintf("Listing %s closed", action.Listing)
+			utils.Outf(summaryStr)
+
+		case *actions.DeliverDataKey:
+			summaryStr += fmt.Sprintf("Content key of listing %s delivered to %s", action.Listing, codec.MustAddressBech32(tconsts.HRP, action.Buyer))
+			utils.Outf(summaryStr)
+
+		case *actions.OfferLicense:
+			summaryStr += fmt.Sprintf("License for %s %s offered with tx: %s for %d of asset %s per %dms", action.Kind, action.Subject, tx.ID(), action.Price, action.Asset, action.Period)
+			utils.Outf(summaryStr)
+
+		case *actions.PurchaseLicense:
+			summaryStr += fmt.Sprintf("%d periods of license offer %s purchased -> %s", action.Periods, action.Offer, codec.MustAddressBech32(tconsts.HRP, action.Licensor))
+			utils.Outf(summaryStr)
+
+		case *actions.WithdrawLicenseOffer:
+			summaryStr += fmt.Sprintf("License offer %s withdrawn", action.Offer)
+			utils.Outf(summaryStr)
 		}
 	}
 	utils.Outf(
@@ -0,0 +1,357 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gorm.io/gorm"
+
+	"dataverse/gateway"
+	"dataverse/ota"
+	trpc "dataverse/rpc"
+	"dataverse/storage"
+)
+
+var _ ota.Inventory = (*attestationInventory)(nil)
+
+// attestationInventory finds the devices of a group among the machines
+// attested through the gateway, by their latest attestation on chain.
+type attestationInventory struct {
+	db *gorm.DB
+}
+
+func (i *attestationInventory) Devices(ctx context.Context, group ota.Group) ([]ota.Device, error) {
+	var machines []MachineEndpoint
+	if err := i.db.WithContext(ctx).Find(&machines).Error; err != nil {
+		return nil, err
+	}
+	tcli, err := gatewayClient()
+	if err != nil {
+		return nil, err
+	}
+	var devices []ota.Device
+	for _, m := range machines {
+		attestation, err := tcli.MachineAttestation(ctx, m.MachineAddress)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case string(attestation.MachineCategory) != group.Category:
+		case len(group.Manufacturer) > 0 && string(attestation.MachineManufacturer) != group.Manufacturer:
+		case attestation.Status != storage.MachineActive.String():
+		default:
+			devices = append(devices, ota.Device{
+				AttestTx: attestation.Tx,
+				Address:  attestation.MachineAddress,
+				Endpoint: m.Endpoint,
+			})
+		}
+	}
+	return devices, nil
+}
+
+var _ ota.Updates = (*chainUpdates)(nil)
+
+// chainUpdates looks up updates and the reports of their install on chain.
+type chainUpdates struct{}
+
+func (chainUpdates) DeviceName(ctx context.Context, update ids.ID) (string, error) {
+	tcli, err := gatewayClient()
+	if err != nil {
+		return "", err
+	}
+	manifest, err := tcli.UpdateManifest(ctx, update)
+	if err != nil {
+		if strings.Contains(err.Error(), trpc.ErrUpdateNotFound.Error()) {
+			return "", fmt.Errorf("%w: %s", ota.ErrUnknownUpdate, update)
+		}
+		return "", err
+	}
+	return string(manifest.ForDeviceName), nil
+}
+
+func (chainUpdates) Report(ctx context.Context, device ota.Device, update ids.ID) (bool, bool, error) {
+	tcli, err := gatewayClient()
+	if err != nil {
+		return false, false, err
+	}
+	report, err := tcli.UpdateReport(ctx, update, device.AttestTx)
+	if err != nil {
+		if strings.Contains(err.Error(), trpc.ErrUpdateReportNotFound.Error()) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, report.Success, nil
+}
+
+// firmwareCache fetches and checks the executable of each update once, for
+// the devices it is pushed to and those that download it.
+type firmwareCache struct {
+	dir string
+
+	mu    sync.Mutex
+	files map[ids.ID]*firmwareFile
+}
+
+type firmwareFile struct {
+	once sync.Once
+	path string
+	err  error
+}
+
+func newFirmwareCache() (*firmwareCache, error) {
+	dir, err := os.MkdirTemp("", "dataverse-firmware-")
+	if err != nil {
+		return nil, err
+	}
+	return &firmwareCache{dir: dir, files: map[ids.ID]*firmwareFile{}}, nil
+}
+
+// firmware returns the path of the checked executable of [update].
+func (c *firmwareCache) firmware(ctx context.Context, update ids.ID) (string, error) {
+	c.mu.Lock()
+	f, ok := c.files[update]
+	if !ok {
+		f = &firmwareFile{}
+		c.files[update] = f
+	}
+	c.mu.Unlock()
+
+	f.once.Do(func() {
+		tcli, err := gatewayClient()
+		if err != nil {
+			f.err = err
+			return
+		}
+		f.path = filepath.Join(c.dir, update.String()+".bin")
+		if f.err = fetchFirmware(ctx, tcli, update, f.path); f.err != nil {
+			_ = os.Remove(f.path)
+		}
+	})
+	if f.err != nil {
+		// Fetch it again for the next device
+		c.mu.Lock()
+		if c.files[update] == f {
+			delete(c.files, update)
+		}
+		c.mu.Unlock()
+	}
+	return f.path, f.err
+}
+
+// Close removes the fetched executables.
+func (c *firmwareCache) Close() error {
+	return os.RemoveAll(c.dir)
+}
+
+var _ ota.Pusher = (*firmwarePusher)(nil)
+
+// firmwarePusher pushes updates to the OTA endpoint of devices.
+type firmwarePusher struct {
+	cache *firmwareCache
+}
+
+func (p *firmwarePusher) Push(ctx context.Context, device ota.Device, update ids.ID) error {
+	if len(device.Endpoint) == 0 {
+		return ErrNoDeviceEndpoint
+	}
+	path, err := p.cache.firmware(ctx, update)
+	if err != nil {
+		return err
+	}
+	return pushFirmware(ctx, device.Endpoint, update, path)
+}
+
+// validDeviceEndpoint reports whether [endpoint] is a host, with an optional
+// port, that can be put in a device url.
+func validDeviceEndpoint(endpoint string) bool {
+	if len(endpoint) == 0 || strings.ContainsAny(endpoint, "/?#@ ") {
+		return false
+	}
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return true
+	}
+	return !strings.Contains(endpoint, ":")
+}
+
+type CreateRolloutArgs struct {
+	UpdateTx     string `json:"update_tx"`
+	Category     string `json:"category"`
+	Manufacturer string `json:"manufacturer"`
+
+	// Unset fields default to those of [ota.DefaultSpec]
+	Waves          []float64         `json:"waves"`
+	Concurrency    int               `json:"concurrency"`
+	MaxFailureRate *float64          `json:"max_failure_rate"`
+	WaveDelay      *gateway.Duration `json:"wave_delay"`
+	OfferTimeout   *gateway.Duration `json:"offer_timeout"`
+
+	PreviousUpdateTx string `json:"previous_update_tx"`
+
+	// [Start] runs the rollout once it is created
+	Start bool `json:"start"`
+}
+
+func (a *CreateRolloutArgs) spec() (ota.Spec, error) {
+	update, err := ids.FromString(a.UpdateTx)
+	if err != nil {
+		return ota.Spec{}, err
+	}
+	spec := ota.DefaultSpec(update, ota.Group{Category: a.Category, Manufacturer: a.Manufacturer})
+	if len(a.Waves) > 0 {
+		spec.Waves = a.Waves
+	}
+	if a.Concurrency > 0 {
+		spec.Concurrency = a.Concurrency
+	}
+	if a.MaxFailureRate != nil {
+		spec.MaxFailureRate = *a.MaxFailureRate
+	}
+	if a.WaveDelay != nil {
+		spec.WaveDelay = time.Duration(*a.WaveDelay)
+	}
+	if a.OfferTimeout != nil {
+		spec.OfferTimeout = time.Duration(*a.OfferTimeout)
+	}
+	if len(a.PreviousUpdateTx) > 0 {
+		spec.PreviousUpdateTx, err = ids.FromString(a.PreviousUpdateTx)
+		if err != nil {
+			return ota.Spec{}, err
+		}
+	}
+	return spec, nil
+}
+
+// rolloutError replies with the status matching [err].
+func rolloutError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ota.ErrRolloutNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ota.ErrInvalidTransition):
+		status = http.StatusConflict
+	case errors.Is(err, ota.ErrInvalidSpec), errors.Is(err, ota.ErrNoDevices),
+		errors.Is(err, ota.ErrUnknownUpdate), errors.Is(err, ota.ErrDeviceMismatch):
+		status = http.StatusBadRequest
+	}
+	gateway.Error(w, err.Error(), status)
+}
+
+func rolloutID(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 32)
+	return uint(id), err
+}
+
+func writeRolloutReply(w http.ResponseWriter, reply any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+func CreateRollout(o *ota.Orchestrator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var args CreateRolloutArgs
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			return
+		}
+		spec, err := args.spec()
+		if err != nil {
+			gateway.Error(w, "Invalid txid", http.StatusBadRequest)
+			return
+		}
+		rollout, err := o.Create(r.Context(), spec)
+		if err != nil {
+			rolloutError(w, err)
+			return
+		}
+		if args.Start {
+			if err := o.Start(rollout.ID); err != nil {
+				rolloutError(w, err)
+				return
+			}
+		}
+		status, err := o.Status(r.Context(), rollout.ID)
+		if err != nil {
+			rolloutError(w, err)
+			return
+		}
+		writeRolloutReply(w, status)
+	}
+}
+
+func RolloutStatus(o *ota.Orchestrator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := rolloutID(r)
+		if err != nil {
+			gateway.Error(w, "Invalid rollout id", http.StatusBadRequest)
+			return
+		}
+		status, err := o.Status(r.Context(), id)
+		if err != nil {
+			rolloutError(w, err)
+			return
+		}
+		writeRolloutReply(w, status)
+	}
+}
+
+func RolloutDevices(o *ota.Orchestrator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := rolloutID(r)
+		if err != nil {
+			gateway.Error(w, "Invalid rollout id", http.StatusBadRequest)
+			return
+		}
+		devices, err := o.Devices(r.Context(), id, r.URL.Query().Get("status"))
+		if err != nil {
+			rolloutError(w, err)
+			return
+		}
+		writeRolloutReply(w, devices)
+	}
+}
+
+// RolloutAction serves an operator action ([ota.Orchestrator.Start],
+// [ota.Orchestrator.Halt] or [ota.Orchestrator.Rollback]) on a rollout.
+func RolloutAction(o *ota.Orchestrator, action func(uint) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := rolloutID(r)
+		if err != nil {
+			gateway.Error(w, "Invalid rollout id", http.StatusBadRequest)
+			return
+		}
+		if err := action(id); err != nil {
+			rolloutError(w, err)
+			return
+		}
+		status, err := o.Status(r.Context(), id)
+		if err != nil {
+			rolloutError(w, err)
+			return
+		}
+		writeRolloutReply(w, status)
+	}
+}
@@ -16,6 +16,13 @@ const (
 	fsModeWrite     = 0o600
 	defaultDatabase = ".updates-cli"
 	defaultGenesis  = "genesis.json"
+
+	// defaultContentDir is the local content store used when no
+	// --content-store config is given.
+	defaultContentDir = ".dataverse-content"
+
+	// signerAPIKeyEnv holds the gateway API key of the --remote-signer.
+	signerAPIKeyEnv = "DATAVERSE_SIGNER_API_KEY"
 )
 
 var (
@@ -38,6 +45,11 @@ var (
 	startPrometheus       bool
 	maxFee                int64
 	numCores              int
+	keyType               string
+	sponsoredBy           string
+	contentStoreConfig    string
+	remoteSigner          string
+	remoteSignerAddress   string
 
 	rootCmd = &cobra.Command{
 		Use:        "token-cli",
@@ -56,7 +68,8 @@ func init() {
 		spamCmd,
 		prometheusCmd,
 		deployCmd,
-		serverCmd,
+		gatewayCmd,
+		signerCmd,
 		machineCmd,
 	)
 	rootCmd.PersistentFlags().StringVar(
@@ -65,6 +78,30 @@ func init() {
 		defaultDatabase,
 		"path to database (will create it missing)",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&sponsoredBy,
+		"sponsored-by",
+		"",
+		"attestation txid of the default key, to have its attester pay fees",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&contentStoreConfig,
+		"content-store",
+		"",
+		"path to the content store config (defaults to a local store in "+defaultContentDir+")",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&remoteSigner,
+		"remote-signer",
+		"",
+		"uri of a signer to sign with instead of the default key (api key in $"+signerAPIKeyEnv+")",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&remoteSignerAddress,
+		"remote-signer-address",
+		"",
+		"address of the --remote-signer key to sign with (defaults to its only key)",
+	)
 	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
 		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		controller := NewController(dbPath)
@@ -116,6 +153,18 @@ func init() {
 	)
 
 	// key
+	genKeyCmd.PersistentFlags().StringVar(
+		&keyType,
+		"type",
+		ed25519Key,
+		"key type (ed25519 or secp256r1)",
+	)
+	importKeyCmd.PersistentFlags().StringVar(
+		&keyType,
+		"type",
+		ed25519Key,
+		"key type (ed25519 or secp256r1)",
+	)
 	balanceKeyCmd.PersistentFlags().BoolVar(
 		&checkAllChains,
 		"check-all-chains",
@@ -176,11 +225,14 @@ func init() {
 		getRepoCmd,
 		createUpdateCmd,
 		getUpdateCmd,
-	)
-
-	// server
-	serverCmd.AddCommand(
-		startServer,
+		getUpdateStatsCmd,
+		getLatestUpdateCmd,
+		addMaintainerCmd,
+		removeMaintainerCmd,
+		transferProjectCmd,
+		updateProjectCmd,
+		genReleaseKeyCmd,
+		setReleaseKeyCmd,
 	)
 
 	machineCmd.AddCommand(
@@ -188,9 +240,87 @@ func init() {
 		getregisterMachineCID,
 		attestMachine,
 		getAttestedachineCID,
+		onboardMachineCmd,
 		notarizeData,
 		getNotarizeData,
-		serverDataverseCmd,
+		notarizeBatchCmd,
+		getNotarizeBatchCmd,
+		batchProofCmd,
+		verifyInclusionCmd,
+		reportUpdateResult,
+		revokeMachine,
+		suspendMachine,
+		reinstateMachine,
+		listDataCmd,
+		purchaseDataCmd,
+		delistDataCmd,
+		getListingCmd,
+		getReceiptCmd,
+		deliverDataKeyCmd,
+		getDataKeyCmd,
+		offerLicenseCmd,
+		purchaseLicenseCmd,
+		withdrawLicenseOfferCmd,
+		getLicenseCmd,
+		hasAccessCmd,
+		setSponsorshipCmd,
+		getSponsorshipCmd,
+		createManufacturerCmd,
+		addManufacturerAttesterCmd,
+		revokeManufacturerAttesterCmd,
+		getManufacturerCmd,
+		setMetadataSchemaCmd,
+		getMetadataSchemaCmd,
+	)
+
+	// signer
+	serveSignerCmd.PersistentFlags().StringVar(
+		&signerConfigPath,
+		"config",
+		"",
+		"path to the gateway config of the signer, which must authenticate callers",
+	)
+	serveSignerCmd.PersistentFlags().StringVar(
+		&signerKeyPath,
+		"key",
+		"",
+		"path to the key to sign with (defaults to the default key)",
+	)
+	serveSignerCmd.PersistentFlags().StringVar(
+		&signerKeyType,
+		"key-type",
+		ed25519Key,
+		"type of the key",
+	)
+	signerCmd.AddCommand(
+		serveSignerCmd,
+		signerKeysCmd,
+	)
+
+	// gateway
+	gatewayCmd.PersistentFlags().StringVar(
+		&gatewayConfigPath,
+		"config",
+		"",
+		"path to the gateway config (defaults to authenticated routes on localhost)",
+	)
+	gatewayCmd.PersistentFlags().StringVar(
+		&gatewaySignerKey,
+		"signer-key",
+		"",
+		"path to the key the gateway signs transactions with (defaults to the default key)",
+	)
+	gatewayCmd.PersistentFlags().StringVar(
+		&gatewaySignerKeyType,
+		"signer-key-type",
+		ed25519Key,
+		"type of the signer key",
+	)
+	gatewayCmd.PersistentFlags().StringVar(
+		&gatewayIndexDB,
+		"index-db",
+		defaultIndexDB,
+		"path to the gateway database of attested machines and rollouts (will create it missing)",
 	)
 
 	// spam
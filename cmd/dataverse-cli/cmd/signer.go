@@ -0,0 +1,94 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+
+	"dataverse/consts"
+	"dataverse/gateway"
+	"dataverse/signer"
+)
+
+var (
+	signerConfigPath string
+	signerKeyPath    string
+	signerKeyType    string
+)
+
+var signerCmd = &cobra.Command{
+	Use: "signer",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var serveSignerCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Sign transactions for other hosts with a local key",
+	RunE: func(*cobra.Command, []string) error {
+		cfg := gateway.DefaultConfig()
+		if len(signerConfigPath) > 0 {
+			var err error
+			cfg, err = gateway.LoadConfig(signerConfigPath)
+			if err != nil {
+				return err
+			}
+		}
+		g, err := gateway.New(cfg)
+		if err != nil {
+			return err
+		}
+		addr, priv, err := handler.h.GetDefaultKey(false)
+		if len(signerKeyPath) > 0 {
+			key, kerr := loadPrivateKey(signerKeyType, signerKeyPath)
+			if kerr != nil {
+				return kerr
+			}
+			addr, priv, err = key.Address, key.Bytes, nil
+		}
+		if err != nil {
+			return err
+		}
+		service := signer.NewService()
+		if err := service.AddKey(addr, priv); err != nil {
+			return err
+		}
+		service.Register(g)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		utils.Outf(
+			"{{green}}signing for:{{/}} %s {{green}}listening on:{{/}} %s\n",
+			codec.MustAddressBech32(consts.HRP, addr),
+			cfg.ListenAddress,
+		)
+		return g.Run(ctx)
+	},
+}
+
+var signerKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List the keys of the --remote-signer",
+	RunE: func(*cobra.Command, []string) error {
+		if len(remoteSigner) == 0 {
+			return ErrNoRemoteSigner
+		}
+		keys, err := signer.NewClient(remoteSigner, os.Getenv(signerAPIKeyEnv)).Keys(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			utils.Outf("{{yellow}}key:{{/}} %s\n", key.Address)
+		}
+		return nil
+	},
+}
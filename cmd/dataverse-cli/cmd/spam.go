@@ -50,7 +50,7 @@ var runSpamCmd = &cobra.Command{
 				return nil
 			},
 			func(priv *cli.PrivateKey) (chain.AuthFactory, error) { // getFactory
-				return auth.NewED25519Factory(ed25519.PrivateKey(priv.Bytes)), nil
+				return auth.NewFactory(priv.Address, priv.Bytes)
 			},
 			func() (*cli.PrivateKey, error) { // createAccount
 				p, err := ed25519.GeneratePrivateKey()
@@ -88,10 +88,14 @@ var runSpamCmd = &cobra.Command{
 			},
 			func(cli *rpc.JSONRPCClient, priv *cli.PrivateKey) func(context.Context, uint64) error { // submitDummy
 				return func(ictx context.Context, count uint64) error {
-					_, _, err := sendAndWait(ictx, nil, &actions.Transfer{
+					factory, err := auth.NewFactory(priv.Address, priv.Bytes)
+					if err != nil {
+						return err
+					}
+					_, _, err = sendAndWait(ictx, nil, &actions.Transfer{
 						To:    priv.Address,
 						Value: count, // prevent duplicate txs
-					}, cli, sclient, tclient, auth.NewED25519Factory(ed25519.PrivateKey(priv.Bytes)), false)
+					}, cli, sclient, tclient, factory, false)
 					return err
 				}
 			},
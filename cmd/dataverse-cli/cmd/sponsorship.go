@@ -0,0 +1,60 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"dataverse/actions"
+	"dataverse/consts"
+
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var setSponsorshipCmd = &cobra.Command{
+	Use: "set-sponsorship",
+	RunE: func(*cobra.Command, []string) error {
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+		limit, err := handler.Root().PromptAmount("fee cap", consts.Decimals, hconsts.MaxUint64, nil)
+		if err != nil {
+			return err
+		}
+		return confirmAndSend(context.Background(), &actions.SetSponsorship{
+			MachineAttestTx: attestationTx,
+			Cap:             limit,
+		})
+	},
+}
+
+var getSponsorshipCmd = &cobra.Command{
+	Use: "get-sponsorship",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		attestationTx, err := handler.Root().PromptID("attestation txid")
+		if err != nil {
+			return err
+		}
+		sponsorship, err := tcli.Sponsorship(ctx, attestationTx)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}sponsor:{{/}} %s {{yellow}}cap:{{/}} %s {{yellow}}spent:{{/}} %s {{yellow}}remaining:{{/}} %s %s\n",
+			sponsorship.Sponsor,
+			utils.FormatBalance(sponsorship.Cap, consts.Decimals),
+			utils.FormatBalance(sponsorship.Spent, consts.Decimals),
+			utils.FormatBalance(sponsorship.Remaining, consts.Decimals),
+			consts.Symbol,
+		)
+		return nil
+	},
+}
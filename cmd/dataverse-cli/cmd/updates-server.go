@@ -4,72 +4,58 @@ import (
 	"bytes"
 	"context"
 	"dataverse/actions"
+	"dataverse/gateway"
+	trpc "dataverse/rpc"
+	"dataverse/storage"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/spf13/cobra"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 )
 
-var serverCmd = &cobra.Command{
-	Use: "server",
-	RunE: func(*cobra.Command, []string) error {
-		return ErrMissingSubcommand
-	},
-}
-
-func trimNullChars(s string) string {
-
-	t := strings.TrimRight(s, "\x00")
-	u := strings.TrimLeft(t, "\x00")
-
-	return u
-}
-
 func GetUpdateDataHandler(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, _, _, _, tcli, _ := handler.DefaultActor()
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		t := r.URL.Query().Get("transactionid")
 		transactionId, err := ids.FromString(t)
 
 		if err != nil {
 
-			fmt.Fprintln(w, "Invalid Id Passed")
-			response := map[string]interface{}{
-				"status": "failed",
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			// w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(response)
+			gateway.Error(w, "Invalid TxId", http.StatusBadRequest)
 
 		} else {
 
 			_, ProjectTxID, UpdateExecutableHash, UpdateIPFSUrl, ForDeviceName, UpdateVersion, _, err := tcli.Update(ctx, transactionId, false)
 
 			if err != nil {
-				fmt.Fprintln(w, "Server Error")
+				gateway.Error(w, "Cannot query chain", http.StatusInternalServerError)
+				return
 			}
 
 			response := map[string]interface{}{
-				"ProjectTxID":          trimNullChars(string(ProjectTxID)),
-				"UpdateExecutableHash": trimNullChars(string(UpdateExecutableHash)),
-				"UpdateIPFSUrl":        trimNullChars(string(UpdateIPFSUrl)),
-				"ForDeviceName":        trimNullChars(string(ForDeviceName)),
-				"UpdateVersion":        UpdateVersion,
+				"ProjectTxID":          ProjectTxID.String(),
+				"UpdateExecutableHash": hex.EncodeToString(UpdateExecutableHash),
+				"UpdateIPFSUrl":        string(UpdateIPFSUrl),
+				"ForDeviceName":        string(ForDeviceName),
+				"UpdateVersion":        UpdateVersion.String(),
 				"status":               "success",
 			}
-			fmt.Println("Project Tx Id: ", string(ProjectTxID), ", Exe Hash: ", string(UpdateExecutableHash), ", Ipfs URL: ", string(UpdateIPFSUrl), ", For Devide: ", string(ForDeviceName), ", Version: ", UpdateVersion)
 			w.Header().Set("Content-Type", "application/json")
 
 			// w.WriteHeader(http.StatusOK)
@@ -84,7 +70,11 @@ func GetUpdateHash(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, _, _, _, tcli, _ := handler.DefaultActor()
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		t := r.URL.Query().Get("transactionid")
 		hash := r.URL.Query().Get("hash")
@@ -92,30 +82,27 @@ func GetUpdateHash(ctx context.Context) http.HandlerFunc {
 
 		if err != nil {
 
-			fmt.Fprintln(w, "Invalid Id Passed")
-			http.Error(w, "Invalid TxId", http.StatusBadRequest)
+			gateway.Error(w, "Invalid TxId", http.StatusBadRequest)
 			return
 
 		} else {
 
-			_, ProjectTxID, UpdateExecutableHash, UpdateIPFSUrl, ForDeviceName, UpdateVersion, _, err := tcli.Update(ctx, transactionId, false)
+			_, _, UpdateExecutableHash, _, _, _, _, err := tcli.Update(ctx, transactionId, false)
 
 			if err != nil {
-				fmt.Fprintln(w, "Server Error")
-				http.Error(w, "Cannot query chain", http.StatusInternalServerError)
+				gateway.Error(w, "Cannot query chain", http.StatusInternalServerError)
 				return
 			}
 
-			trueHash := trimNullChars(string(UpdateExecutableHash))
+			trueHash := hex.EncodeToString(UpdateExecutableHash)
 			response := ""
 			if hash != trueHash {
-				http.Error(w, "Invalid String", http.StatusBadRequest)
+				gateway.Error(w, "Invalid String", http.StatusBadRequest)
 				return
 			} else {
 				response = "VALID"
 			}
 
-			fmt.Println("Project Tx Id: ", string(ProjectTxID), ", Exe Hash: ", string(UpdateExecutableHash), ", Ipfs URL: ", string(UpdateIPFSUrl), ", For Devide: ", string(ForDeviceName), ", Version: ", UpdateVersion)
 			w.Header().Set("Content-Type", "application/json")
 
 			// w.WriteHeader(http.StatusOK)
@@ -136,18 +123,22 @@ func CreateRepositoryHandler(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, factory, cli, scli, tcli, err := handler.DefaultActor()
+		_, _, factory, cli, scli, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		body, err := io.ReadAll(r.Body)
 
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			gateway.Error(w, "Error reading request body", http.StatusBadRequest)
 			return
 		}
 
 		var projectInfo ProjectInfo
 		if err := json.Unmarshal(body, &projectInfo); err != nil {
-			http.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
 			return
 		}
 
@@ -162,7 +153,8 @@ func CreateRepositoryHandler(ctx context.Context) http.HandlerFunc {
 
 		response := ""
 		if err != nil {
-			http.Error(w, "Error while creating Repository", http.StatusInternalServerError)
+			gateway.Error(w, "Error while creating Repository", http.StatusInternalServerError)
+			return
 		}
 
 		response = id.String()
@@ -176,85 +168,93 @@ func CreateRepositoryHandler(ctx context.Context) http.HandlerFunc {
 
 }
 
-func cleanupFile(file *os.File) {
-	// Cleanup logic
-	err := os.Remove(file.Name())
-	if err != nil {
-		fmt.Println("Error deleting file:", err)
-		return
-	}
-	fmt.Println("File deleted successfully.")
-}
-
 func CreateUpdateHandler(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, factory, cli, scli, tcli, err := handler.DefaultActor()
+		_, _, factory, cli, scli, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		err_mparse := r.ParseMultipartForm(10 << 20) // 10 MB limit for the entire request
 		if err_mparse != nil {
-			http.Error(w, "Unable to parse form", http.StatusBadRequest)
+			gateway.Error(w, "Unable to parse form", http.StatusBadRequest)
 			return
 		}
 
 		// Extract form values
-		projectID := r.FormValue("project_id")
+		projectID, err := ids.FromString(r.FormValue("project_id"))
+		if err != nil {
+			gateway.Error(w, "Invalid project id", http.StatusBadRequest)
+			return
+		}
 		forDeviceName := r.FormValue("for_device_name")
-		version, _ := strconv.ParseUint(r.FormValue("version"), 10, 8)
+		// The manifest is signed with the project release key by the
+		// publisher, the key never reaches this server
+		signature, err := hex.DecodeString(r.FormValue("signature"))
+		if err != nil || len(signature) != ed25519.SignatureLen {
+			gateway.Error(w, "Invalid signature", http.StatusBadRequest)
+			return
+		}
+		version, err := storage.ParseVersion(r.FormValue("version"))
+		if err != nil {
+			gateway.Error(w, "Invalid version, expected major.minor.patch", http.StatusBadRequest)
+			return
+		}
 
 		// Get a reference to the uploaded file
-		file, fileHeader, err := r.FormFile("executable_file")
+		file, _, err := r.FormFile("executable_file")
 		if err != nil {
-			http.Error(w, "Unable to get file from request", http.StatusBadRequest)
+			gateway.Error(w, "Unable to get file from request", http.StatusBadRequest)
 			return
 		}
 		defer file.Close()
 
-		// Create a new file on the server
-		dst, err := os.Create(fileHeader.Filename)
+		// Create a new file on the server, the uploaded name isn't trusted
+		dst, err := os.CreateTemp("", "update-*")
 		if err != nil {
-			http.Error(w, "Unable to create file on server", http.StatusInternalServerError)
+			gateway.Error(w, "Unable to create file on server", http.StatusInternalServerError)
 			return
 		}
+		defer os.Remove(dst.Name())
 		defer dst.Close()
 
 		// Copy the uploaded file to the new file
 		_, err = io.Copy(dst, file)
 		if err != nil {
-			http.Error(w, "Unable to copy file", http.StatusInternalServerError)
+			gateway.Error(w, "Unable to copy file", http.StatusInternalServerError)
 			return
 		}
 
-		executable_ipfs_url, err := DeployBin(
-			fileHeader.Filename,
-			"fc43a725fd778580045c",
-			"37c52b3571d7df2c1326c1460a1b192c209a1fb212c6b1b96eb2626bb2076efe",
-		)
-
-		executable_hash, err := CalculateMD5(fileHeader.Filename)
-
+		executable_cid, err := uploadContent(r.Context(), dst.Name())
 		if err != nil {
-			http.Error(w, "Cannot upload file to IPFS", http.StatusInternalServerError)
+			gateway.Error(w, "Cannot upload file to the content store", http.StatusInternalServerError)
+			return
 		}
-		// Print received data
-		fmt.Printf("Received data:\nProject ID: %s\nDevice Name: %s\nVersion: %s\n",
-			projectID, forDeviceName, version)
-
-		// File cleanup using defer
-		defer cleanupFile(dst)
 
+		executable_hash, executable_size, err := CalculateSHA256(dst.Name())
+		if err != nil {
+			gateway.Error(w, "Cannot hash file", http.StatusInternalServerError)
+			return
+		}
 		update := &actions.CreateUpdate{
-			ProjectTxID:          []byte(projectID),
-			UpdateExecutableHash: []byte(executable_hash),
-			UpdateIPFSUrl:        []byte(executable_ipfs_url),
+			ProjectTxID:          projectID,
+			UpdateExecutableHash: executable_hash,
+			UpdateIPFSUrl:        []byte(executable_cid),
 			ForDeviceName:        []byte(forDeviceName),
-			UpdateVersion:        uint8(version),
-			SuccessCount:         0,
+			UpdateVersion:        version,
+			UpdateSize:           executable_size,
+			Signature:            ed25519.Signature(signature),
 		}
 
 		// Generate transaction
 		_, id, err := sendAndWait(ctx, nil, update, cli, scli, tcli, factory, true)
+		if err != nil {
+			gateway.Error(w, "Cannot create update: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("File uploaded successfully: " + id.String()))
@@ -268,52 +268,85 @@ type PushUpdateInfo struct {
 	DeviceIp string `json:"device-ip"`
 }
 
-func pushFirmwareHash(hash, txid, filePath, deviceIp string) error {
+const (
+	// deviceOTATimeout bounds a push to the OTA endpoint of a device.
+	deviceOTATimeout = 2 * time.Minute
+	// deviceUserAgent identifies the gateway to the devices it pushes to.
+	deviceUserAgent = "dataverse-gateway"
+)
 
-	url := "http://" + deviceIp + "/ota/start?mode=fr&hash=" + hash + "&txid=" + txid
-	fmt.Println(url)
+var deviceClient = &http.Client{Timeout: deviceOTATimeout}
 
-	// Create the request
-	request, err := http.NewRequest("GET", url, nil)
+// fetchFirmware downloads the executable of [updateTx] to [filePath] and
+// checks it is the one the release key of its project signed.
+func fetchFirmware(ctx context.Context, tcli *trpc.JSONRPCClient, updateTx ids.ID, filePath string) error {
+	update, err := tcli.UpdateManifest(ctx, updateTx)
 	if err != nil {
-		fmt.Println("Error creating request:", err)
 		return err
 	}
+	hasReleaseKey, releaseKey, err := tcli.ReleaseKey(ctx, update.ProjectTxID)
+	if err != nil {
+		return err
+	}
+	if !hasReleaseKey {
+		return ErrNoReleaseKey
+	}
+	if err := downloadContent(ctx, filePath, string(update.UpdateIPFSUrl), update.UpdateExecutableHash); err != nil {
+		return err
+	}
+	// Never push an executable that isn't the one the release key signed
+	return VerifyUpdateExecutable(filePath, update, releaseKey)
+}
 
-	// Set the necessary headers
-	request.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0")
-	request.Header.Set("Accept", "*/*")
-	request.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	request.Header.Set("Accept-Encoding", "gzip, deflate")
-	request.Header.Set("Referer", "http://192.168.0.6/update")
-	request.Header.Set("Connection", "keep-alive")
-
-	// Make the request
-	client := &http.Client{}
-	response, err := client.Do(request)
+// pushFirmware installs the executable of [updateTx] at [filePath], which
+// must have been checked by [fetchFirmware], on the device at [deviceIp].
+func pushFirmware(ctx context.Context, deviceIp string, updateTx ids.ID, filePath string) error {
+	// The device OTA endpoint checks the image it receives against an MD5
+	firmwareMD5, err := CalculateMD5(filePath)
 	if err != nil {
-		fmt.Println("Error making request:", err)
 		return err
 	}
-	defer response.Body.Close()
+	if err := pushFirmwareHash(ctx, firmwareMD5, updateTx.String(), deviceIp); err != nil {
+		return fmt.Errorf("cannot push firmware hash: %w", err)
+	}
+	if err := PushFirmwareUpdate(ctx, deviceIp, filePath); err != nil {
+		return fmt.Errorf("cannot push firmware: %w", err)
+	}
+	return nil
+}
 
-	// Print the response status and headers
-	fmt.Println("Response Status:", response.Status)
-	fmt.Println("Response Headers:", response.Header)
+// checkDeviceReply returns an error if the device did not accept the
+// request.
+func checkDeviceReply(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	return fmt.Errorf("device replied %s: %s", response.Status, strings.TrimSpace(string(body)))
+}
+
+func pushFirmwareHash(ctx context.Context, hash, txid, deviceIp string) error {
+
+	url := "http://" + deviceIp + "/ota/start?mode=fr&hash=" + hash + "&txid=" + txid
 
-	// You can read and print the response body if needed
-	body, err := ioutil.ReadAll(response.Body)
+	// Create the request
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		fmt.Println("Error reading response:", err)
 		return err
 	}
-	fmt.Println("Response Body:", string(body))
 
-	return nil
+	request.Header.Set("User-Agent", deviceUserAgent)
 
+	// Make the request
+	response, err := deviceClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return checkDeviceReply(response)
 }
 
-func PushFirmwareUpdate(deviceIp string, filePath string, w http.ResponseWriter) error {
+func PushFirmwareUpdate(ctx context.Context, deviceIp string, filePath string) error {
 
 	url := "http://" + deviceIp + "/ota/upload"
 
@@ -324,109 +357,91 @@ func PushFirmwareUpdate(deviceIp string, filePath string, w http.ResponseWriter)
 	// Add the file to the request body
 	file, err := os.Open(filePath)
 	if err != nil {
-		http.Error(w, "Error Opening file", http.StatusInternalServerError)
 		return err
 	}
 	defer file.Close()
 
 	fileWriter, err := writer.CreateFormFile("file", "firmware.bin")
 	if err != nil {
-		fmt.Println("Error creating form file:", err)
 		return err
 	}
 
-	_, err = io.Copy(fileWriter, file)
-	if err != nil {
-		fmt.Println("Error copying file content:", err)
+	if _, err := io.Copy(fileWriter, file); err != nil {
 		return err
 	}
 
 	// Close the multipart writer to finalize the request body
-	writer.Close()
+	if err := writer.Close(); err != nil {
+		return err
+	}
 
 	// Create the request
-	request, err := http.NewRequest("POST", url, &requestBody)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &requestBody)
 	if err != nil {
-		fmt.Println("Error creating request:", err)
 		return err
 	}
 
-	// Set the necessary headers
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Accept", "*/*")
-	request.Header.Set("Accept-Language", "en-US,en;q=0.9")
-	request.Header.Set("Referer", "http://"+deviceIp+"/update")
-	request.Header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
-	request.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36")
-	request.Header.Set("Accept-Encoding", "gzip, deflate")
+	request.Header.Set("User-Agent", deviceUserAgent)
 
 	// Make the request
-	client := &http.Client{}
-
-	// delete the downloaded firmware file
-	deleteFile(filePath)
-
-	response, err := client.Do(request)
+	response, err := deviceClient.Do(request)
 	if err != nil {
-		fmt.Println("Error making request:", err)
 		return err
 	}
 	defer response.Body.Close()
-
-	// Read and print the response
-	body2, err := io.ReadAll(response.Body)
-
-	if err != nil {
-		fmt.Println("Error reading response:", err)
-		return err
-	}
-
-	fmt.Println("Response:", string(body2))
-
-	return nil
+	return checkDeviceReply(response)
 }
 
 func PushUpdate(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		filePath, err := os.Getwd()
-		filePath += "/firmware.bin"
-
-		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		body, err := io.ReadAll(r.Body)
+		// Each push downloads to its own file, pushes may run concurrently
+		firmware, err := os.CreateTemp("", "firmware-*.bin")
+		if err != nil {
+			gateway.Error(w, "Unable to create file on server", http.StatusInternalServerError)
+			return
+		}
+		firmware.Close()
+		filePath := firmware.Name()
+		defer os.Remove(filePath)
 
 		var pushUpdateInfo PushUpdateInfo
-		if err := json.Unmarshal(body, &pushUpdateInfo); err != nil {
-			http.Error(w, "Error decoding JSON", http.StatusBadRequest)
+		if err := json.NewDecoder(r.Body).Decode(&pushUpdateInfo); err != nil {
+			gateway.Error(w, "Error decoding JSON", http.StatusBadRequest)
+			return
+		}
+		if !validDeviceEndpoint(pushUpdateInfo.DeviceIp) {
+			gateway.Error(w, "Invalid device ip", http.StatusBadRequest)
 			return
 		}
 
 		transactionId, err := ids.FromString(pushUpdateInfo.UpdateTx)
-
-		_, _, UpdateExecutableHash, UpdateIPFSUrl, _, _, _, err := tcli.Update(ctx, transactionId, false)
-
 		if err != nil {
-			fmt.Fprintln(w, "Server Error")
-		}
-
-		err_download := downloadIPFSFile(filePath, trimNullChars(string(UpdateIPFSUrl)))
-
-		if err_download != nil {
-			fmt.Println("Error Downloading file:", err)
+			gateway.Error(w, "Invalid update txid", http.StatusBadRequest)
 			return
 		}
 
-		err = pushFirmwareHash(trimNullChars(string(UpdateExecutableHash)), transactionId.String(), filePath, pushUpdateInfo.DeviceIp)
-		if err != nil {
-			http.Error(w, "Cannot push hash to firmware: "+err.Error(), http.StatusInternalServerError)
+		if err := fetchFirmware(ctx, tcli, transactionId, filePath); err != nil {
+			status := http.StatusBadGateway
+			switch {
+			case errors.Is(err, ErrNoReleaseKey), errors.Is(err, ErrDigestMismatch),
+				errors.Is(err, ErrSizeMismatch), errors.Is(err, ErrInvalidSignature):
+				status = http.StatusUnprocessableEntity
+			}
+			gateway.Error(w, "Cannot fetch firmware: "+err.Error(), status)
 			return
 		}
 
-		err = PushFirmwareUpdate(pushUpdateInfo.DeviceIp, filePath, w)
-		if err != nil {
-			http.Error(w, "Cannot push firmware: "+err.Error(), http.StatusInternalServerError)
+		if err := pushFirmware(r.Context(), pushUpdateInfo.DeviceIp, transactionId, filePath); err != nil {
+			gateway.Error(w, err.Error(), http.StatusBadGateway)
 			return
 		}
 
@@ -440,43 +455,65 @@ func GetUpdate(ctx context.Context) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		_, _, _, _, _, tcli, _ := handler.DefaultActor()
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		t := r.URL.Query().Get("transactionid")
-		transactionId, _ := ids.FromString(t)
-
-		_, ProjectTxID, UpdateExecutableHash, UpdateIPFSUrl, ForDeviceName, UpdateVersion, _, _ := tcli.Update(ctx, transactionId, false)
+		transactionId, err := ids.FromString(t)
+		if err != nil {
+			gateway.Error(w, "Invalid TxId", http.StatusBadRequest)
+			return
+		}
 
-		uversion, _ := strconv.ParseUint(trimNullChars(string(UpdateVersion)), 10, 8)
+		_, ProjectTxID, UpdateExecutableHash, UpdateIPFSUrl, ForDeviceName, UpdateVersion, _, err := tcli.Update(ctx, transactionId, false)
+		if err != nil {
+			gateway.Error(w, "Cannot query chain", http.StatusInternalServerError)
+			return
+		}
 
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("Project Id: " + trimNullChars(string(ProjectTxID)) + "\n Hash: " + trimNullChars(string(UpdateExecutableHash)) + "\n IPFS URL: " + trimNullChars(string(UpdateIPFSUrl)) + "\n Device Name: " + trimNullChars(string(ForDeviceName)) + "\n VersionL " + string(uversion)))
+		w.Write([]byte("Project Id: " + ProjectTxID.String() + "\n Hash: " + hex.EncodeToString(UpdateExecutableHash) + "\n IPFS URL: " + string(UpdateIPFSUrl) + "\n Device Name: " + string(ForDeviceName) + "\n VersionL " + UpdateVersion.String()))
 	}
 
 }
 
-var startServer = &cobra.Command{
-	Use: "start",
-	RunE: func(*cobra.Command, []string) error {
+// GetLatestUpdate returns the most recent update of a project for a device,
+// so devices don't need to know the update txID up front.
+func GetLatestUpdate(ctx context.Context) http.HandlerFunc {
 
-		ctx := context.Background()
+	return func(w http.ResponseWriter, r *http.Request) {
 
-		http.HandleFunc("/", GetUpdateDataHandler(ctx))
-		http.HandleFunc("/create-repository", CreateRepositoryHandler(ctx))
-		http.HandleFunc("/create-update", CreateUpdateHandler(ctx))
-		http.HandleFunc("/check-hash", GetUpdateHash(ctx))
-		http.HandleFunc("/push-update", PushUpdate(ctx))
-		http.HandleFunc("/get-update", GetUpdate(ctx))
+		_, _, _, _, _, tcli, err := gatewayActor()
+		if err != nil {
+			gateway.Error(w, "Cannot load signer: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		// Start the HTTP server on port 8080
-		fmt.Println("Server is listening on port 8080...")
-		err_http := http.ListenAndServe(":8080", nil)
-		fmt.Println("Server Ended")
+		projectID, err := ids.FromString(r.URL.Query().Get("project_id"))
+		if err != nil {
+			gateway.Error(w, "Invalid project id", http.StatusBadRequest)
+			return
+		}
+		device := r.URL.Query().Get("for_device_name")
 
-		if err_http != nil {
-			return err_http
+		update, err := tcli.LatestUpdate(ctx, projectID, device)
+		if err != nil {
+			gateway.Error(w, err.Error(), http.StatusNotFound)
+			return
 		}
 
-		return err_http
-	},
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"UpdateTxID":           update.UpdateTx.String(),
+			"ProjectTxID":          update.ProjectTxID.String(),
+			"UpdateExecutableHash": hex.EncodeToString(update.UpdateExecutableHash),
+			"UpdateIPFSUrl":        string(update.UpdateIPFSUrl),
+			"ForDeviceName":        string(update.ForDeviceName),
+			"UpdateVersion":        update.UpdateVersion.String(),
+		})
+	}
+
 }
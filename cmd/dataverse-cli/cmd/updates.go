@@ -4,9 +4,13 @@ import (
 	"context"
 	"dataverse/actions"
 	"dataverse/consts"
+	"dataverse/storage"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +49,23 @@ var createRepoCmd = &cobra.Command{
 			return err
 		}
 
+		// Accounts allowed to publish updates besides the owner
+		maintainers := []codec.Address{}
+		for len(maintainers) < storage.MaxProjectMaintainers {
+			add, err := handler.Root().PromptBool("add maintainer")
+			if err != nil {
+				return err
+			}
+			if !add {
+				break
+			}
+			maintainer, err := handler.Root().PromptAddress("Maintainer Address")
+			if err != nil {
+				return err
+			}
+			maintainers = append(maintainers, maintainer)
+		}
+
 		// Confirm action
 		cont, err := handler.Root().PromptContinue()
 		if !cont || err != nil {
@@ -55,6 +76,7 @@ var createRepoCmd = &cobra.Command{
 			ProjectName:        []byte(project_name),
 			ProjectDescription: []byte(project_description),
 			Logo:               []byte(URL),
+			Maintainers:        maintainers,
 		}
 
 		// Generate transaction
@@ -84,12 +106,32 @@ var getRepoCmd = &cobra.Command{
 
 		id, err := handler.Root().PromptID("Project txid")
 
-		ID, ProjectName, ProjectDescription, ProjectOwner, Logo, err := tcli.Project(ctx, id, false)
+		ID, ProjectName, ProjectDescription, ProjectOwner, Logo, Maintainers, _, err := tcli.Project(ctx, id, false)
+		if err != nil {
+			return err
+		}
 
 		addr, err := codec.AddressBech32(consts.HRP, codec.Address(ID))
 		// owner, err := codec.AddressBech32(consts.HRP, codec.Address(ProjectOwner))
 
 		fmt.Println("Id: ", addr, ", Project Name: ", string(ProjectName), ", Project Logo: ", string(Logo), ", Project Description: ", string(ProjectDescription), ", Project Owner: ", string(ProjectOwner))
+		for _, maintainer := range Maintainers {
+			fmt.Println("Maintainer: ", maintainer)
+		}
+		var cursor []byte
+		for {
+			history, next, err := tcli.ProjectHistory(ctx, id, cursor, 0)
+			if err != nil {
+				return err
+			}
+			for _, change := range history {
+				fmt.Println("Change: ", change.Kind, ", TxID: ", change.TxID, ", Timestamp: ", change.Timestamp, ", Actor: ", change.Actor, ", Subject: ", change.Subject)
+			}
+			if next == nil {
+				break
+			}
+			cursor = next
+		}
 
 		return err
 
@@ -106,7 +148,7 @@ var createUpdateCmd = &cobra.Command{
 			return err
 		}
 
-		project_id, err := handler.Root().PromptString("Project txid", 1, 100)
+		project_id, err := handler.Root().PromptID("Project txid")
 		if err != nil {
 			return err
 		}
@@ -116,18 +158,14 @@ var createUpdateCmd = &cobra.Command{
 			return err
 		}
 
-		executable_ipfs_url, err := DeployBin(
-			executable_path,
-			"fc43a725fd778580045c",
-			"37c52b3571d7df2c1326c1460a1b192c209a1fb212c6b1b96eb2626bb2076efe",
-		)
+		executable_cid, err := uploadContent(ctx, executable_path)
 		if err != nil {
 			return err
 		}
 
 		fmt.Println("Binary Upload completed")
 
-		executable_hash, err := CalculateMD5(executable_path)
+		executable_hash, executable_size, err := CalculateSHA256(executable_path)
 		if err != nil {
 			return err
 		}
@@ -139,19 +177,33 @@ var createUpdateCmd = &cobra.Command{
 			return err
 		}
 
-		version, err := handler.Root().PromptInt("Update Version", 10)
+		versionStr, err := handler.Root().PromptString("Update Version (major.minor.patch)", 5, 32)
+		if err != nil {
+			return err
+		}
+		version, err := storage.ParseVersion(versionStr)
+		if err != nil {
+			return err
+		}
+
+		release_key_path, err := handler.Root().PromptString("Release Key Path", 1, 500)
+		if err != nil {
+			return err
+		}
+		release_key, err := utils.LoadBytes(release_key_path, ed25519.PrivateKeyLen)
 		if err != nil {
 			return err
 		}
 
 		update := &actions.CreateUpdate{
-			ProjectTxID:          []byte(project_id),
-			UpdateExecutableHash: []byte(executable_hash),
-			UpdateIPFSUrl:        []byte(executable_ipfs_url),
+			ProjectTxID:          project_id,
+			UpdateExecutableHash: executable_hash,
+			UpdateIPFSUrl:        []byte(executable_cid),
 			ForDeviceName:        []byte(for_device_name),
-			UpdateVersion:        uint8(version),
-			SuccessCount:         0,
+			UpdateVersion:        version,
+			UpdateSize:           executable_size,
 		}
+		update.Signature = ed25519.Sign(update.Manifest(), ed25519.PrivateKey(release_key))
 
 		// Generate transaction
 		_, id, err := sendAndWait(ctx, nil, update, cli, scli, tcli, factory, true)
@@ -183,9 +235,68 @@ var getUpdateCmd = &cobra.Command{
 
 		addr, err := codec.AddressBech32(consts.HRP, codec.Address(ID))
 
-		fmt.Println("Id: ", addr, ", Project Tx Id: ", string(ProjectTxID), ", Exe Hash: ", string(UpdateExecutableHash), ", Ipfs URL: ", string(UpdateIPFSUrl), ", For Devide: ", string(ForDeviceName), ", Version: ", UpdateVersion, ", Success: ", SuccessCount)
+		fmt.Println("Id: ", addr, ", Project Tx Id: ", ProjectTxID.String(), ", Exe Hash: ", hex.EncodeToString(UpdateExecutableHash), ", Ipfs URL: ", string(UpdateIPFSUrl), ", For Devide: ", string(ForDeviceName), ", Version: ", UpdateVersion, ", Success: ", SuccessCount)
 
 		return err
 
 	},
 }
+
+var getUpdateStatsCmd = &cobra.Command{
+	Use: "get-update-stats",
+	RunE: func(*cobra.Command, []string) error {
+
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		id, err := handler.Root().PromptID("Update txid")
+		if err != nil {
+			return err
+		}
+
+		succeeded, failed, err := tcli.UpdateStats(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Installed: ", succeeded, ", Failed: ", failed)
+
+		return nil
+
+	},
+}
+
+var getLatestUpdateCmd = &cobra.Command{
+	Use: "get-latest-update",
+	RunE: func(*cobra.Command, []string) error {
+
+		ctx := context.Background()
+		_, _, _, _, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		project, err := handler.Root().PromptID("Project txid")
+		if err != nil {
+			return err
+		}
+
+		device, err := handler.Root().PromptString("Device Name", 1, actions.ForDeviceNameUnits)
+		if err != nil {
+			return err
+		}
+
+		update, err := tcli.LatestUpdate(ctx, project, device)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Update Tx Id: ", update.UpdateTx, ", Exe Hash: ", hex.EncodeToString(update.UpdateExecutableHash), ", Ipfs URL: ", string(update.UpdateIPFSUrl), ", Version: ", update.UpdateVersion)
+
+		return nil
+
+	},
+}
@@ -2,85 +2,126 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
+
+	"dataverse/actions"
+	"dataverse/content"
+	trpc "dataverse/rpc"
 )
 
-type PinataResponse struct {
-	IpfsHash string `json:"IpfsHash"`
+// contentStore returns the store selected with --content-store, or a local
+// store in [defaultContentDir] if none is.
+func contentStore() (content.ContentStore, error) {
+	if len(contentStoreConfig) == 0 {
+		return content.NewStore(content.DefaultStoreConfig(defaultContentDir))
+	}
+	c, err := content.LoadStoreConfig(contentStoreConfig)
+	if err != nil {
+		return nil, err
+	}
+	return content.NewStore(c)
 }
 
-func DeployBin(filePath, apiKey, secretApiKey string) (string, error) {
+// uploadContent puts the file at [filePath] in the content store and returns
+// its CID.
+func uploadContent(ctx context.Context, filePath string) (string, error) {
+	store, err := contentStore()
+	if err != nil {
+		return "", err
+	}
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
+	return store.Put(ctx, file)
+}
 
-	// Create a new buffer to store the multipart/form-data request body
-	var requestBody bytes.Buffer
-	writer := multipart.NewWriter(&requestBody)
-
-	// Create a part for the file
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
-	if err != nil {
-		return "", err
+// downloadContent writes the content [ref] refers to to [filePath]. Nothing
+// is written if the content doesn't match its CID or, for gateway URLs whose
+// CID can't be verified, the executable hash [digest] of its update.
+func downloadContent(ctx context.Context, filePath string, ref string, digest []byte) error {
+	var r io.ReadCloser
+	cid, err := content.ParseRef(ref)
+	switch {
+	case err == nil:
+		store, err := contentStore()
+		if err != nil {
+			return err
+		}
+		if r, err = store.Get(ctx, cid); err != nil {
+			return err
+		}
+	case errors.Is(err, content.ErrLegacyRef):
+		if r, err = fetchURL(ctx, ref); err != nil {
+			return err
+		}
+	default:
+		return err
 	}
+	defer r.Close()
 
-	// Copy the file content into the part
-	_, err = io.Copy(part, file)
+	// Content is only known to match its CID once read in full, so it is
+	// written to a temporary file that is renamed once checked
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*")
 	if err != nil {
-		return "", err
+		return err
 	}
+	sum, md5Sum := sha256.New(), md5.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, sum, md5Sum), r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if len(cid) == 0 && !digestMatches(digest, sum.Sum(nil), md5Sum.Sum(nil)) {
+		os.Remove(tmp.Name())
+		return ErrDigestMismatch
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
 
-	writer.Close()
-
-	// Create the HTTP POST request
-	req, err := http.NewRequest("POST", "https://api.pinata.cloud/pinning/pinFileToIPFS", &requestBody)
+// fetchURL returns the body of [url].
+func fetchURL(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	// Set content type header
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// Set API key headers
-	req.Header.Add("pinata_api_key", apiKey)
-	req.Header.Add("pinata_secret_api_key", secretApiKey)
-
-	// Perform the HTTP request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// Check the response status code
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status code %d, %s", resp.StatusCode, body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("cannot fetch %s: status %d", url, resp.StatusCode)
 	}
+	return resp.Body, nil
+}
 
-	var pinataResponse PinataResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pinataResponse); err != nil {
-		return "", err
+// digestMatches reports whether [digest], the executable hash of an update,
+// is that of content with the sha256 [sum]. Updates published before
+// executables were hashed with sha256 hold their hex encoded md5, which is
+// checked against [md5Sum] instead.
+func digestMatches(digest []byte, sum []byte, md5Sum []byte) bool {
+	if legacy, err := hex.DecodeString(string(digest)); err == nil && len(legacy) == md5.Size {
+		return bytes.Equal(legacy, md5Sum)
 	}
-
-	imageUrl := "https://ipfs.io/ipfs/" + pinataResponse.IpfsHash
-
-	return imageUrl, nil
+	return bytes.Equal(digest, sum)
 }
 
 func CalculateMD5(filePath string) (string, error) {
@@ -103,36 +144,53 @@ func CalculateMD5(filePath string) (string, error) {
 	return hashString, nil
 }
 
-func downloadIPFSFile(filepath string, url string) (err error) {
+// CalculateSHA256 returns the SHA-256 digest and the size of the file at
+// [filePath].
+func CalculateSHA256(filePath string) ([]byte, uint64, error) {
 
-	// Create a Resty client
-	client := resty.New()
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
 
-	// Perform the HTTP GET request
-	resp, err := client.R().
-		Get(url)
+	hash := sha256.New()
 
-	// Check for errors
+	size, err := io.Copy(hash, file)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return err
+		return nil, 0, err
 	}
 
-	// Check the response status code
-	if resp.StatusCode() != 200 {
-		fmt.Println("Error: Non-200 status code received")
-		return ErrInsufficientSupply
+	return hash.Sum(nil), uint64(size), nil
+}
+
+// VerifyUpdateExecutable checks that the file at [filePath] is the executable
+// of [update] and that its manifest is signed by [releaseKey].
+// Updates published before updates were signed can't be and are refused
+// with [ErrUnsignedUpdate].
+func VerifyUpdateExecutable(filePath string, update *trpc.UpdateReply, releaseKey ed25519.PublicKey) error {
+	if len(update.Signature) == 0 {
+		return ErrUnsignedUpdate
 	}
 
-	// Save the response body to a file
-	err = ioutil.WriteFile(filepath, resp.Body(), 0644)
+	digest, size, err := CalculateSHA256(filePath)
 	if err != nil {
-		fmt.Println("Error saving file:", err)
 		return err
 	}
+	if !bytes.Equal(digest, update.UpdateExecutableHash) {
+		return ErrDigestMismatch
+	}
+	if size != update.UpdateSize {
+		return ErrSizeMismatch
+	}
+	if len(update.Signature) != ed25519.SignatureLen {
+		return ErrInvalidSignature
+	}
 
-	fmt.Println("File downloaded successfully:", filepath)
-
+	manifest := actions.UpdateManifest(update.ProjectTxID, update.ForDeviceName, update.UpdateVersion, digest, size)
+	if !ed25519.Verify(manifest, releaseKey, ed25519.Signature(update.Signature)) {
+		return ErrInvalidSignature
+	}
 	return nil
 }
 
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+var (
+	ErrInvalidCID     = errors.New("invalid CID")
+	ErrUnsupportedCID = errors.New("only raw sha2-256 CIDs are supported")
+	ErrLegacyRef      = errors.New("content of a gateway URL can't be verified by its CID")
+)
+
+// CID returns the base32 CIDv1 (raw codec, sha2-256) of [data]. This is the
+// form notarized data CIDs are stored in on-chain.
+func CID(data []byte) (string, error) {
+	hash, err := mh.Sum(data, mh.SHA2_256, -1)
+	if err != nil {
+		return "", err
+	}
+	return cid.NewCidV1(cid.Raw, hash).StringOfBase(multibase.Base32)
+}
+
+// CIDFromDigest returns the CID, as made by [CID], of the data whose sha256
+// digest is [digest]. It lets large content be addressed while streaming it.
+func CIDFromDigest(digest []byte) (string, error) {
+	if len(digest) != sha256.Size {
+		return "", ErrInvalidCID
+	}
+	hash, err := mh.Encode(digest, mh.SHA2_256)
+	if err != nil {
+		return "", err
+	}
+	return cid.NewCidV1(cid.Raw, hash).StringOfBase(multibase.Base32)
+}
+
+// Multihash returns the multihash of the CID [c], in any version and base.
+// CIDs of the same content hashed the same way share it, so it identifies
+// notarized data whatever form its CID was submitted in.
+func Multihash(c string) ([]byte, error) {
+	parsed, err := cid.Decode(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	return parsed.Hash(), nil
+}
+
+// Digest returns the sha256 digest of the content [c] addresses. Only CIDs
+// made by [CID] can be verified this way, others (e.g. UnixFS files) would
+// need their DAG to be rebuilt.
+func Digest(c string) ([]byte, error) {
+	parsed, err := cid.Decode(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	if parsed.Type() != cid.Raw {
+		return nil, ErrUnsupportedCID
+	}
+	decoded, err := mh.Decode(parsed.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	if decoded.Code != mh.SHA2_256 || len(decoded.Digest) != sha256.Size {
+		return nil, ErrUnsupportedCID
+	}
+	return decoded.Digest, nil
+}
+
+// ParseRef returns the CID of a content reference: a bare CID, an ipfs://
+// URI or a gateway URL (https://host/ipfs/<cid>), which is how updates used
+// to reference their executable.
+//
+// Those updates were published as UnixFS files (CIDv0), which [Digest] can't
+// verify. ParseRef fails with [ErrLegacyRef] for gateway URLs of such
+// content: it has to be fetched from the URL and checked against a digest
+// recorded elsewhere.
+func ParseRef(ref string) (string, error) {
+	url := strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://")
+	c := strings.TrimPrefix(ref, "ipfs://")
+	if i := strings.LastIndex(c, "/ipfs/"); i >= 0 {
+		c = c[i+len("/ipfs/"):]
+	}
+	c = strings.TrimSuffix(c, "/")
+	if _, err := Digest(c); err != nil {
+		if url && errors.Is(err, ErrUnsupportedCID) {
+			return "", fmt.Errorf("%w: %s", ErrLegacyRef, ref)
+		}
+		return "", err
+	}
+	return c, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+)
+
+func TestMultihash(t *testing.T) {
+	c, err := CID([]byte("sensor reading"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Multihash(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := cid.Decode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base58, err := parsed.StringOfBase(multibase.Base58BTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range []string{base58, cid.NewCidV0(parsed.Hash()).String()} {
+		got, err := Multihash(other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("expected %s to have the multihash of %s", other, c)
+		}
+	}
+	if _, err := Multihash("not a cid"); !errors.Is(err, ErrInvalidCID) {
+		t.Fatalf("expected invalid CID, got %v", err)
+	}
+}
+
+func TestParseLegacyRef(t *testing.T) {
+	// The executable of updates published as UnixFS files
+	legacy := "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+	if _, err := ParseRef("https://ipfs.io/ipfs/" + legacy); !errors.Is(err, ErrLegacyRef) {
+		t.Fatalf("expected legacy ref, got %v", err)
+	}
+	for _, ref := range []string{legacy, "ipfs://" + legacy} {
+		if _, err := ParseRef(ref); !errors.Is(err, ErrUnsupportedCID) {
+			t.Fatalf("expected unsupported CID for %s, got %v", ref, err)
+		}
+	}
+	if _, err := ParseRef("https://ipfs.io/ipfs/not-a-cid"); !errors.Is(err, ErrInvalidCID) {
+		t.Fatalf("expected invalid CID, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	LocalStoreType = "local"
+	IPFSStoreType  = "ipfs"
+	S3StoreType    = "s3"
+
+	// Credentials are read from the environment when they are not in the
+	// config, so they never need to be written to disk.
+	IPFSAuthEnv       = "DATAVERSE_IPFS_AUTH"
+	S3AccessKeyEnv    = "AWS_ACCESS_KEY_ID"
+	S3SecretKeyEnv    = "AWS_SECRET_ACCESS_KEY"
+	S3SessionTokenEnv = "AWS_SESSION_TOKEN"
+
+	defaultS3Region = "us-east-1"
+)
+
+var (
+	ErrUnknownStoreType   = errors.New("unknown content store type")
+	ErrInvalidStoreConfig = errors.New("invalid content store config")
+)
+
+// StoreConfig selects and configures a [ContentStore], e.g.
+//
+//	{"type": "local", "path": "/var/lib/dataverse/content"}
+//	{"type": "ipfs", "api": "http://127.0.0.1:5001"}
+//	{"type": "s3", "endpoint": "https://s3.us-east-1.amazonaws.com", "bucket": "firmware", "region": "us-east-1"}
+type StoreConfig struct {
+	Type string `json:"type"`
+
+	// local
+	Path string `json:"path,omitempty"`
+
+	// ipfs, [Auth] defaults to $DATAVERSE_IPFS_AUTH
+	API  string `json:"api,omitempty"`
+	Auth string `json:"auth,omitempty"`
+
+	// s3, credentials default to $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY
+	// and $AWS_SESSION_TOKEN
+	Endpoint     string `json:"endpoint,omitempty"`
+	Bucket       string `json:"bucket,omitempty"`
+	Region       string `json:"region,omitempty"`
+	Prefix       string `json:"prefix,omitempty"`
+	AccessKey    string `json:"access_key,omitempty"`
+	SecretKey    string `json:"secret_key,omitempty"`
+	SessionToken string `json:"session_token,omitempty"`
+}
+
+// LoadStoreConfig reads a [StoreConfig] from the JSON file at [path].
+func LoadStoreConfig(path string) (StoreConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return StoreConfig{}, err
+	}
+	var c StoreConfig
+	if err := json.Unmarshal(b, &c); err != nil {
+		return StoreConfig{}, err
+	}
+	return c, nil
+}
+
+// DefaultStoreConfig is a local store in [dir].
+func DefaultStoreConfig(dir string) StoreConfig {
+	return StoreConfig{Type: LocalStoreType, Path: dir}
+}
+
+// NewStore returns the store configured by [c].
+func NewStore(c StoreConfig) (ContentStore, error) {
+	switch c.Type {
+	case LocalStoreType:
+		if len(c.Path) == 0 {
+			return nil, fmt.Errorf("%w: local store needs a path", ErrInvalidStoreConfig)
+		}
+		return NewLocalStore(c.Path)
+	case IPFSStoreType:
+		if len(c.API) == 0 {
+			return nil, fmt.Errorf("%w: ipfs store needs an api", ErrInvalidStoreConfig)
+		}
+		return NewIPFSStore(c.API, orEnv(c.Auth, IPFSAuthEnv)), nil
+	case S3StoreType:
+		if len(c.Endpoint) == 0 || len(c.Bucket) == 0 {
+			return nil, fmt.Errorf("%w: s3 store needs an endpoint and a bucket", ErrInvalidStoreConfig)
+		}
+		region := c.Region
+		if len(region) == 0 {
+			region = defaultS3Region
+		}
+		creds := S3Credentials{
+			AccessKey:    orEnv(c.AccessKey, S3AccessKeyEnv),
+			SecretKey:    orEnv(c.SecretKey, S3SecretKeyEnv),
+			SessionToken: orEnv(c.SessionToken, S3SessionTokenEnv),
+		}
+		return NewS3Store(c.Endpoint, c.Bucket, region, c.Prefix, creds), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStoreType, c.Type)
+	}
+}
+
+func orEnv(v string, env string) string {
+	if len(v) > 0 {
+		return v
+	}
+	return os.Getenv(env)
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+// Content keys are delivered to buyers sealed to their ed25519 auth key. The
+// key is converted to its X25519 form, an ephemeral X25519 key agrees on a
+// shared secret with it and the content key is encrypted with AES-256-GCM
+// under sha256(shared secret | ephemeral key | recipient key).
+//
+// An envelope is laid out as:
+//
+//	ephemeral key (32) | nonce (12) | encrypted content key | tag (16)
+//
+// [context] is authenticated but not stored, it binds an envelope to what it
+// was sealed for (e.g. a listing) so it can't be replayed elsewhere.
+
+const (
+	// MaxContentKeyLen is the largest content key that can be sealed.
+	MaxContentKeyLen = 64
+
+	x25519KeyLen = 32
+	nonceLen     = 12
+	tagLen       = 16
+
+	// EnvelopeOverhead is how many bytes an envelope adds to the content key.
+	EnvelopeOverhead = x25519KeyLen + nonceLen + tagLen
+)
+
+var (
+	ErrInvalidContentKey = errors.New("invalid content key")
+	ErrInvalidEnvelope   = errors.New("invalid envelope")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+)
+
+// curve25519P is the field prime 2^255 - 19.
+var curve25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// SealKey encrypts [key] to [recipient] for [context].
+func SealKey(recipient ed25519.PublicKey, context []byte, key []byte) ([]byte, error) {
+	if len(key) == 0 || len(key) > MaxContentKeyLen {
+		return nil, ErrInvalidContentKey
+	}
+	pub, err := x25519PublicKey(recipient)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := envelopeCipher(shared, ephemeral.PublicKey(), pub)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, x25519KeyLen+nonceLen, EnvelopeOverhead+len(key))
+	copy(envelope, ephemeral.PublicKey().Bytes())
+	nonce := envelope[x25519KeyLen:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(envelope, nonce, key, context), nil
+}
+
+// OpenKey decrypts the content key in [envelope] with [priv], the ed25519 key
+// it was sealed to.
+func OpenKey(priv ed25519.PrivateKey, context []byte, envelope []byte) ([]byte, error) {
+	if !ValidEnvelope(envelope) {
+		return nil, ErrInvalidEnvelope
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope[:x25519KeyLen])
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	x, err := x25519PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := x.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	aead, err := envelopeCipher(shared, ephemeral, x.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	nonce := envelope[x25519KeyLen : x25519KeyLen+nonceLen]
+	key, err := aead.Open(nil, nonce, envelope[x25519KeyLen+nonceLen:], context)
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	return key, nil
+}
+
+// ValidEnvelope reports whether [envelope] has the size of a sealed content
+// key. It does not check that it can be opened.
+func ValidEnvelope(envelope []byte) bool {
+	return len(envelope) > EnvelopeOverhead && len(envelope) <= EnvelopeOverhead+MaxContentKeyLen
+}
+
+// envelopeCipher returns the AEAD keyed by the secret shared between the
+// ephemeral key and the recipient key.
+func envelopeCipher(shared []byte, ephemeral *ecdh.PublicKey, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write(shared)
+	h.Write(ephemeral.Bytes())
+	h.Write(recipient.Bytes())
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// x25519PublicKey converts an ed25519 public key to the X25519 public key of
+// the same secret: u = (1 + y) / (1 - y).
+func x25519PublicKey(pk ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	le := pk
+	le[31] &= 0x7f // drop the sign of x
+	y := new(big.Int).SetBytes(reverse(le[:]))
+	if y.Cmp(curve25519P) >= 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, den.ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+
+	b := make([]byte, x25519KeyLen)
+	u.FillBytes(b)
+	return ecdh.X25519().NewPublicKey(reverse(b))
+}
+
+// x25519PrivateKey converts an ed25519 private key to its X25519 form, the
+// clamped scalar ed25519 derives from the seed.
+func x25519PrivateKey(priv ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	h := sha512.Sum512(priv[:ed25519.PrivateKeySeedLen])
+	return ecdh.X25519().NewPrivateKey(h[:x25519KeyLen])
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
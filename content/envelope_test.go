@@ -0,0 +1,44 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestEnvelope(t *testing.T) {
+	priv, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{0xab}, 32)
+	envelope, err := SealKey(priv.PublicKey(), []byte("listing"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope) != EnvelopeOverhead+len(key) || !ValidEnvelope(envelope) {
+		t.Fatalf("unexpected envelope size %d", len(envelope))
+	}
+	opened, err := OpenKey(priv, []byte("listing"), envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, key) {
+		t.Fatal("opened key does not match sealed key")
+	}
+
+	if _, err := OpenKey(priv, []byte("other listing"), envelope); err == nil {
+		t.Fatal("expected error for wrong context")
+	}
+	other, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenKey(other, []byte("listing"), envelope); err == nil {
+		t.Fatal("expected error for wrong recipient")
+	}
+}
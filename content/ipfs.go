@@ -0,0 +1,167 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var _ ContentStore = (*IPFSStore)(nil)
+
+// MaxIPFSBlockSize is the size limit of content put in an [IPFSStore].
+const MaxIPFSBlockSize = 1 << 20
+
+// IPFSStore keeps content on an IPFS node through its HTTP RPC API (e.g.
+// http://127.0.0.1:5001), whether run locally or by a pinning service.
+//
+// Content is stored as a single raw block rather than a UnixFS file, so its
+// CID is the one computed locally and can be verified without rebuilding a
+// DAG. Chunking it with "add" would give it the CID of a DAG instead, which
+// can't be checked against the digest on chain, so content is limited to
+// [MaxIPFSBlockSize], the largest block the network exchanges over bitswap.
+// Larger executables must be kept in a local or S3 store.
+type IPFSStore struct {
+	api    string
+	auth   string
+	client *http.Client
+}
+
+// NewIPFSStore returns a store using the RPC API at [api]. [auth], if not
+// empty, is sent as the Authorization header (e.g. "Basic ...").
+func NewIPFSStore(api string, auth string) *IPFSStore {
+	return &IPFSStore{
+		api:    strings.TrimSuffix(api, "/"),
+		auth:   auth,
+		client: &http.Client{},
+	}
+}
+
+type ipfsBlockStat struct {
+	Key  string `json:"Key"`
+	Size int64  `json:"Size"`
+}
+
+type ipfsError struct {
+	Message string `json:"Message"`
+}
+
+// call posts to the RPC [method] and returns the response body, which the
+// caller must close, if it succeeded.
+func (s *IPFSStore) call(ctx context.Context, method string, args url.Values, body io.Reader, contentType string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.api+"/api/v0/"+method+"?"+args.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(s.auth) > 0 {
+		req.Header.Set("Authorization", s.auth)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+	var ipfsErr ipfsError
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(b, &ipfsErr) != nil {
+		ipfsErr.Message = string(b)
+	}
+	if strings.Contains(ipfsErr.Message, "not found") {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("ipfs %s failed with status %d: %s", method, resp.StatusCode, ipfsErr.Message)
+}
+
+func (s *IPFSStore) Put(ctx context.Context, r io.Reader) (string, error) {
+	// Reading one more byte than the limit is enough to tell content is too
+	// large, without spooling all of it
+	f, info, err := spool("", io.LimitReader(r, MaxIPFSBlockSize+1))
+	if err != nil {
+		return "", err
+	}
+	defer removeSpool(f)
+	if info.Size > MaxIPFSBlockSize {
+		return "", fmt.Errorf("%w: ipfs blocks are limited to %d bytes", ErrContentTooLarge, MaxIPFSBlockSize)
+	}
+
+	// The block is streamed to the node instead of being buffered
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		part, err := writer.CreateFormFile("file", info.CID)
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	args := url.Values{}
+	args.Set("cid-codec", "raw")
+	args.Set("mhtype", "sha2-256")
+	args.Set("pin", "true")
+	body, err := s.call(ctx, "block/put", args, pr, writer.FormDataContentType())
+	if err != nil {
+		pr.CloseWithError(err)
+		return "", err
+	}
+	defer body.Close()
+	var stat ipfsBlockStat
+	if err := json.NewDecoder(body).Decode(&stat); err != nil {
+		return "", err
+	}
+	// The node may encode the CID differently, only the digest must match
+	stored, err := Digest(stat.Key)
+	if err != nil {
+		return "", err
+	}
+	expected, _ := Digest(info.CID)
+	if !bytes.Equal(stored, expected) {
+		return "", ErrCIDMismatch
+	}
+	return info.CID, nil
+}
+
+func (s *IPFSStore) Get(ctx context.Context, cid string) (io.ReadCloser, error) {
+	if _, err := Digest(cid); err != nil {
+		return nil, err
+	}
+	body, err := s.call(ctx, "block/get", url.Values{"arg": {cid}}, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(cid, body)
+}
+
+func (s *IPFSStore) Stat(ctx context.Context, cid string) (Info, error) {
+	if _, err := Digest(cid); err != nil {
+		return Info{}, err
+	}
+	// Only look at the node, not the network, for whether it has the block
+	body, err := s.call(ctx, "block/stat", url.Values{"arg": {cid}, "offline": {"true"}}, nil, "")
+	if err != nil {
+		return Info{}, err
+	}
+	defer body.Close()
+	var stat ipfsBlockStat
+	if err := json.NewDecoder(body).Decode(&stat); err != nil {
+		return Info{}, err
+	}
+	return Info{CID: cid, Size: stat.Size}, nil
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ ContentStore = (*S3Store)(nil)
+
+const (
+	s3Service       = "s3"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3DateLayout    = "20060102T150405Z"
+	s3DayLayout     = "20060102"
+	s3ContentSHA256 = "X-Amz-Content-Sha256"
+	s3Date          = "X-Amz-Date"
+	s3SecurityToken = "X-Amz-Security-Token"
+)
+
+// emptySHA256 is the payload hash of requests without a body.
+var emptySHA256 = hex.EncodeToString(sha256.New().Sum(nil))
+
+// S3Credentials sign requests to an S3-compatible store.
+type S3Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// S3Store keeps content as objects named [prefix]<cid> in a bucket of an
+// S3-compatible store (AWS S3, MinIO, R2...). Requests are addressed
+// path-style (https://endpoint/bucket/key) and signed with AWS Signature
+// Version 4.
+type S3Store struct {
+	endpoint string
+	bucket   string
+	region   string
+	prefix   string
+	creds    S3Credentials
+	client   *http.Client
+}
+
+func NewS3Store(endpoint, bucket, region, prefix string, creds S3Credentials) *S3Store {
+	return &S3Store{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   bucket,
+		region:   region,
+		prefix:   prefix,
+		creds:    creds,
+		client:   &http.Client{},
+	}
+}
+
+// do sends a signed request for the object of [cid]. [payloadHash] is the
+// hex sha256 of [body].
+func (s *S3Store) do(
+	ctx context.Context,
+	method string,
+	cid string,
+	body io.Reader,
+	size int64,
+	payloadHash string,
+) (*http.Response, error) {
+	u, err := url.Parse(s.endpoint + "/" + url.PathEscape(s.bucket) + "/" + url.PathEscape(s.prefix+cid))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	s.sign(req, payloadHash, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode/100 != 2:
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("s3 %s failed with status %d: %s", method, resp.StatusCode, b)
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to [req].
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format(s3DateLayout)
+	day := now.Format(s3DayLayout)
+	req.Header.Set(s3ContentSHA256, payloadHash)
+	req.Header.Set(s3Date, amzDate)
+	if len(s.creds.SessionToken) > 0 {
+		req.Header.Set(s3SecurityToken, s.creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.region + "/" + s3Service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.creds.SecretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.creds.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func (s *S3Store) Put(ctx context.Context, r io.Reader) (string, error) {
+	f, info, err := spool("", r)
+	if err != nil {
+		return "", err
+	}
+	defer removeSpool(f)
+	digest, err := Digest(info.CID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.do(ctx, http.MethodPut, info.CID, f, info.Size, hex.EncodeToString(digest))
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return info.CID, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, cid string) (io.ReadCloser, error) {
+	if _, err := Digest(cid); err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, cid, nil, 0, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(cid, resp.Body)
+}
+
+func (s *S3Store) Stat(ctx context.Context, cid string) (Info, error) {
+	if _, err := Digest(cid); err != nil {
+		return Info{}, err
+	}
+	resp, err := s.do(ctx, http.MethodHead, cid, nil, 0, emptySHA256)
+	if err != nil {
+		return Info{}, err
+	}
+	resp.Body.Close()
+	return Info{CID: cid, Size: resp.ContentLength}, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Content (update executables, data) is kept off-chain in a [ContentStore]
+// and referenced on-chain by its CID. CIDs are always computed locally, as
+// [CID] does, and content read back from a store is checked against its CID,
+// so a store never has to be trusted to return what was put in it.
+
+var (
+	ErrNotFound        = errors.New("content not found")
+	ErrCIDMismatch     = errors.New("content does not match its CID")
+	ErrContentTooLarge = errors.New("content too large for the store")
+)
+
+// Info describes stored content.
+type Info struct {
+	CID  string `json:"cid"`
+	Size int64  `json:"size"`
+}
+
+// ContentStore stores content by CID.
+type ContentStore interface {
+	// Put stores the content read from [r] and returns its CID.
+	Put(ctx context.Context, r io.Reader) (string, error)
+	// Get returns the content of [cid]. Reading it fails with
+	// [ErrCIDMismatch] once the content turns out not to match [cid], which
+	// is only known at EOF: content must be read in full before any of it
+	// is used or served, e.g. to a temporary file.
+	Get(ctx context.Context, cid string) (io.ReadCloser, error)
+	// Stat returns the size of the content of [cid], or [ErrNotFound].
+	Stat(ctx context.Context, cid string) (Info, error)
+}
+
+// spool copies [r] to a temporary file in [dir] (the default temporary
+// directory if empty) and returns it rewound, with the CID and size of its
+// content. The caller must close and remove the file.
+func spool(dir string, r io.Reader) (*os.File, Info, error) {
+	f, err := os.CreateTemp(dir, "content-*")
+	if err != nil {
+		return nil, Info{}, err
+	}
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	var c string
+	if err == nil {
+		c, err = CIDFromDigest(h.Sum(nil))
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, Info{}, err
+	}
+	return f, Info{CID: c, Size: size}, nil
+}
+
+func removeSpool(f *os.File) {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
+
+// verifyingReader hashes what is read through it and fails with
+// [ErrCIDMismatch] at EOF if it is not the expected content. What was read
+// before then is unverified.
+type verifyingReader struct {
+	r      io.ReadCloser
+	h      hash.Hash
+	digest []byte
+}
+
+// NewVerifyingReader wraps [r], the content of [cid], so it is checked as it
+// is read.
+func NewVerifyingReader(cid string, r io.ReadCloser) (io.ReadCloser, error) {
+	digest, err := Digest(cid)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{r: r, h: sha256.New(), digest: digest}, nil
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+	if errors.Is(err, io.EOF) && !bytes.Equal(v.h.Sum(nil), v.digest) {
+		return n, ErrCIDMismatch
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.r.Close()
+}
+
+var _ ContentStore = (*LocalStore)(nil)
+
+// LocalStore keeps content in files named after their CID in a directory.
+// It needs no network, so it is also how content is tested offline.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store in [dir], which is created if missing.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// path returns the file of [cid], CIDs are parsed first so they can't be
+// used to escape [dir].
+func (s *LocalStore) path(cid string) (string, error) {
+	if _, err := Digest(cid); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, cid), nil
+}
+
+func (s *LocalStore) Put(_ context.Context, r io.Reader) (string, error) {
+	f, info, err := spool(s.dir, r)
+	if err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, info.CID)); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return info.CID, nil
+}
+
+func (s *LocalStore) Get(_ context.Context, cid string) (io.ReadCloser, error) {
+	p, err := s.path(cid)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(cid, f)
+}
+
+func (s *LocalStore) Stat(_ context.Context, cid string) (Info, error) {
+	p, err := s.path(cid)
+	if err != nil {
+		return Info{}, err
+	}
+	fi, err := os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return Info{}, ErrNotFound
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{CID: cid, Size: fi.Size()}, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewStore(DefaultStoreConfig(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("firmware"), 1024)
+	c, err := store.Put(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := CID(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != expected {
+		t.Fatalf("stored as %s, expected %s", c, expected)
+	}
+	info, err := store.Stat(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(data)) {
+		t.Fatalf("unexpected size %d", info.Size)
+	}
+	r, err := store.Get(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Fatal("read content does not match")
+	}
+
+	for _, ref := range []string{c, "ipfs://" + c, "https://gateway.example/ipfs/" + c} {
+		parsed, err := ParseRef(ref)
+		if err != nil || parsed != c {
+			t.Fatalf("unexpected ref %s: %s %v", ref, parsed, err)
+		}
+	}
+
+	missing, err := CID([]byte("missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Stat(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := store.Get(ctx, "../"+c); err == nil {
+		t.Fatal("expected error for invalid CID")
+	}
+
+	// Tampered content must not be returned as is
+	if err := os.WriteFile(filepath.Join(dir, c), []byte("tampered"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err = store.Get(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("expected CID mismatch, got %v", err)
+	}
+}
+
+func TestIPFSStoreBlockSize(t *testing.T) {
+	ctx := context.Background()
+	var puts int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		puts++
+		if r.URL.Path != "/api/v0/block/put" || r.URL.Query().Has("allow-big-block") {
+			t.Errorf("unexpected call %s", r.URL)
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		c, _ := CID(data)
+		json.NewEncoder(w).Encode(&ipfsBlockStat{Key: c, Size: int64(len(data))})
+	}))
+	defer node.Close()
+	store, err := NewStore(StoreConfig{Type: IPFSStoreType, API: node.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte{0x1}, MaxIPFSBlockSize)
+	c, err := store.Put(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, _ := CID(data); c != expected {
+		t.Fatalf("stored as %s, expected %s", c, expected)
+	}
+
+	// Larger content isn't sent to the node
+	data = append(data, 0x1)
+	if _, err := store.Put(ctx, bytes.NewReader(data)); !errors.Is(err, ErrContentTooLarge) {
+		t.Fatalf("expected %v, got %v", ErrContentTooLarge, err)
+	}
+	if puts != 1 {
+		t.Fatalf("expected 1 block put, got %d", puts)
+	}
+}
@@ -10,9 +10,11 @@ import (
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/gossiper"
 	hrpc "github.com/ava-labs/hypersdk/rpc"
 	hstorage "github.com/ava-labs/hypersdk/storage"
@@ -164,6 +166,20 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 	results := blk.Results()
 	for i, tx := range blk.Txs {
 		result := results[i]
+		storeProjectChange := func(project ids.ID, kind storage.ProjectChangeKind, subject codec.Address) error {
+			return storage.StoreProjectChange(ctx, batch, project, blk.Hght, uint32(i), storage.ProjectChange{
+				TxID:      tx.ID(),
+				Timestamp: blk.GetTimestamp(),
+				Kind:      kind,
+				Actor:     tx.Auth.Actor(),
+				Subject:   subject,
+			})
+		}
+		// Notarizations of data and of batches are indexed by the machine
+		// that signed them
+		storeMachineNotarization := func() error {
+			return storage.StoreMachineNotarization(ctx, batch, tx.Auth.Actor(), blk.Hght, uint32(i), tx.ID(), blk.GetTimestamp())
+		}
 		if c.config.GetStoreTransactions() {
 			err := storage.StoreTransaction(
 				ctx,
@@ -212,14 +228,70 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				c.metrics.exportAsset.Inc()
 			case *actions.CreateProject:
 				c.metrics.createProject.Inc()
+				if err := storeProjectChange(tx.ID(), storage.ProjectCreated, tx.Auth.Actor()); err != nil {
+					return err
+				}
 			case *actions.CreateUpdate:
 				c.metrics.createUpdate.Inc()
 			case *actions.RegisterMachine:
 				c.metrics.registerMachine.Inc()
 			case *actions.AttestMachine:
 				c.metrics.attestMachine.Inc()
-			case *actions.NotarizeData:
+			case *actions.OnboardMachine:
+				c.metrics.registerMachine.Inc()
 				c.metrics.attestMachine.Inc()
+				if action.Value > 0 {
+					c.metrics.transfer.Inc()
+				}
+			case *actions.NotarizeData:
+				c.metrics.notarizeData.Inc()
+				if err := storeMachineNotarization(); err != nil {
+					return err
+				}
+			case *actions.NotarizeBatch:
+				c.metrics.notarizeBatch.Inc()
+				if err := storeMachineNotarization(); err != nil {
+					return err
+				}
+			case *actions.SetSponsorship:
+				c.metrics.sponsorship.Inc()
+			case *actions.CreateManufacturer, *actions.AddManufacturerAttester, *actions.RevokeManufacturerAttester:
+				c.metrics.manufacturer.Inc()
+			case *actions.SetMetadataSchema:
+				c.metrics.metadataSchema.Inc()
+			case *actions.ReportUpdateResult:
+				c.metrics.reportUpdateResult.Inc()
+			case *actions.AddProjectMaintainer:
+				c.metrics.manageProject.Inc()
+				if err := storeProjectChange(action.Project, storage.ProjectMaintainerAdded, action.Maintainer); err != nil {
+					return err
+				}
+			case *actions.RemoveProjectMaintainer:
+				c.metrics.manageProject.Inc()
+				if err := storeProjectChange(action.Project, storage.ProjectMaintainerRemoved, action.Maintainer); err != nil {
+					return err
+				}
+			case *actions.TransferProjectOwnership:
+				c.metrics.manageProject.Inc()
+				if err := storeProjectChange(action.Project, storage.ProjectOwnershipTransferred, action.NewOwner); err != nil {
+					return err
+				}
+			case *actions.UpdateProjectMetadata:
+				c.metrics.manageProject.Inc()
+				if err := storeProjectChange(action.Project, storage.ProjectMetadataUpdated, codec.EmptyAddress); err != nil {
+					return err
+				}
+			case *actions.RevokeMachine, *actions.SuspendMachine, *actions.ReinstateMachine:
+				c.metrics.machineStatus.Inc()
+			case *actions.ListData, *actions.PurchaseData, *actions.DelistData, *actions.DeliverDataKey:
+				c.metrics.dataMarket.Inc()
+			case *actions.OfferLicense, *actions.PurchaseLicense, *actions.WithdrawLicenseOffer:
+				c.metrics.license.Inc()
+			case *actions.SetProjectReleaseKey:
+				c.metrics.manageProject.Inc()
+				if err := storeProjectChange(action.Project, storage.ProjectReleaseKeyChanged, codec.EmptyAddress); err != nil {
+					return err
+				}
 			}
 		}
 	}
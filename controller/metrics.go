@@ -30,6 +30,16 @@ type metrics struct {
 	registerMachine prometheus.Counter
 	attestMachine   prometheus.Counter
 	notarizeData    prometheus.Counter
+
+	reportUpdateResult prometheus.Counter
+	manageProject      prometheus.Counter
+	machineStatus      prometheus.Counter
+	dataMarket         prometheus.Counter
+	license            prometheus.Counter
+	notarizeBatch      prometheus.Counter
+	sponsorship        prometheus.Counter
+	manufacturer       prometheus.Counter
+	metadataSchema     prometheus.Counter
 }
 
 func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
@@ -104,6 +114,51 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "notarize_data",
 			Help:      "no of notarized assets",
 		}),
+		reportUpdateResult: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "report_update_result",
+			Help:      "no of update results reported by machines",
+		}),
+		manageProject: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "manage_project",
+			Help:      "no of project maintainer, ownership and metadata changes",
+		}),
+		machineStatus: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "machine_status",
+			Help:      "no of machine revocations, suspensions and reinstatements",
+		}),
+		dataMarket: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "data_market",
+			Help:      "no of data listings, purchases, delistings and key deliveries",
+		}),
+		license: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "license",
+			Help:      "no of license offers, purchases and withdrawals",
+		}),
+		notarizeBatch: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "machine",
+			Name:      "notarize_batch",
+			Help:      "no of notarized batches",
+		}),
+		sponsorship: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "machine",
+			Name:      "sponsorship",
+			Help:      "no of machine sponsorships set",
+		}),
+		manufacturer: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "machine",
+			Name:      "manufacturer",
+			Help:      "no of manufacturer creations and attester changes",
+		}),
+		metadataSchema: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "machine",
+			Name:      "metadata_schema",
+			Help:      "no of machine metadata schemas set",
+		}),
 	}
 	r := prometheus.NewRegistry()
 	errs := wrappers.Errs{}
@@ -125,6 +180,15 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 		r.Register(m.registerMachine),
 		r.Register(m.attestMachine),
 		r.Register(m.notarizeData),
+		r.Register(m.reportUpdateResult),
+		r.Register(m.manageProject),
+		r.Register(m.machineStatus),
+		r.Register(m.dataMarket),
+		r.Register(m.license),
+		r.Register(m.notarizeBatch),
+		r.Register(m.sponsorship),
+		r.Register(m.manufacturer),
+		r.Register(m.metadataSchema),
 		gatherer.Register(consts.Name, r),
 	)
 	return m, errs.Err
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 )
 
 func (c *Controller) Genesis() *genesis.Genesis {
@@ -95,6 +96,29 @@ func (c *Controller) GetUpdateFromState(
 	return storage.GetUpdateFromState(ctx, c.inner.ReadState, update)
 }
 
+func (c *Controller) GetLatestUpdateFromState(
+	ctx context.Context,
+	project ids.ID,
+	device []byte,
+) (bool, ids.ID, storage.Version, error) {
+	return storage.GetLatestUpdateFromState(ctx, c.inner.ReadState, project, device)
+}
+
+func (c *Controller) GetUpdateStatsFromState(
+	ctx context.Context,
+	update ids.ID,
+) (uint64, uint64, error) {
+	return storage.GetUpdateStatsFromState(ctx, c.inner.ReadState, update)
+}
+
+func (c *Controller) GetUpdateReportFromState(
+	ctx context.Context,
+	update ids.ID,
+	machine ids.ID,
+) (bool, storage.UpdateReportData, error) {
+	return storage.GetUpdateReportFromState(ctx, c.inner.ReadState, update, machine)
+}
+
 func (c *Controller) GetMachineCID(
 	ctx context.Context,
 	machinCIDID ids.ID,
@@ -103,12 +127,34 @@ func (c *Controller) GetMachineCID(
 	return storage.GetMachineCID(ctx, c.inner.ReadState, machinCIDID)
 }
 
+func (c *Controller) GetMachineCIDIndexFromState(
+	ctx context.Context,
+	machineCID []byte,
+) (bool, ids.ID, error) {
+	return storage.GetMachineIndexFromState(ctx, c.inner.ReadState, storage.MachineCIDIndexKey(machineCID))
+}
+
+func (c *Controller) GetMachineKeyIndexFromState(
+	ctx context.Context,
+	keyType uint8,
+	key []byte,
+) (bool, ids.ID, error) {
+	return storage.GetMachineIndexFromState(ctx, c.inner.ReadState, storage.MachineKeyIndexKey(keyType, key))
+}
+
+func (c *Controller) GetMachineAttestationFromState(
+	ctx context.Context,
+	machine codec.Address,
+) (bool, ids.ID, error) {
+	return storage.GetMachineIndexFromState(ctx, c.inner.ReadState, storage.MachineAttestationKey(machine))
+}
+
 func (c *Controller) GetAttestMachine(
 	ctx context.Context,
 	tx ids.ID,
 
 ) (bool, storage.AttestMachineData, error) {
-	return storage.GetAttestMachine(ctx, c.inner.ReadState, tx)
+	return storage.GetAttestMachineFromState(ctx, c.inner.ReadState, tx)
 }
 
 func (c *Controller) GetNotarizeData(
@@ -116,5 +162,114 @@ func (c *Controller) GetNotarizeData(
 	tx ids.ID,
 
 ) (bool, storage.NotarizeDataData, error) {
-	return storage.GetNotarizeData(ctx, c.inner.ReadState, tx)
+	return storage.GetNotarizeDataFromState(ctx, c.inner.ReadState, tx)
+}
+
+func (c *Controller) GetMachineNotarizations(
+	ctx context.Context,
+	machine codec.Address,
+	cursor []byte,
+	limit int,
+) ([]storage.NotarizationRef, []byte, error) {
+	return storage.GetMachineNotarizations(ctx, c.metaDB, machine, cursor, limit)
+}
+
+func (c *Controller) GetDataCIDNotarizationsFromState(
+	ctx context.Context,
+	multihash []byte,
+) ([]storage.NotarizationRef, error) {
+	return storage.GetDataCIDNotarizationsFromState(ctx, c.inner.ReadState, multihash)
+}
+
+func (c *Controller) GetProjectMaintainersFromState(
+	ctx context.Context,
+	project ids.ID,
+) ([]codec.Address, error) {
+	return storage.GetProjectMaintainersFromState(ctx, c.inner.ReadState, project)
+}
+
+func (c *Controller) GetProjectReleaseKeyFromState(
+	ctx context.Context,
+	project ids.ID,
+) (bool, ed25519.PublicKey, error) {
+	return storage.GetProjectReleaseKeyFromState(ctx, c.inner.ReadState, project)
+}
+
+func (c *Controller) GetProjectHistory(
+	ctx context.Context,
+	project ids.ID,
+	cursor []byte,
+	limit int,
+) ([]storage.ProjectChange, []byte, error) {
+	return storage.GetProjectHistory(ctx, c.metaDB, project, cursor, limit)
+}
+
+func (c *Controller) GetListingFromState(
+	ctx context.Context,
+	listing ids.ID,
+) (bool, storage.ListingData, error) {
+	return storage.GetListingFromState(ctx, c.inner.ReadState, listing)
+}
+
+func (c *Controller) GetPurchaseReceiptFromState(
+	ctx context.Context,
+	listing ids.ID,
+	buyer codec.Address,
+) (bool, storage.PurchaseReceiptData, error) {
+	return storage.GetPurchaseReceiptFromState(ctx, c.inner.ReadState, listing, buyer)
+}
+
+func (c *Controller) GetLicenseOfferFromState(
+	ctx context.Context,
+	offer ids.ID,
+) (bool, storage.LicenseOfferData, error) {
+	return storage.GetLicenseOfferFromState(ctx, c.inner.ReadState, offer)
+}
+
+func (c *Controller) GetLicenseFromState(
+	ctx context.Context,
+	subject ids.ID,
+	licensee codec.Address,
+) (bool, storage.LicenseData, error) {
+	return storage.GetLicenseFromState(ctx, c.inner.ReadState, subject, licensee)
+}
+
+func (c *Controller) GetTimestampFromState(ctx context.Context) (int64, error) {
+	return storage.GetTimestampFromState(ctx, c.inner.ReadState)
+}
+
+func (c *Controller) GetKeyEnvelopeFromState(
+	ctx context.Context,
+	listing ids.ID,
+	buyer codec.Address,
+) (bool, storage.KeyEnvelopeData, error) {
+	return storage.GetKeyEnvelopeFromState(ctx, c.inner.ReadState, listing, buyer)
+}
+
+func (c *Controller) GetNotarizeBatchFromState(
+	ctx context.Context,
+	tx ids.ID,
+) (bool, storage.NotarizeBatchData, error) {
+	return storage.GetNotarizeBatchFromState(ctx, c.inner.ReadState, tx)
+}
+
+func (c *Controller) GetSponsorshipFromState(
+	ctx context.Context,
+	attestTx ids.ID,
+) (bool, storage.SponsorshipData, error) {
+	return storage.GetSponsorshipFromState(ctx, c.inner.ReadState, attestTx)
+}
+
+func (c *Controller) GetManufacturerFromState(
+	ctx context.Context,
+	name []byte,
+) (bool, storage.ManufacturerData, error) {
+	return storage.GetManufacturerFromState(ctx, c.inner.ReadState, name)
+}
+
+func (c *Controller) GetMetadataSchemaFromState(
+	ctx context.Context,
+	category []byte,
+) (bool, storage.MetadataSchemaData, error) {
+	return storage.GetMetadataSchemaFromState(ctx, c.inner.ReadState, category)
 }
@@ -0,0 +1,164 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+var (
+	ErrUnauthorized = errors.New("missing or invalid credentials")
+	ErrInvalidJWT   = errors.New("invalid jwt")
+)
+
+type principalKey struct{}
+
+// Principal returns who the request was authenticated as: "api_key:<name>",
+// "jwt:<subject>" or "" on public routes.
+func Principal(ctx context.Context) string {
+	p, _ := ctx.Value(principalKey{}).(string)
+	return p
+}
+
+// authenticate returns the principal of [r] if it has credentials accepted
+// by [methods].
+func (g *Gateway) authenticate(r *http.Request, methods []string) (string, error) {
+	for _, method := range methods {
+		switch method {
+		case AuthNone:
+			return "", nil
+		case AuthAPIKey:
+			if key := r.Header.Get(apiKeyHeader); len(key) > 0 {
+				if name, ok := g.apiKey(key); ok {
+					return AuthAPIKey + ":" + name, nil
+				}
+			}
+		case AuthJWT:
+			if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix); ok {
+				if subject, err := g.verifyJWT(token, time.Now()); err == nil {
+					return AuthJWT + ":" + subject, nil
+				}
+			}
+		}
+	}
+	return "", ErrUnauthorized
+}
+
+// apiKey returns the name of [key] if it is configured. All keys are
+// compared in constant time so timing doesn't tell which exist.
+func (g *Gateway) apiKey(key string) (string, bool) {
+	digest := sha256.Sum256([]byte(key))
+	var name string
+	for _, configured := range g.apiKeys {
+		if subtle.ConstantTimeCompare(digest[:], configured.digest) == 1 {
+			name = configured.name
+		}
+	}
+	return name, len(name) > 0
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt int64           `json:"exp"`
+	NotBefore int64           `json:"nbf"`
+}
+
+// hasAudience reports whether [aud], a string or a list of strings, has
+// [audience].
+func hasAudience(aud json.RawMessage, audience string) bool {
+	var one string
+	if json.Unmarshal(aud, &one) == nil {
+		return one == audience
+	}
+	var many []string
+	if json.Unmarshal(aud, &many) != nil {
+		return false
+	}
+	for _, a := range many {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyJWT returns the subject of [token] if it is signed with the
+// configured secret and valid at [now]. Tokens must expire.
+func (g *Gateway) verifyJWT(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidJWT
+	}
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return "", ErrInvalidJWT
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidJWT
+	}
+	mac := hmac.New(sha256.New, g.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", ErrInvalidJWT
+	}
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return "", ErrInvalidJWT
+	}
+	switch {
+	case claims.ExpiresAt == 0 || now.Unix() >= claims.ExpiresAt:
+		return "", ErrInvalidJWT
+	case claims.NotBefore != 0 && now.Unix() < claims.NotBefore:
+		return "", ErrInvalidJWT
+	case len(g.cfg.JWT.Issuer) > 0 && claims.Issuer != g.cfg.JWT.Issuer:
+		return "", ErrInvalidJWT
+	case len(g.cfg.JWT.Audience) > 0 && !hasAudience(claims.Audience, g.cfg.JWT.Audience):
+		return "", ErrInvalidJWT
+	}
+	return claims.Subject, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+type apiKey struct {
+	name   string
+	digest []byte
+}
+
+func parseAPIKeys(keys []APIKey) []apiKey {
+	parsed := make([]apiKey, len(keys))
+	for i, key := range keys {
+		digest, _ := hex.DecodeString(key.SHA256) // checked by [Config.Verify]
+		parsed[i] = apiKey{name: key.Name, digest: digest}
+	}
+	return parsed
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// AuthNone makes a route public.
+	AuthNone = "none"
+	// AuthAPIKey accepts a key of [Config.APIKeys] in the X-API-Key header.
+	AuthAPIKey = "api_key"
+	// AuthJWT accepts an HS256 JWT in the Authorization header.
+	AuthJWT = "jwt"
+
+	defaultListenAddress   = "127.0.0.1:8080"
+	defaultShutdownTimeout = 30 * time.Second
+	defaultMaxBodyBytes    = 32 << 20
+)
+
+var ErrInvalidConfig = errors.New("invalid gateway config")
+
+// Duration is a [time.Duration] written as a string (e.g. "30s") in JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	parsed, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Config configures a [Gateway]. Routes not listed in [Routes] use
+// [DefaultRoute], which requires an API key or a JWT unless configured
+// otherwise, so nothing is exposed without auth by accident.
+type Config struct {
+	ListenAddress string `json:"listen_address"`
+	TLS           struct {
+		CertFile string `json:"cert_file"`
+		KeyFile  string `json:"key_file"`
+	} `json:"tls"`
+	// [Insecure] allows serving without TLS on a non-loopback address, e.g.
+	// behind a reverse proxy that terminates TLS.
+	Insecure bool `json:"insecure"`
+
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	MaxBodyBytes    int64    `json:"max_body_bytes"`
+
+	APIKeys []APIKey  `json:"api_keys"`
+	JWT     JWTConfig `json:"jwt"`
+
+	DefaultRoute RouteConfig            `json:"default_route"`
+	Routes       map[string]RouteConfig `json:"routes"`
+}
+
+// APIKey authorizes the holder of the key whose sha256 digest is [SHA256].
+// Only digests are configured so config files hold no secrets.
+type APIKey struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// JWTConfig verifies JWTs signed with HS256 by the holder of the secret in
+// the [SecretEnv] environment variable.
+type JWTConfig struct {
+	SecretEnv string `json:"secret_env"`
+	Issuer    string `json:"issuer"`
+	Audience  string `json:"audience"`
+}
+
+// RouteConfig is how a route is authenticated and rate limited.
+type RouteConfig struct {
+	// [Auth] lists the accepted auth methods, [AuthNone] makes the route
+	// public.
+	Auth      []string  `json:"auth"`
+	RateLimit RateLimit `json:"rate_limit"`
+}
+
+// RateLimit allows each client of a route [PerSecond] requests per second on
+// average, with bursts of up to [Burst]. A zero [PerSecond] disables it.
+type RateLimit struct {
+	PerSecond float64 `json:"per_second"`
+	Burst     int     `json:"burst"`
+}
+
+// DefaultConfig listens on loopback and requires an API key or a JWT on
+// every route.
+func DefaultConfig() Config {
+	return Config{
+		ListenAddress:   defaultListenAddress,
+		ShutdownTimeout: Duration(defaultShutdownTimeout),
+		MaxBodyBytes:    defaultMaxBodyBytes,
+		DefaultRoute: RouteConfig{
+			Auth:      []string{AuthAPIKey, AuthJWT},
+			RateLimit: RateLimit{PerSecond: 10, Burst: 20},
+		},
+	}
+}
+
+// LoadConfig reads a [Config] from the JSON file at [path], fields it omits
+// keep their [DefaultConfig] value.
+func LoadConfig(path string) (Config, error) {
+	c := DefaultConfig()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Config{}, err
+	}
+	return c, c.Verify()
+}
+
+// route returns the config of [path].
+func (c *Config) route(path string) RouteConfig {
+	if route, ok := c.Routes[path]; ok {
+		return route
+	}
+	return c.DefaultRoute
+}
+
+func (c *Config) jwtSecret() []byte {
+	if len(c.JWT.SecretEnv) == 0 {
+		return nil
+	}
+	return []byte(os.Getenv(c.JWT.SecretEnv))
+}
+
+// Verify checks that [c] can be served safely.
+func (c *Config) Verify() error {
+	host, _, err := net.SplitHostPort(c.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("%w: listen address: %v", ErrInvalidConfig, err)
+	}
+	hasTLS := len(c.TLS.CertFile) > 0 || len(c.TLS.KeyFile) > 0
+	if hasTLS && (len(c.TLS.CertFile) == 0 || len(c.TLS.KeyFile) == 0) {
+		return fmt.Errorf("%w: tls needs both a cert_file and a key_file", ErrInvalidConfig)
+	}
+	if ip := net.ParseIP(host); !hasTLS && !c.Insecure && host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%w: serving on %s without tls needs insecure to be set", ErrInvalidConfig, c.ListenAddress)
+	}
+	for _, key := range c.APIKeys {
+		if digest, err := hex.DecodeString(key.SHA256); err != nil || len(digest) != 32 || len(key.Name) == 0 {
+			return fmt.Errorf("%w: api key %q must have a name and a hex sha256", ErrInvalidConfig, key.Name)
+		}
+	}
+	if err := c.verifyRoute("default", c.DefaultRoute); err != nil {
+		return err
+	}
+	for path, route := range c.Routes {
+		if err := c.verifyRoute(path, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) verifyRoute(path string, route RouteConfig) error {
+	if len(route.Auth) == 0 {
+		return fmt.Errorf("%w: route %s has no auth, use %q to make it public", ErrInvalidConfig, path, AuthNone)
+	}
+	for _, method := range route.Auth {
+		switch method {
+		case AuthNone:
+			if len(route.Auth) > 1 {
+				return fmt.Errorf("%w: route %s can't be both public and authenticated", ErrInvalidConfig, path)
+			}
+		case AuthAPIKey:
+			if len(c.APIKeys) == 0 {
+				return fmt.Errorf("%w: route %s accepts api keys but none are configured", ErrInvalidConfig, path)
+			}
+		case AuthJWT:
+			if len(c.jwtSecret()) == 0 {
+				return fmt.Errorf("%w: route %s accepts jwts but $%s is empty", ErrInvalidConfig, path, c.JWT.SecretEnv)
+			}
+		default:
+			return fmt.Errorf("%w: route %s has unknown auth %q", ErrInvalidConfig, path, method)
+		}
+	}
+	if route.RateLimit.PerSecond < 0 || (route.RateLimit.PerSecond > 0 && route.RateLimit.Burst < 1) {
+		return fmt.Errorf("%w: route %s rate limit needs a burst of at least 1", ErrInvalidConfig, path)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ErrorReply is the body of every error response of the gateway.
+type ErrorReply struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
+
+// Error replies to the request with the JSON error [msg] and [status]. It
+// takes the same arguments as [http.Error] so handlers can use either.
+func Error(w http.ResponseWriter, msg string, status int) {
+	h := w.Header()
+	// Drop headers set for a successful reply (e.g. Content-Length)
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorReply{Error: msg, Status: status})
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Gateway serves HTTP routes, each authenticated and rate limited as
+// configured, with JSON errors. It is how the chain is exposed to devices and
+// operators over HTTP.
+type Gateway struct {
+	cfg       Config
+	apiKeys   []apiKey
+	jwtSecret []byte
+	mux       *http.ServeMux
+	routes    map[string]struct{}
+}
+
+// New returns a gateway for [cfg], which must pass [Config.Verify].
+func New(cfg Config) (*Gateway, error) {
+	if err := cfg.Verify(); err != nil {
+		return nil, err
+	}
+	g := &Gateway{
+		cfg:       cfg,
+		apiKeys:   parseAPIKeys(cfg.APIKeys),
+		jwtSecret: cfg.jwtSecret(),
+		mux:       http.NewServeMux(),
+		routes:    map[string]struct{}{},
+	}
+	g.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, "route not found", http.StatusNotFound)
+	})
+	return g, nil
+}
+
+// Handle serves [h] on exactly [path], with the auth and rate limit
+// configured for it.
+func (g *Gateway) Handle(path string, h http.Handler) {
+	route := g.cfg.route(path)
+	limit := newLimiter(route.RateLimit)
+	// Failed authentications are limited per address, and an address that
+	// ran out is refused before its credentials are checked, so they can't
+	// be guessed faster than the route allows
+	failures := newLimiter(route.RateLimit)
+	g.routes[path] = struct{}{}
+	g.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		// Paths ending in / would otherwise match everything under them
+		if r.URL.Path != path {
+			Error(w, "route not found", http.StatusNotFound)
+			return
+		}
+		now := time.Now()
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		if failures.exhausted(host, now) {
+			rateLimited(w)
+			return
+		}
+		principal, err := g.authenticate(r, route.Auth)
+		if err != nil {
+			failures.allow(host, now)
+			w.Header().Set("WWW-Authenticate", bearerPrefix[:len(bearerPrefix)-1])
+			Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		client := principal
+		if len(client) == 0 {
+			client = host
+		}
+		if !limit.allow(client, now) {
+			rateLimited(w)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, g.cfg.MaxBodyBytes)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+func rateLimited(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+}
+
+// HandleFunc serves [f] on [path], see [Gateway.Handle].
+func (g *Gateway) HandleFunc(path string, f http.HandlerFunc) {
+	g.Handle(path, f)
+}
+
+// ServeHTTP serves the routes of the gateway. A handler that panics gets a
+// JSON error instead of a dropped connection.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if err := recover(); err != nil {
+			if err == http.ErrAbortHandler { //nolint:errorlint
+				panic(err)
+			}
+			Error(w, "internal error", http.StatusInternalServerError)
+		}
+	}()
+	g.mux.ServeHTTP(w, r)
+}
+
+// Run serves the gateway until [ctx] is done, then stops accepting requests
+// and waits up to [Config.ShutdownTimeout] for those in flight.
+func (g *Gateway) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              g.cfg.ListenAddress,
+		Handler:           g,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		if len(g.cfg.TLS.CertFile) > 0 {
+			errs <- server.ListenAndServeTLS(g.cfg.TLS.CertFile, g.cfg.TLS.KeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(g.cfg.ShutdownTimeout))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("gateway shutdown: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Routes returns the paths served by the gateway.
+func (g *Gateway) Routes() []string {
+	paths := make([]string, 0, len(g.routes))
+	for path := range g.routes {
+		paths = append(paths, path)
+	}
+	return paths
+}
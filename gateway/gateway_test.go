@@ -0,0 +1,153 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testJWTSecretEnv = "GATEWAY_TEST_JWT_SECRET"
+
+func testJWT(secret string, claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + payload))
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestGateway(t *testing.T) {
+	t.Setenv(testJWTSecretEnv, "secret")
+	digest := sha256.Sum256([]byte("key"))
+	cfg := DefaultConfig()
+	cfg.APIKeys = []APIKey{{Name: "ci", SHA256: hex.EncodeToString(digest[:])}}
+	cfg.JWT = JWTConfig{SecretEnv: testJWTSecretEnv, Audience: "dataverse"}
+	cfg.Routes = map[string]RouteConfig{
+		"/public": {Auth: []string{AuthNone}, RateLimit: RateLimit{PerSecond: 1, Burst: 2}},
+	}
+	g, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Principal(r.Context()))
+	}
+	g.HandleFunc("/private", ok)
+	g.HandleFunc("/public", ok)
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/private", nil)
+	var reply ErrorReply
+	if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil || w.Code != http.StatusUnauthorized ||
+		reply.Status != http.StatusUnauthorized {
+		t.Fatalf("expected json unauthorized error, got %d %s", w.Code, w.Body)
+	}
+	if w := serve("/private", http.Header{"X-Api-Key": {"wrong"}}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized, got %d", w.Code)
+	}
+	if w := serve("/private", http.Header{"X-Api-Key": {"key"}}); w.Code != http.StatusOK || w.Body.String() != "api_key:ci" {
+		t.Fatalf("expected api key auth, got %d %s", w.Code, w.Body)
+	}
+
+	exp := time.Now().Add(time.Minute).Unix()
+	token := testJWT("secret", fmt.Sprintf(`{"sub":"operator","aud":["dataverse"],"exp":%d}`, exp))
+	if w := serve("/private", http.Header{"Authorization": {"Bearer " + token}}); w.Code != http.StatusOK || w.Body.String() != "jwt:operator" {
+		t.Fatalf("expected jwt auth, got %d %s", w.Code, w.Body)
+	}
+	for _, invalid := range []string{
+		testJWT("other", fmt.Sprintf(`{"sub":"operator","aud":"dataverse","exp":%d}`, exp)),
+		testJWT("secret", fmt.Sprintf(`{"sub":"operator","aud":"other","exp":%d}`, exp)),
+		testJWT("secret", `{"sub":"operator","aud":"dataverse"}`),
+		testJWT("secret", fmt.Sprintf(`{"sub":"operator","aud":"dataverse","exp":%d}`, time.Now().Unix()-1)),
+	} {
+		if w := serve("/private", http.Header{"Authorization": {"Bearer " + invalid}}); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected invalid jwt to be rejected, got %d", w.Code)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := serve("/public", nil); w.Code != http.StatusOK {
+			t.Fatalf("expected public route to be served, got %d", w.Code)
+		}
+	}
+	if w := serve("/public", nil); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected rate limit, got %d", w.Code)
+	}
+	if w := serve("/unknown", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expected not found, got %d", w.Code)
+	}
+}
+
+func TestAuthFailuresLimited(t *testing.T) {
+	digest := sha256.Sum256([]byte("key"))
+	cfg := DefaultConfig()
+	cfg.APIKeys = []APIKey{{Name: "ci", SHA256: hex.EncodeToString(digest[:])}}
+	cfg.DefaultRoute = RouteConfig{Auth: []string{AuthAPIKey}, RateLimit: RateLimit{PerSecond: 0.001, Burst: 2}}
+	g, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.HandleFunc("/private", func(http.ResponseWriter, *http.Request) {})
+
+	serve := func(remote string, key string) int {
+		r := httptest.NewRequest(http.MethodGet, "/private", nil)
+		r.RemoteAddr = remote
+		r.Header.Set("X-Api-Key", key)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w.Code
+	}
+	for i := 0; i < 2; i++ {
+		if code := serve("192.0.2.1:1234", "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("expected unauthorized, got %d", code)
+		}
+	}
+	// The address is refused even with the right key once it ran out
+	for _, key := range []string{"wrong", "key"} {
+		if code := serve("192.0.2.1:1234", key); code != http.StatusTooManyRequests {
+			t.Fatalf("expected rate limit, got %d", code)
+		}
+	}
+	if code := serve("192.0.2.2:1234", "key"); code != http.StatusOK {
+		t.Fatalf("expected other addresses to be served, got %d", code)
+	}
+}
+
+func TestConfigVerify(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Verify(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected auth without credentials to be rejected, got %v", err)
+	}
+	cfg.DefaultRoute.Auth = []string{AuthNone}
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ListenAddress = "0.0.0.0:8080"
+	if err := cfg.Verify(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected public listener without tls to be rejected, got %v", err)
+	}
+	cfg.Insecure = true
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// maxBuckets bounds how many clients are tracked per route, idle buckets are
+// dropped once it is reached.
+const maxBuckets = 10_000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter is a token bucket per client of a route.
+type limiter struct {
+	rate  RateLimit
+	mu    sync.Mutex
+	count map[string]*bucket
+}
+
+func newLimiter(rate RateLimit) *limiter {
+	return &limiter{rate: rate, count: map[string]*bucket{}}
+}
+
+// allow takes a token of [client] if it has one left at [now].
+func (l *limiter) allow(client string, now time.Time) bool {
+	if l.rate.PerSecond == 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(client, now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// exhausted reports whether [client] has no token left at [now], without
+// taking one.
+func (l *limiter) exhausted(client string, now time.Time) bool {
+	if l.rate.PerSecond == 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.refill(client, now).tokens < 1
+}
+
+// refill returns the bucket of [client] with the tokens it earned by [now].
+func (l *limiter) refill(client string, now time.Time) *bucket {
+	b, ok := l.count[client]
+	if !ok {
+		if len(l.count) >= maxBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: float64(l.rate.Burst), last: now}
+		l.count[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate.PerSecond
+	if b.tokens > float64(l.rate.Burst) {
+		b.tokens = float64(l.rate.Burst)
+	}
+	b.last = now
+	return b
+}
+
+// prune drops the buckets that have refilled, they are the same as new ones.
+func (l *limiter) prune(now time.Time) {
+	for client, b := range l.count {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate.PerSecond >= float64(l.rate.Burst) {
+			delete(l.count, client)
+		}
+	}
+}
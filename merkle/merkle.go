@@ -0,0 +1,138 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package merkle builds the Merkle trees committed by batch notarizations and
+// the proofs that a data CID is included in one.
+//
+// Leaves are sha256(0x00 | multihash) and nodes are sha256(0x01 | left |
+// right), so a leaf can't be passed off as a node. The multihash of a data CID
+// (see content.Multihash) is the same whatever version or base the CID is
+// written in, so a CID is found in a batch in any of its forms. A node without a sibling is carried to
+// the next level unchanged, which means proofs are only valid together with
+// the number of leaves of the tree.
+package merkle
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	leafPrefix = 0x0
+	nodePrefix = 0x1
+
+	// HashLen is the size of roots and proof hashes.
+	HashLen = sha256.Size
+
+	// MaxProofLen is the longest proof of a tree with up to 2^32 leaves.
+	MaxProofLen = 32
+)
+
+var (
+	ErrNoLeaves      = errors.New("tree has no leaves")
+	ErrTooManyLeaves = errors.New("tree has too many leaves")
+	ErrInvalidIndex  = errors.New("leaf index out of range")
+)
+
+// Tree is a Merkle tree over the multihashes of data CIDs.
+type Tree struct {
+	// levels[0] holds the leaves and the last level holds the root.
+	levels [][][]byte
+}
+
+// New builds the tree over [multihashes], in order.
+func New(multihashes [][]byte) (*Tree, error) {
+	if len(multihashes) == 0 {
+		return nil, ErrNoLeaves
+	}
+	if uint64(len(multihashes)) > 1<<MaxProofLen {
+		return nil, ErrTooManyLeaves
+	}
+	level := make([][]byte, len(multihashes))
+	for i, multihash := range multihashes {
+		level[i] = Leaf(multihash)
+	}
+	t := &Tree{levels: [][][]byte{level}}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, node(level[i], level[i+1]))
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Count returns the number of leaves of the tree.
+func (t *Tree) Count() int {
+	return len(t.levels[0])
+}
+
+// Proof returns the sibling hashes needed to recompute the root from the leaf
+// at [index], from the bottom of the tree up.
+func (t *Tree) Proof(index int) ([][]byte, error) {
+	if index < 0 || index >= t.Count() {
+		return nil, ErrInvalidIndex
+	}
+	proof := [][]byte{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Verify reports whether [proof] shows that [multihash] is the leaf at [index]
+// of the tree of [count] leaves with [root].
+func Verify(root []byte, count uint64, index uint64, multihash []byte, proof [][]byte) bool {
+	if count == 0 || index >= count || len(proof) > MaxProofLen {
+		return false
+	}
+	h := Leaf(multihash)
+	width := count
+	for width > 1 {
+		if index^1 < width {
+			if len(proof) == 0 || len(proof[0]) != HashLen {
+				return false
+			}
+			if index%2 == 0 {
+				h = node(h, proof[0])
+			} else {
+				h = node(proof[0], h)
+			}
+			proof = proof[1:]
+		}
+		index /= 2
+		width = (width + 1) / 2
+	}
+	return len(proof) == 0 && string(h) == string(root)
+}
+
+// Leaf returns the leaf hash of [multihash].
+func Leaf(multihash []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(multihash)
+	return h.Sum(nil)
+}
+
+func node(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		cids := make([][]byte, n)
+		for i := range cids {
+			cids[i] = []byte(fmt.Sprintf("cid-%d", i))
+		}
+		tree, err := New(cids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, cid := range cids {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(tree.Root(), uint64(n), uint64(i), cid, proof) {
+				t.Fatalf("proof of leaf %d of %d does not verify", i, n)
+			}
+			if Verify(tree.Root(), uint64(n), uint64(i), []byte("other"), proof) {
+				t.Fatalf("proof of leaf %d of %d verifies another cid", i, n)
+			}
+			if n > 1 && Verify(tree.Root(), uint64(n), uint64((i+1)%n), cid, proof) {
+				t.Fatalf("proof of leaf %d of %d verifies at another index", i, n)
+			}
+		}
+	}
+}
+
+func TestLeafIsNotNode(t *testing.T) {
+	tree, err := New([][]byte{[]byte("a"), []byte("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The concatenation of the leaves must not verify as a single leaf.
+	forged := append([]byte{nodePrefix}, append(Leaf([]byte("a")), Leaf([]byte("b"))...)...)
+	if Verify(tree.Root(), 1, 0, forged[1:], nil) {
+		t.Fatal("node verified as a leaf")
+	}
+	if _, err := New(nil); err != ErrNoLeaves {
+		t.Fatalf("expected %v, got %v", ErrNoLeaves, err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ota
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+
+	"dataverse/auth"
+)
+
+// Headers of a request signed by a device.
+const (
+	AttestationHeader = "X-Machine-Attestation"
+	KeyHeader         = "X-Machine-Key"
+	TimestampHeader   = "X-Machine-Timestamp"
+	SignatureHeader   = "X-Machine-Signature"
+)
+
+// MaxClockSkew bounds how old (or early) a signed request may be.
+const MaxClockSkew = 5 * time.Minute
+
+var (
+	ErrUnsignedRequest   = errors.New("request is not signed by a machine")
+	ErrExpiredRequest    = errors.New("signed request expired")
+	ErrNotMachineKey     = errors.New("request is not signed by the attested machine")
+	ErrMachineNotActive  = errors.New("machine is not active")
+	ErrInvalidRequestSig = errors.New("invalid request signature")
+)
+
+// Machine is an attested machine, as known to the chain.
+type Machine struct {
+	AttestTx ids.ID
+	Address  codec.Address
+	Category string
+	Active   bool
+}
+
+// MachineResolver returns the machine attested by [attestTx].
+type MachineResolver func(ctx context.Context, attestTx ids.ID) (*Machine, error)
+
+// RequestMessage is what a device signs to authenticate a request: the
+// method, the request URI (path and query) and the unix time of the request.
+func RequestMessage(method string, uri string, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("dataverse-ota\n%s\n%s\n%d", method, uri, timestamp))
+}
+
+// SignRequest signs [r] at [now] with the machine key [priv] of type [typ]
+// of the machine attested by [attestTx].
+func SignRequest(r *http.Request, attestTx ids.ID, typ uint8, priv []byte, now time.Time) error {
+	timestamp := now.Unix()
+	key, sig, err := auth.SignMachineKey(typ, priv, RequestMessage(r.Method, r.URL.RequestURI(), timestamp))
+	if err != nil {
+		return err
+	}
+	r.Header.Set(AttestationHeader, attestTx.String())
+	r.Header.Set(KeyHeader, hex.EncodeToString(key))
+	r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(SignatureHeader, hex.EncodeToString(sig))
+	return nil
+}
+
+// VerifyRequest returns the machine that signed [r], if it is active and
+// signed [r] with the key it was attested with less than [MaxClockSkew]
+// from [now]. A request signed within that window can be replayed, so
+// signed routes must be idempotent. The signature is checked before the
+// machine is resolved, so unsigned requests cost no lookup.
+func VerifyRequest(r *http.Request, now time.Time, resolve MachineResolver) (*Machine, error) {
+	attestTx, err := ids.FromString(r.Header.Get(AttestationHeader))
+	if err != nil {
+		return nil, ErrUnsignedRequest
+	}
+	key, err := hex.DecodeString(r.Header.Get(KeyHeader))
+	if err != nil || len(key) == 0 {
+		return nil, ErrUnsignedRequest
+	}
+	sig, err := hex.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil || len(sig) == 0 {
+		return nil, ErrUnsignedRequest
+	}
+	timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return nil, ErrUnsignedRequest
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return nil, ErrExpiredRequest
+	}
+
+	typ, err := auth.MachineKeyType(key)
+	if err != nil {
+		return nil, ErrInvalidRequestSig
+	}
+	signer, err := auth.VerifyMachineKey(typ, key, RequestMessage(r.Method, r.URL.RequestURI(), timestamp), sig)
+	if err != nil {
+		return nil, ErrInvalidRequestSig
+	}
+
+	machine, err := resolve(r.Context(), attestTx)
+	if err != nil {
+		return nil, err
+	}
+	// Machine addresses are derived from their key
+	if signer != machine.Address {
+		return nil, ErrNotMachineKey
+	}
+	if !machine.Active {
+		return nil, ErrMachineNotActive
+	}
+	return machine, nil
+}
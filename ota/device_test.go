@@ -0,0 +1,90 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ota
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	"dataverse/auth"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	priv, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestTx := ids.GenerateTestID()
+	machine := &Machine{AttestTx: attestTx, Address: auth.NewED25519Address(priv.PublicKey()), Category: "sensor", Active: true}
+	resolve := func(_ context.Context, id ids.ID) (*Machine, error) {
+		if id != attestTx {
+			return nil, errors.New("unknown attestation")
+		}
+		return machine, nil
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		key    ed25519.PrivateKey
+		signed time.Time
+		tamper bool
+		active bool
+		err    error
+	}{
+		{name: "valid", key: priv, signed: now, active: true},
+		{name: "tampered", key: priv, signed: now, active: true, tamper: true, err: ErrInvalidRequestSig},
+		{name: "stale", key: priv, signed: now.Add(-2 * MaxClockSkew), active: true, err: ErrExpiredRequest},
+		{name: "other machine", key: other, signed: now, active: true, err: ErrNotMachineKey},
+		{name: "inactive", key: priv, signed: now, active: false, err: ErrMachineNotActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine.Active = tt.active
+			r := httptest.NewRequest("GET", "/ota/manifest?project_id=p&current_version=1.0.0", nil)
+			if err := SignRequest(r, attestTx, auth.ED25519Key, tt.key[:], tt.signed); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper {
+				r.URL.RawQuery += "&device_name=camera"
+			}
+			m, err := VerifyRequest(r, now, resolve)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err == nil && m.AttestTx != attestTx {
+				t.Fatal("unexpected machine")
+			}
+		})
+	}
+
+	r := httptest.NewRequest("GET", "/ota/manifest", nil)
+	if _, err := VerifyRequest(r, now, resolve); !errors.Is(err, ErrUnsignedRequest) {
+		t.Fatalf("expected unsigned request, got %v", err)
+	}
+
+	// Requests with an invalid signature are refused before any lookup
+	r = httptest.NewRequest("GET", "/ota/manifest", nil)
+	if err := SignRequest(r, attestTx, auth.ED25519Key, priv[:], now); err != nil {
+		t.Fatal(err)
+	}
+	r.URL.RawQuery = "device_name=camera"
+	_, err = VerifyRequest(r, now, func(context.Context, ids.ID) (*Machine, error) {
+		t.Fatal("resolved the machine of an invalid request")
+		return nil, nil
+	})
+	if !errors.Is(err, ErrInvalidRequestSig) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
@@ -0,0 +1,709 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gorm.io/gorm"
+)
+
+// createBatchSize bounds the devices inserted per statement.
+const createBatchSize = 500
+
+// settleInterval is how often the devices of a wave are checked for the
+// reports of their install, while the rollout waits for them.
+var settleInterval = 30 * time.Second
+
+// deviceTransition names the statuses a device goes through when an update
+// is pushed to it.
+type deviceTransition struct {
+	from, during, done, failed string
+}
+
+var (
+	deployTransition   = deviceTransition{DevicePending, DevicePushing, DeviceSucceeded, DeviceFailed}
+	rollbackTransition = deviceTransition{DeviceSucceeded, DeviceRollingBack, DeviceRolledBack, DeviceRollbackFailed}
+)
+
+type run struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Orchestrator runs rollouts in the background and persists their state in
+// a database, so they resume where they stopped after a restart.
+type Orchestrator struct {
+	db        *gorm.DB
+	inventory Inventory
+	pusher    Pusher
+	updates   Updates
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[uint]*run
+}
+
+// New returns an orchestrator storing rollouts in [db]. Devices are updated
+// concurrently, so a sqlite [db] should be limited to a single connection.
+func New(db *gorm.DB, inventory Inventory, pusher Pusher, updates Updates) (*Orchestrator, error) {
+	if err := db.AutoMigrate(&Rollout{}, &RolloutDevice{}); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Orchestrator{
+		db:        db,
+		inventory: inventory,
+		pusher:    pusher,
+		updates:   updates,
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   map[uint]*run{},
+	}, nil
+}
+
+// Resume restarts the rollouts that were running when the orchestrator was
+// last stopped.
+func (o *Orchestrator) Resume() error {
+	var rollouts []*Rollout
+	if err := o.db.Where("status IN ?", []string{RolloutRunning, RolloutRollingBack}).Find(&rollouts).Error; err != nil {
+		return err
+	}
+	for _, r := range rollouts {
+		o.launch(r)
+	}
+	return nil
+}
+
+// Close stops the rollouts, devices being updated are updated again when
+// the rollout resumes.
+func (o *Orchestrator) Close() {
+	o.cancel()
+	o.wg.Wait()
+}
+
+// Create resolves the devices of [spec] and records a pending rollout of
+// them, see [Orchestrator.Start]. The updates of [spec] must be for the
+// category of its group.
+func (o *Orchestrator) Create(ctx context.Context, spec Spec) (*Rollout, error) {
+	if err := spec.Verify(); err != nil {
+		return nil, err
+	}
+	for _, update := range []ids.ID{spec.UpdateTx, spec.PreviousUpdateTx} {
+		if update == ids.Empty {
+			continue
+		}
+		name, err := o.updates.DeviceName(ctx, update)
+		if err != nil {
+			return nil, err
+		}
+		if name != spec.Group.Category {
+			return nil, fmt.Errorf("%w: %s is for %q", ErrDeviceMismatch, update, name)
+		}
+	}
+	devices, err := o.inventory.Devices(ctx, spec.Group)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, ErrNoDevices
+	}
+	// The same group always gets the same canaries
+	sort.Slice(devices, func(i, j int) bool {
+		return bytes.Compare(devices[i].AttestTx[:], devices[j].AttestTx[:]) < 0
+	})
+	r := &Rollout{
+		UpdateTx:       spec.UpdateTx.String(),
+		Category:       spec.Group.Category,
+		Manufacturer:   spec.Group.Manufacturer,
+		Waves:          len(spec.Waves),
+		Concurrency:    spec.Concurrency,
+		MaxFailureRate: spec.MaxFailureRate,
+		WaveDelay:      spec.WaveDelay,
+		OfferTimeout:   spec.OfferTimeout,
+		Status:         RolloutPending,
+	}
+	if spec.PreviousUpdateTx != ids.Empty {
+		r.PreviousUpdateTx = spec.PreviousUpdateTx.String()
+	}
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(r).Error; err != nil {
+			return err
+		}
+		rows := make([]*RolloutDevice, len(devices))
+		for i, d := range devices {
+			previous, err := installedUpdate(tx, d.Address)
+			if err != nil {
+				return err
+			}
+			if len(previous) == 0 {
+				previous = r.PreviousUpdateTx
+			}
+			rows[i] = &RolloutDevice{
+				RolloutID:        r.ID,
+				AttestTx:         d.AttestTx.String(),
+				Address:          d.Address,
+				Endpoint:         d.Endpoint,
+				Wave:             spec.waveOf(i, len(devices)),
+				PreviousUpdateTx: previous,
+				Status:           DevicePending,
+			}
+		}
+		return tx.CreateInBatches(rows, createBatchSize).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// installedUpdate returns the update last installed on [address] by a
+// rollout, if any.
+func installedUpdate(tx *gorm.DB, address string) (string, error) {
+	var d RolloutDevice
+	err := tx.Where("address = ? AND installed_tx <> ''", address).Order("updated_at DESC").Limit(1).Find(&d).Error
+	return d.InstalledTx, err
+}
+
+// Start runs a pending rollout, or resumes a halted one. The devices that
+// failed in the wave that halted it are updated again.
+func (o *Orchestrator) Start(id uint) error {
+	r, err := o.transition(id, []string{RolloutPending, RolloutHalted}, RolloutRunning, "")
+	if err != nil {
+		return err
+	}
+	err = o.db.Model(&RolloutDevice{}).
+		Where("rollout_id = ? AND wave = ? AND status = ?", id, r.Wave, DeviceFailed).
+		Update("status", DevicePending).Error
+	if err != nil {
+		return err
+	}
+	o.launch(r)
+	return nil
+}
+
+// Halt stops a running rollout, the devices being updated are updated again
+// if the rollout is resumed.
+func (o *Orchestrator) Halt(id uint) error {
+	if _, err := o.transition(id, []string{RolloutRunning}, RolloutHalted, "halted by operator"); err != nil {
+		return err
+	}
+	o.stop(id)
+	return nil
+}
+
+// Rollback stops the rollout and installs their previous update on the
+// devices it updated. A rollback that failed on some devices can be retried.
+func (o *Orchestrator) Rollback(id uint) error {
+	r, err := o.transition(
+		id,
+		[]string{RolloutRunning, RolloutHalted, RolloutCompleted, RolloutRolledBack},
+		RolloutRollingBack,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	o.stop(id)
+	o.launch(r)
+	return nil
+}
+
+// Status returns the rollout [id] with the number of devices in each status.
+func (o *Orchestrator) Status(ctx context.Context, id uint) (*Status, error) {
+	r, err := o.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		Status string
+		Count  int
+	}
+	err = o.db.WithContext(ctx).Model(&RolloutDevice{}).
+		Select("status, count(*) AS count").
+		Where("rollout_id = ?", id).
+		Group("status").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{Rollout: r, Devices: map[string]int{}}
+	for _, c := range counts {
+		status.Devices[c.Status] = c.Count
+	}
+	return status, nil
+}
+
+// Devices returns the devices of the rollout [id], only those in [status]
+// if it is not empty.
+func (o *Orchestrator) Devices(ctx context.Context, id uint, status string) ([]*RolloutDevice, error) {
+	if _, err := o.get(ctx, id); err != nil {
+		return nil, err
+	}
+	q := o.db.WithContext(ctx).Where("rollout_id = ?", id)
+	if len(status) > 0 {
+		q = q.Where("status = ?", status)
+	}
+	var devices []*RolloutDevice
+	return devices, q.Order("wave, id").Find(&devices).Error
+}
+
+// Released reports whether [update] may be pulled by the device
+// [attestTx]. Updates rolled out by the orchestrator are only released to
+// the devices they were offered to, other updates are released to all.
+func (o *Orchestrator) Released(ctx context.Context, update ids.ID, attestTx ids.ID) (bool, error) {
+	var rollouts []uint
+	err := o.db.WithContext(ctx).Model(&Rollout{}).Where("update_tx = ?", update.String()).Pluck("id", &rollouts).Error
+	if err != nil || len(rollouts) == 0 {
+		return err == nil, err
+	}
+	var offered int64
+	err = o.db.WithContext(ctx).Model(&RolloutDevice{}).
+		Where("rollout_id IN ? AND attest_tx = ? AND status IN ?", rollouts, attestTx.String(), []string{DeviceOffered, DeviceSucceeded}).
+		Count(&offered).Error
+	return offered > 0, err
+}
+
+// Offered returns the updates rolled out to the device [attestTx], which are
+// released to it.
+func (o *Orchestrator) Offered(ctx context.Context, attestTx ids.ID) ([]ids.ID, error) {
+	rollouts := o.db.Model(&RolloutDevice{}).
+		Select("rollout_id").
+		Where("attest_tx = ? AND status IN ?", attestTx.String(), []string{DeviceOffered, DeviceSucceeded})
+	var txs []string
+	err := o.db.WithContext(ctx).Model(&Rollout{}).
+		Where("id IN (?)", rollouts).
+		Distinct("update_tx").
+		Pluck("update_tx", &txs).Error
+	if err != nil {
+		return nil, err
+	}
+	updates := make([]ids.ID, len(txs))
+	for i, tx := range txs {
+		if updates[i], err = ids.FromString(tx); err != nil {
+			return nil, err
+		}
+	}
+	return updates, nil
+}
+
+// Installed records that the device [attestTx] runs [update], which it was
+// offered by a rollout.
+func (o *Orchestrator) Installed(ctx context.Context, update ids.ID, attestTx ids.ID) error {
+	rollouts := o.db.Model(&Rollout{}).Select("id").Where("update_tx = ?", update.String())
+	return o.db.WithContext(ctx).Model(&RolloutDevice{}).
+		Where("rollout_id IN (?) AND attest_tx = ? AND status = ?", rollouts, attestTx.String(), DeviceOffered).
+		Updates(map[string]any{"status": DeviceSucceeded, "installed_tx": update.String()}).Error
+}
+
+func (o *Orchestrator) get(ctx context.Context, id uint) (*Rollout, error) {
+	var rollouts []*Rollout
+	if err := o.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&rollouts).Error; err != nil {
+		return nil, err
+	}
+	if len(rollouts) == 0 {
+		return nil, ErrRolloutNotFound
+	}
+	return rollouts[0], nil
+}
+
+// transition moves the rollout [id] from one of the statuses [from] to [to].
+func (o *Orchestrator) transition(id uint, from []string, to string, msg string) (*Rollout, error) {
+	res := o.db.Model(&Rollout{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]any{"status": to, "error": msg})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	r, err := o.get(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTransition, r.Status)
+	}
+	return r, nil
+}
+
+// finish moves the rollout [id] from [from] to [to], unless an operator
+// already moved it.
+func (o *Orchestrator) finish(id uint, from string, to string, msg string) error {
+	return o.db.Model(&Rollout{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{"status": to, "error": msg}).Error
+}
+
+// launch runs [r] in the background, deploying or rolling back depending on
+// its status.
+func (o *Orchestrator) launch(r *Rollout) {
+	ctx, cancel := context.WithCancel(o.ctx)
+	current := &run{cancel: cancel, done: make(chan struct{})}
+	o.mu.Lock()
+	o.running[r.ID] = current
+	o.mu.Unlock()
+
+	o.wg.Add(1)
+	go func() {
+		defer func() {
+			cancel()
+			o.mu.Lock()
+			if o.running[r.ID] == current {
+				delete(o.running, r.ID)
+			}
+			o.mu.Unlock()
+			close(current.done)
+			o.wg.Done()
+		}()
+		var err error
+		if r.Status == RolloutRollingBack {
+			err = o.rollback(ctx, r)
+		} else {
+			err = o.deploy(ctx, r)
+		}
+		if err != nil && ctx.Err() == nil {
+			_ = o.finish(r.ID, r.Status, RolloutHalted, err.Error())
+		}
+	}()
+}
+
+// stop cancels the background run of the rollout [id] and waits for it.
+func (o *Orchestrator) stop(id uint) {
+	o.mu.Lock()
+	current, ok := o.running[id]
+	o.mu.Unlock()
+	if !ok {
+		return
+	}
+	current.cancel()
+	<-current.done
+}
+
+// deploy updates the devices of [r] wave by wave, from the wave it reached.
+func (o *Orchestrator) deploy(ctx context.Context, r *Rollout) error {
+	update, err := ids.FromString(r.UpdateTx)
+	if err != nil {
+		return err
+	}
+	for wave := r.Wave; wave < r.Waves; wave++ {
+		err := o.db.Model(&Rollout{}).
+			Where("id = ? AND status = ?", r.ID, RolloutRunning).
+			Update("wave", wave).Error
+		if err != nil {
+			return err
+		}
+		failed, size, err := o.deployWave(ctx, r, wave, update)
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+		if failed <= budget(r, size) {
+			var delay time.Duration
+			if wave < r.Waves-1 {
+				delay = r.WaveDelay
+			}
+			failed, size, err = o.settleWave(ctx, r, wave, update, delay)
+			if err != nil || ctx.Err() != nil {
+				return err
+			}
+		}
+		if failed > budget(r, size) {
+			msg := fmt.Sprintf("wave %d: %d of %d devices failed", wave, failed, size)
+			return o.finish(r.ID, RolloutRunning, RolloutHalted, msg)
+		}
+	}
+	return o.finish(r.ID, RolloutRunning, RolloutCompleted, "")
+}
+
+// budget is how many of [size] devices of a wave of [r] may fail.
+func budget(r *Rollout, size int) int {
+	return int(r.MaxFailureRate * float64(size))
+}
+
+// deployWave updates the devices of [wave] that are not updated yet, and
+// stops as soon as more of them failed than the rollout allows. It returns
+// how many devices of the wave failed and its size.
+func (o *Orchestrator) deployWave(ctx context.Context, r *Rollout, wave int, update ids.ID) (int, int, error) {
+	var devices []*RolloutDevice
+	if err := o.db.Where("rollout_id = ? AND wave = ?", r.ID, wave).Order("id").Find(&devices).Error; err != nil {
+		return 0, 0, err
+	}
+	var (
+		failed  atomic.Int64
+		pending []*RolloutDevice
+	)
+	for _, d := range devices {
+		switch d.Status {
+		case DeviceFailed:
+			failed.Add(1)
+		case DevicePending, DevicePushing:
+			// [DevicePushing] was interrupted by a restart, its outcome is
+			// unknown
+			pending = append(pending, d)
+		}
+	}
+	allowed := int64(budget(r, len(devices)))
+	err := forEach(ctx, r.Concurrency, pending, func() bool {
+		return failed.Load() > allowed
+	}, func(d *RolloutDevice) error {
+		if len(d.Endpoint) == 0 {
+			// The device pulls the update once it is offered
+			d.Status, d.Error, d.OfferedAt = DeviceOffered, "", time.Now()
+			return o.db.Save(d).Error
+		}
+		if err := o.push(ctx, d, update, r.UpdateTx, deployTransition); err != nil {
+			return err
+		}
+		if d.Status == DeviceFailed {
+			failed.Add(1)
+		}
+		return nil
+	})
+	return int(failed.Load()), len(devices), err
+}
+
+// settleWave waits at least [delay], and until the devices of [wave] that
+// were offered [update] confirmed it or ran out of time, so failures that
+// show after the install are reported. It returns early once more devices
+// of the wave failed than the rollout allows, with how many failed and the
+// size of the wave.
+func (o *Orchestrator) settleWave(
+	ctx context.Context,
+	r *Rollout,
+	wave int,
+	update ids.ID,
+	delay time.Duration,
+) (int, int, error) {
+	deadline := time.Now().Add(delay)
+	for {
+		failed, size, offered, err := o.checkWave(ctx, r, wave, update)
+		if err != nil || ctx.Err() != nil {
+			return 0, 0, err
+		}
+		left := time.Until(deadline)
+		if failed > budget(r, size) || (offered == 0 && left <= 0) {
+			return failed, size, nil
+		}
+		wait := settleInterval
+		if offered == 0 && left < wait {
+			wait = left
+		}
+		select {
+		case <-ctx.Done():
+			return 0, 0, nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// checkWave records the reports of the devices of [wave] that installed
+// [update], and fails the offered devices that did not confirm it in time.
+// It returns how many devices of the wave failed, its size and how many are
+// still offered the update.
+func (o *Orchestrator) checkWave(ctx context.Context, r *Rollout, wave int, update ids.ID) (int, int, int, error) {
+	var devices []*RolloutDevice
+	if err := o.db.Where("rollout_id = ? AND wave = ?", r.ID, wave).Find(&devices).Error; err != nil {
+		return 0, 0, 0, err
+	}
+	now := time.Now()
+	var failed, offered int
+	for _, d := range devices {
+		if d.Status == DeviceOffered || d.Status == DeviceSucceeded {
+			if err := o.checkDevice(ctx, r, d, update, now); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+		switch d.Status {
+		case DeviceFailed:
+			failed++
+		case DeviceOffered:
+			offered++
+		}
+	}
+	return failed, len(devices), offered, nil
+}
+
+// checkDevice moves [d] to the status matching what it reported on chain
+// about installing [update], failing it if it was offered [update] more than
+// the offer timeout of [r] before [now] and did not report.
+func (o *Orchestrator) checkDevice(ctx context.Context, r *Rollout, d *RolloutDevice, update ids.ID, now time.Time) error {
+	device, err := d.device()
+	if err != nil {
+		return err
+	}
+	reported, success, err := o.updates.Report(ctx, device, update)
+	if err != nil {
+		return err
+	}
+	from := d.Status
+	switch {
+	case reported && !success:
+		d.Status, d.Error = DeviceFailed, ErrReportedFailure.Error()
+	case reported && from == DeviceOffered:
+		d.Status, d.InstalledTx = DeviceSucceeded, r.UpdateTx
+	case !reported && from == DeviceOffered && now.Sub(d.OfferedAt) > r.OfferTimeout:
+		d.Status, d.Error = DeviceFailed, ErrNotConfirmed.Error()
+	default:
+		return nil
+	}
+	// The device may confirm the update through [Orchestrator.Installed]
+	// meanwhile
+	res := o.db.Model(&RolloutDevice{}).
+		Where("id = ? AND status = ?", d.ID, from).
+		Updates(map[string]any{"status": d.Status, "error": d.Error, "installed_tx": d.InstalledTx})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return o.db.Where("id = ?", d.ID).First(d).Error
+	}
+	return nil
+}
+
+// rollback installs their previous update on the devices [r] updated.
+func (o *Orchestrator) rollback(ctx context.Context, r *Rollout) error {
+	var devices []*RolloutDevice
+	err := o.db.Where(
+		"rollout_id = ? AND status IN ?",
+		r.ID,
+		[]string{DeviceOffered, DeviceSucceeded, DeviceRollingBack, DeviceRollbackFailed},
+	).Find(&devices).Error
+	if err != nil {
+		return err
+	}
+	var failed, unsupported atomic.Int64
+	err = forEach(ctx, r.Concurrency, devices, func() bool { return false }, func(d *RolloutDevice) error {
+		if len(d.Endpoint) == 0 {
+			// The update is no longer offered, but devices that pulled it
+			// can't be made to pull the previous one
+			if d.Status == DeviceOffered {
+				d.Status = DeviceRolledBack
+			} else {
+				unsupported.Add(1)
+				d.Status, d.Error = DeviceRollbackUnsupported, ErrPullRollback.Error()
+			}
+			return o.db.Save(d).Error
+		}
+		if len(d.PreviousUpdateTx) == 0 {
+			failed.Add(1)
+			d.Status, d.Error = DeviceRollbackFailed, ErrNoPreviousUpdate.Error()
+			return o.db.Save(d).Error
+		}
+		previous, err := ids.FromString(d.PreviousUpdateTx)
+		if err != nil {
+			return err
+		}
+		if err := o.push(ctx, d, previous, d.PreviousUpdateTx, rollbackTransition); err != nil {
+			return err
+		}
+		if d.Status == DeviceRollbackFailed {
+			failed.Add(1)
+		}
+		return nil
+	})
+	if err != nil || ctx.Err() != nil {
+		return err
+	}
+	var msgs []string
+	if n := failed.Load(); n > 0 {
+		msgs = append(msgs, fmt.Sprintf("%d devices failed to roll back", n))
+	}
+	if n := unsupported.Load(); n > 0 {
+		msgs = append(msgs, fmt.Sprintf("%d devices pull their updates and can't be rolled back", n))
+	}
+	msg := strings.Join(msgs, ", ")
+	return o.finish(r.ID, RolloutRollingBack, RolloutRolledBack, msg)
+}
+
+// push installs [update] on [d], recording its status as it goes through
+// [t]. A push interrupted by [ctx] puts the device back where it was.
+func (o *Orchestrator) push(
+	ctx context.Context,
+	d *RolloutDevice,
+	update ids.ID,
+	installed string,
+	t deviceTransition,
+) error {
+	d.Status, d.Error = t.during, ""
+	d.Attempts++
+	if err := o.db.Save(d).Error; err != nil {
+		return err
+	}
+	device, err := d.device()
+	if err == nil {
+		err = o.pusher.Push(ctx, device, update)
+	}
+	switch {
+	case ctx.Err() != nil:
+		d.Status = t.from
+	case err != nil:
+		d.Status, d.Error = t.failed, err.Error()
+	default:
+		d.Status, d.InstalledTx = t.done, installed
+	}
+	return o.db.Save(d).Error
+}
+
+// forEach calls [f] on [devices], [concurrency] at a time, until [stop]
+// returns true, [ctx] is done or [f] fails. It returns the first error of
+// [f].
+func forEach(
+	ctx context.Context,
+	concurrency int,
+	devices []*RolloutDevice,
+	stop func() bool,
+	f func(*RolloutDevice) error,
+) error {
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, concurrency)
+		once sync.Once
+		ferr error
+	)
+	failed := make(chan struct{})
+	done := func() bool {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-failed:
+			return true
+		default:
+			return stop()
+		}
+	}
+	for _, d := range devices {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		case <-failed:
+		}
+		if done() {
+			break
+		}
+		wg.Add(1)
+		go func(d *RolloutDevice) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := f(d); err != nil {
+				once.Do(func() {
+					ferr = err
+					close(failed)
+				})
+			}
+		}(d)
+	}
+	wg.Wait()
+	return ferr
+}
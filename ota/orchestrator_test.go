@@ -0,0 +1,391 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var errPushFailed = errors.New("push failed")
+
+type mockInventory []Device
+
+func (m mockInventory) Devices(_ context.Context, group Group) ([]Device, error) {
+	if group.Category != "sensor" {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// mockPusher records what each device has installed, and fails pushes to
+// the devices in [fail].
+type mockPusher struct {
+	mu        sync.Mutex
+	installed map[string]ids.ID
+	fail      map[string]bool
+	active    int
+	maxActive int
+}
+
+func (m *mockPusher) Push(_ context.Context, device Device, update ids.ID) error {
+	m.mu.Lock()
+	m.active++
+	if m.active > m.maxActive {
+		m.maxActive = m.active
+	}
+	m.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active--
+	if m.fail[device.Address] {
+		return errPushFailed
+	}
+	m.installed[device.Address] = update
+	return nil
+}
+
+func (m *mockPusher) get(address string) ids.ID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.installed[address]
+}
+
+// mockUpdates are updates for sensors unless [names] says otherwise, the
+// devices in [reports] reported whether they installed them.
+type mockUpdates struct {
+	mu      sync.Mutex
+	names   map[ids.ID]string
+	reports map[string]bool
+}
+
+func (m *mockUpdates) DeviceName(_ context.Context, update ids.ID) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if name, ok := m.names[update]; ok {
+		return name, nil
+	}
+	return "sensor", nil
+}
+
+func (m *mockUpdates) Report(_ context.Context, device Device, _ ids.ID) (bool, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	success, ok := m.reports[device.Address]
+	return ok, success, nil
+}
+
+func (m *mockUpdates) report(address string, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reports[address] = success
+}
+
+// newTestOrchestrator returns an orchestrator of [devices] devices, the last
+// [pull] of which have no endpoint.
+func newTestOrchestrator(t *testing.T, devices int, pull int) (*Orchestrator, *mockPusher, *mockUpdates) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	inventory := make(mockInventory, devices)
+	for i := range inventory {
+		inventory[i] = Device{AttestTx: ids.GenerateTestID(), Address: fmt.Sprintf("device-%d", i)}
+		if i < devices-pull {
+			inventory[i].Endpoint = fmt.Sprintf("10.0.0.%d:8080", i)
+		}
+	}
+	pusher := &mockPusher{installed: map[string]ids.ID{}, fail: map[string]bool{}}
+	updates := &mockUpdates{names: map[ids.ID]string{}, reports: map[string]bool{}}
+	o, err := New(db, inventory, pusher, updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(o.Close)
+	settleInterval = 5 * time.Millisecond
+	return o, pusher, updates
+}
+
+// waitFor waits for the rollout [id] to reach [status].
+func waitFor(t *testing.T, o *Orchestrator, id uint, status string) *Status {
+	for i := 0; i < 500; i++ {
+		s, err := o.Status(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Rollout.Status == status {
+			return s
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("rollout %d did not reach %s", id, status)
+	return nil
+}
+
+// waitForDevice waits for the device [address] of the rollout [id] to reach
+// [status].
+func waitForDevice(t *testing.T, o *Orchestrator, id uint, address string, status string) *RolloutDevice {
+	for i := 0; i < 500; i++ {
+		devices, err := o.Devices(context.Background(), id, status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range devices {
+			if d.Address == address {
+				return d
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("device %s of rollout %d did not reach %s", address, id, status)
+	return nil
+}
+
+func testSpec(update ids.ID) Spec {
+	spec := DefaultSpec(update, Group{Category: "sensor"})
+	spec.Waves = []float64{0.1, 0.5, 1}
+	spec.Concurrency = 3
+	spec.MaxFailureRate = 0.2
+	spec.WaveDelay = 0
+	return spec
+}
+
+func TestRollout(t *testing.T) {
+	ctx := context.Background()
+	o, pusher, updates := newTestOrchestrator(t, 20, 0)
+
+	camera := ids.GenerateTestID()
+	updates.names[camera] = "camera"
+	if _, err := o.Create(ctx, DefaultSpec(camera, Group{Category: "camera"})); !errors.Is(err, ErrNoDevices) {
+		t.Fatalf("expected no devices, got %v", err)
+	}
+	if _, err := o.Create(ctx, testSpec(camera)); !errors.Is(err, ErrDeviceMismatch) {
+		t.Fatalf("expected the update not to be for the group, got %v", err)
+	}
+	spec := testSpec(ids.GenerateTestID())
+	spec.Waves = []float64{0.5, 0.1, 1}
+	if _, err := o.Create(ctx, spec); !errors.Is(err, ErrInvalidSpec) {
+		t.Fatalf("expected invalid spec, got %v", err)
+	}
+
+	first := ids.GenerateTestID()
+	r, err := o.Create(ctx, testSpec(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err := o.Devices(ctx, r.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waves := map[int]int{}
+	for _, d := range devices {
+		waves[d.Wave]++
+	}
+	if waves[0] != 2 || waves[1] != 8 || waves[2] != 10 {
+		t.Fatalf("unexpected waves %v", waves)
+	}
+	if err := o.Start(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	s := waitFor(t, o, r.ID, RolloutCompleted)
+	if s.Devices[DeviceSucceeded] != 20 {
+		t.Fatalf("expected all devices updated, got %v", s.Devices)
+	}
+	if pusher.maxActive > 3 {
+		t.Fatalf("expected at most 3 pushes at once, got %d", pusher.maxActive)
+	}
+	if err := o.Start(r.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected completed rollout not to start, got %v", err)
+	}
+
+	// The second update fails on the last device of the second wave, which
+	// is more than it allows
+	spec = testSpec(ids.GenerateTestID())
+	spec.MaxFailureRate = 0.1
+	r, err = o.Create(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err = o.Devices(ctx, r.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range devices {
+		if d.PreviousUpdateTx != first.String() {
+			t.Fatal("expected the first update to be rolled back to")
+		}
+	}
+	pusher.fail[devices[9].Address] = true
+	if err := o.Start(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	s = waitFor(t, o, r.ID, RolloutHalted)
+	if s.Rollout.Wave != 1 || s.Devices[DeviceFailed] != 1 || s.Devices[DevicePending] != 10 {
+		t.Fatalf("expected the rollout to halt after the second wave, got %+v %v", s.Rollout, s.Devices)
+	}
+
+	if err := o.Rollback(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	s = waitFor(t, o, r.ID, RolloutRolledBack)
+	if s.Devices[DeviceRolledBack] != 9 || len(s.Rollout.Error) > 0 {
+		t.Fatalf("expected updated devices to be rolled back, got %+v %v", s.Rollout, s.Devices)
+	}
+	for _, d := range devices {
+		if installed := pusher.get(d.Address); installed != first {
+			t.Fatalf("expected %s to run the first update, got %s", d.Address, installed)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	o, pusher, _ := newTestOrchestrator(t, 10, 0)
+	update := ids.GenerateTestID()
+	spec := testSpec(update)
+	spec.Waves = []float64{1}
+	spec.MaxFailureRate = 0
+	r, err := o.Create(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pusher.fail["device-3"] = true
+	if err := o.Start(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, o, r.ID, RolloutHalted)
+	if err := o.Halt(r.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected halted rollout not to halt, got %v", err)
+	}
+
+	// Once fixed, resuming updates the device that failed
+	pusher.mu.Lock()
+	pusher.fail["device-3"] = false
+	pusher.mu.Unlock()
+	if err := o.Start(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	s := waitFor(t, o, r.ID, RolloutCompleted)
+	if s.Devices[DeviceSucceeded] != 10 || pusher.get("device-3") != update {
+		t.Fatalf("expected all devices updated, got %v", s.Devices)
+	}
+}
+
+func TestPull(t *testing.T) {
+	ctx := context.Background()
+	o, pusher, updates := newTestOrchestrator(t, 4, 2)
+	update := ids.GenerateTestID()
+	previous := ids.GenerateTestID()
+	spec := testSpec(update)
+	spec.Waves = []float64{0.5, 1}
+	spec.MaxFailureRate = 0.5
+	spec.PreviousUpdateTx = previous
+	r, err := o.Create(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err := o.Devices(ctx, r.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pulling []Device
+	for _, d := range devices {
+		if len(d.Endpoint) == 0 {
+			device, err := d.device()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pulling = append(pulling, device)
+		}
+	}
+
+	// Updates are only released to the devices of their rollout once offered
+	if released, err := o.Released(ctx, update, pulling[0].AttestTx); err != nil || released {
+		t.Fatalf("expected the update not to be released yet, got %t %v", released, err)
+	}
+	if released, err := o.Released(ctx, ids.GenerateTestID(), pulling[0].AttestTx); err != nil || !released {
+		t.Fatalf("expected updates without rollouts to be released, got %t %v", released, err)
+	}
+
+	// The first device confirms the update, the second reports it failed on
+	// chain. The rollout waits for both.
+	updates.report(pulling[1].Address, false)
+	if err := o.Start(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitForDevice(t, o, r.ID, pulling[0].Address, DeviceOffered)
+	if released, err := o.Released(ctx, update, pulling[0].AttestTx); err != nil || !released {
+		t.Fatalf("expected the update to be released, got %t %v", released, err)
+	}
+	if offered, err := o.Offered(ctx, pulling[0].AttestTx); err != nil || len(offered) != 1 || offered[0] != update {
+		t.Fatalf("expected the update to be offered, got %v %v", offered, err)
+	}
+	if s, err := o.Status(ctx, r.ID); err != nil || s.Rollout.Status != RolloutRunning {
+		t.Fatalf("expected the rollout to wait for the offered device, got %+v %v", s, err)
+	}
+	if err := o.Installed(ctx, update, pulling[0].AttestTx); err != nil {
+		t.Fatal(err)
+	}
+	s := waitFor(t, o, r.ID, RolloutCompleted)
+	if s.Devices[DeviceSucceeded] != 3 || s.Devices[DeviceFailed] != 1 || pusher.get(pulling[0].Address) != ids.Empty {
+		t.Fatalf("expected the installed update to be recorded, got %v", s.Devices)
+	}
+	if d := waitForDevice(t, o, r.ID, pulling[1].Address, DeviceFailed); d.Error != ErrReportedFailure.Error() {
+		t.Fatalf("expected the reported failure, got %q", d.Error)
+	}
+
+	// Devices that don't confirm the update in time fail
+	updates.mu.Lock()
+	delete(updates.reports, pulling[1].Address)
+	updates.mu.Unlock()
+	spec = testSpec(ids.GenerateTestID())
+	spec.Waves = []float64{1}
+	spec.MaxFailureRate = 0
+	spec.OfferTimeout = 20 * time.Millisecond
+	timeout, err := o.Create(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Start(timeout.ID); err != nil {
+		t.Fatal(err)
+	}
+	s = waitFor(t, o, timeout.ID, RolloutHalted)
+	if s.Devices[DeviceSucceeded] != 2 || s.Devices[DeviceFailed] == 0 {
+		t.Fatalf("expected the offered devices to fail, got %v", s.Devices)
+	}
+	failed, err := o.Devices(ctx, timeout.ID, DeviceFailed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range failed {
+		if d.Error != ErrNotConfirmed.Error() {
+			t.Fatalf("expected %s not to confirm, got %q", d.Address, d.Error)
+		}
+	}
+
+	// Devices that pulled the update can't be rolled back
+	if err := o.Rollback(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	s = waitFor(t, o, r.ID, RolloutRolledBack)
+	if s.Devices[DeviceRolledBack] != 2 || s.Devices[DeviceRollbackUnsupported] != 1 || len(s.Rollout.Error) == 0 {
+		t.Fatalf("expected the pulling device not to be rolled back, got %+v %v", s.Rollout, s.Devices)
+	}
+}
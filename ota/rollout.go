@@ -0,0 +1,234 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package ota rolls updates out to groups of attested devices in waves,
+// tracking the state of every device so a rollout survives restarts, halts
+// when too many devices fail and can be rolled back. Devices the gateway
+// can reach are pushed to, the others pull their updates with requests
+// signed by their machine key.
+package ota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Rollout statuses.
+const (
+	RolloutPending     = "pending"
+	RolloutRunning     = "running"
+	RolloutHalted      = "halted"
+	RolloutCompleted   = "completed"
+	RolloutRollingBack = "rolling_back"
+	RolloutRolledBack  = "rolled_back"
+)
+
+// Device statuses.
+const (
+	DevicePending        = "pending"
+	DevicePushing        = "pushing"
+	DeviceOffered        = "offered"
+	DeviceSucceeded      = "succeeded"
+	DeviceFailed         = "failed"
+	DeviceRollingBack    = "rolling_back"
+	DeviceRolledBack     = "rolled_back"
+	DeviceRollbackFailed = "rollback_failed"
+
+	// [DeviceRollbackUnsupported] is a device that pulled the update, it
+	// can't be made to install the previous one
+	DeviceRollbackUnsupported = "rollback_unsupported"
+)
+
+var (
+	ErrInvalidSpec       = errors.New("invalid rollout spec")
+	ErrRolloutNotFound   = errors.New("rollout not found")
+	ErrInvalidTransition = errors.New("rollout can't do that in its current status")
+	ErrNoDevices         = errors.New("no devices in the group")
+	ErrNoPreviousUpdate  = errors.New("no previous update to roll back to")
+	ErrUnknownUpdate     = errors.New("update not found")
+	ErrDeviceMismatch    = errors.New("update is not for the devices of the group")
+	ErrNotConfirmed      = errors.New("device did not confirm the update in time")
+	ErrReportedFailure   = errors.New("device reported the update failed")
+	ErrPullRollback      = errors.New("device pulls its updates, it can't be made to install the previous one")
+)
+
+// Group selects attested devices by the category and, optionally, the
+// manufacturer of their attestation. Only active attestations are targeted.
+type Group struct {
+	Category     string `json:"category"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+}
+
+// Device is a member of a group.
+type Device struct {
+	AttestTx ids.ID `json:"attest_tx"`
+	Address  string `json:"address"`
+
+	// [Endpoint] is where the device is reached, empty if the device can't
+	// be reached by the gateway. Such devices pull their updates, they are
+	// offered the update when their wave is reached and fail unless they
+	// confirm it within the offer timeout of the rollout.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Inventory resolves the devices of a group.
+type Inventory interface {
+	Devices(ctx context.Context, group Group) ([]Device, error)
+}
+
+// Pusher installs [update] on [device]. It returns once the device accepted
+// the update, or with the reason it didn't.
+type Pusher interface {
+	Push(ctx context.Context, device Device, update ids.ID) error
+}
+
+// Updates looks up updates and their install reports on chain.
+type Updates interface {
+	// DeviceName returns the name of the devices [update] is for, or
+	// [ErrUnknownUpdate].
+	DeviceName(ctx context.Context, update ids.ID) (string, error)
+	// Report returns whether [device] reported installing [update] on chain
+	// and, if it did, whether the install succeeded.
+	Report(ctx context.Context, device Device, update ids.ID) (bool, bool, error)
+}
+
+// Spec describes a rollout.
+type Spec struct {
+	UpdateTx ids.ID
+	Group    Group
+
+	// [Waves] are the cumulative fractions of the group updated by the end
+	// of each wave, e.g. [0.01, 0.1, 1] updates 1% of the devices, then up
+	// to 10%, then all of them. The last wave must be 1.
+	Waves []float64
+	// [Concurrency] bounds the devices updated at once.
+	Concurrency int
+	// [MaxFailureRate] is the fraction of a wave allowed to fail before the
+	// rollout halts.
+	MaxFailureRate float64
+	// [WaveDelay] is how long to wait between waves, so failures that show
+	// after the install can be reported and the rollout halted.
+	WaveDelay time.Duration
+	// [OfferTimeout] is how long devices that pull their updates have to
+	// confirm the update they were offered before they count as failed.
+	OfferTimeout time.Duration
+
+	// [PreviousUpdateTx], if set, is rolled back to on devices that have not
+	// been updated by an earlier rollout.
+	PreviousUpdateTx ids.ID
+}
+
+// DefaultSpec returns a spec for [update] with a canary wave of 1%, then
+// 10% and the rest, up to 10 devices at a time and halting when more than
+// 5% of a wave fails. Devices that pull their updates have a day to
+// install them.
+func DefaultSpec(update ids.ID, group Group) Spec {
+	return Spec{
+		UpdateTx:       update,
+		Group:          group,
+		Waves:          []float64{0.01, 0.1, 1},
+		Concurrency:    10,
+		MaxFailureRate: 0.05,
+		WaveDelay:      time.Minute,
+		OfferTimeout:   24 * time.Hour,
+	}
+}
+
+// Verify checks that [s] describes a rollout that can run.
+func (s Spec) Verify() error {
+	switch {
+	case s.UpdateTx == ids.Empty:
+		return fmt.Errorf("%w: missing update", ErrInvalidSpec)
+	case len(s.Group.Category) == 0:
+		return fmt.Errorf("%w: missing group category", ErrInvalidSpec)
+	case len(s.Waves) == 0 || s.Waves[len(s.Waves)-1] != 1:
+		return fmt.Errorf("%w: the last wave must update the whole group", ErrInvalidSpec)
+	case s.Concurrency <= 0:
+		return fmt.Errorf("%w: concurrency must be positive", ErrInvalidSpec)
+	case s.MaxFailureRate < 0 || s.MaxFailureRate > 1:
+		return fmt.Errorf("%w: max failure rate must be between 0 and 1", ErrInvalidSpec)
+	case s.WaveDelay < 0:
+		return fmt.Errorf("%w: negative wave delay", ErrInvalidSpec)
+	case s.OfferTimeout <= 0:
+		return fmt.Errorf("%w: offer timeout must be positive", ErrInvalidSpec)
+	}
+	for i, w := range s.Waves {
+		if w <= 0 || (i > 0 && w <= s.Waves[i-1]) {
+			return fmt.Errorf("%w: waves must be increasing fractions", ErrInvalidSpec)
+		}
+	}
+	return nil
+}
+
+// waveOf returns the wave of the [i]-th of [n] devices.
+func (s Spec) waveOf(i int, n int) int {
+	for wave, w := range s.Waves {
+		if i < int(math.Ceil(w*float64(n))) {
+			return wave
+		}
+	}
+	return len(s.Waves) - 1
+}
+
+// Rollout is the persisted state of a rollout.
+type Rollout struct {
+	ID               uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	UpdateTx         string        `json:"update_tx"`
+	PreviousUpdateTx string        `json:"previous_update_tx,omitempty"`
+	Category         string        `json:"category"`
+	Manufacturer     string        `json:"manufacturer,omitempty"`
+	Waves            int           `json:"waves"`
+	Concurrency      int           `json:"concurrency"`
+	MaxFailureRate   float64       `json:"max_failure_rate"`
+	WaveDelay        time.Duration `json:"wave_delay_ns"`
+	OfferTimeout     time.Duration `json:"offer_timeout_ns"`
+
+	// [Wave] is the wave being updated
+	Wave   int    `json:"wave"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RolloutDevice is the persisted state of a device in a rollout.
+type RolloutDevice struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement" json:"-"`
+	RolloutID uint   `gorm:"uniqueIndex:idx_rollout_device" json:"rollout_id"`
+	AttestTx  string `gorm:"uniqueIndex:idx_rollout_device" json:"attest_tx"`
+	Address   string `gorm:"index" json:"address"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Wave      int    `json:"wave"`
+
+	// [PreviousUpdateTx] is what a rollback installs, the update the device
+	// had before the rollout if known. [InstalledTx] is the update the
+	// rollout last installed.
+	PreviousUpdateTx string `json:"previous_update_tx,omitempty"`
+	InstalledTx      string `json:"installed_tx,omitempty"`
+
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	OfferedAt time.Time `json:"offered_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (d *RolloutDevice) device() (Device, error) {
+	attestTx, err := ids.FromString(d.AttestTx)
+	if err != nil {
+		return Device{}, err
+	}
+	return Device{AttestTx: attestTx, Address: d.Address, Endpoint: d.Endpoint}, nil
+}
+
+// Status is a rollout with how many of its devices are in each status.
+type Status struct {
+	Rollout *Rollout       `json:"rollout"`
+	Devices map[string]int `json:"devices"`
+}
@@ -39,9 +39,34 @@ func init() {
 		consts.ActionRegistry.Register((&actions.RegisterMachine{}).GetTypeID(), actions.UnmarshalRegisterMachineCID, false),
 		consts.ActionRegistry.Register((&actions.AttestMachine{}).GetTypeID(), actions.UnmarshalAttestMachineCID, false),
 		consts.ActionRegistry.Register((&actions.NotarizeData{}).GetTypeID(), actions.UnmarshalNotarizeData, false),
+		consts.ActionRegistry.Register((&actions.ReportUpdateResult{}).GetTypeID(), actions.UnmarshalReportUpdateResult, false),
+		consts.ActionRegistry.Register((&actions.AddProjectMaintainer{}).GetTypeID(), actions.UnmarshalAddProjectMaintainer, false),
+		consts.ActionRegistry.Register((&actions.RemoveProjectMaintainer{}).GetTypeID(), actions.UnmarshalRemoveProjectMaintainer, false),
+		consts.ActionRegistry.Register((&actions.TransferProjectOwnership{}).GetTypeID(), actions.UnmarshalTransferProjectOwnership, false),
+		consts.ActionRegistry.Register((&actions.UpdateProjectMetadata{}).GetTypeID(), actions.UnmarshalUpdateProjectMetadata, false),
+		consts.ActionRegistry.Register((&actions.SetProjectReleaseKey{}).GetTypeID(), actions.UnmarshalSetProjectReleaseKey, false),
+		consts.ActionRegistry.Register((&actions.RevokeMachine{}).GetTypeID(), actions.UnmarshalRevokeMachine, false),
+		consts.ActionRegistry.Register((&actions.SuspendMachine{}).GetTypeID(), actions.UnmarshalSuspendMachine, false),
+		consts.ActionRegistry.Register((&actions.ReinstateMachine{}).GetTypeID(), actions.UnmarshalReinstateMachine, false),
+		consts.ActionRegistry.Register((&actions.ListData{}).GetTypeID(), actions.UnmarshalListData, false),
+		consts.ActionRegistry.Register((&actions.PurchaseData{}).GetTypeID(), actions.UnmarshalPurchaseData, false),
+		consts.ActionRegistry.Register((&actions.DelistData{}).GetTypeID(), actions.UnmarshalDelistData, false),
+		consts.ActionRegistry.Register((&actions.OfferLicense{}).GetTypeID(), actions.UnmarshalOfferLicense, false),
+		consts.ActionRegistry.Register((&actions.PurchaseLicense{}).GetTypeID(), actions.UnmarshalPurchaseLicense, false),
+		consts.ActionRegistry.Register((&actions.WithdrawLicenseOffer{}).GetTypeID(), actions.UnmarshalWithdrawLicenseOffer, false),
+		consts.ActionRegistry.Register((&actions.DeliverDataKey{}).GetTypeID(), actions.UnmarshalDeliverDataKey, false),
+		consts.ActionRegistry.Register((&actions.NotarizeBatch{}).GetTypeID(), actions.UnmarshalNotarizeBatch, false),
+		consts.ActionRegistry.Register((&actions.OnboardMachine{}).GetTypeID(), actions.UnmarshalOnboardMachine, false),
+		consts.ActionRegistry.Register((&actions.SetSponsorship{}).GetTypeID(), actions.UnmarshalSetSponsorship, false),
+		consts.ActionRegistry.Register((&actions.CreateManufacturer{}).GetTypeID(), actions.UnmarshalCreateManufacturer, false),
+		consts.ActionRegistry.Register((&actions.AddManufacturerAttester{}).GetTypeID(), actions.UnmarshalAddManufacturerAttester, false),
+		consts.ActionRegistry.Register((&actions.RevokeManufacturerAttester{}).GetTypeID(), actions.UnmarshalRevokeManufacturerAttester, false),
+		consts.ActionRegistry.Register((&actions.SetMetadataSchema{}).GetTypeID(), actions.UnmarshalSetMetadataSchema, false),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
+		consts.AuthRegistry.Register((&auth.SECP256R1{}).GetTypeID(), auth.UnmarshalSECP256R1, false),
+		consts.AuthRegistry.Register((&auth.Sponsored{}).GetTypeID(), auth.UnmarshalSponsored, false),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -6,5 +6,7 @@ package rpc
 const (
 	JSONRPCEndpoint = "/tokenapi"
 
-	ordersToSend = 128
+	ordersToSend         = 128
+	notarizationsToSend  = 128
+	projectChangesToSend = 256
 )
@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 )
 
 type Controller interface {
@@ -35,8 +36,29 @@ type Controller interface {
 	)
 	GetLoanFromState(context.Context, ids.ID, ids.ID) (uint64, error)
 	GetProjectFromState(context.Context, ids.ID) (bool, storage.ProjectData, error)
+	GetProjectMaintainersFromState(context.Context, ids.ID) ([]codec.Address, error)
+	GetProjectReleaseKeyFromState(context.Context, ids.ID) (bool, ed25519.PublicKey, error)
+	GetProjectHistory(context.Context, ids.ID, []byte, int) ([]storage.ProjectChange, []byte, error)
 	GetUpdateFromState(context.Context, ids.ID) (bool, storage.UpdateData, error)
+	GetLatestUpdateFromState(context.Context, ids.ID, []byte) (bool, ids.ID, storage.Version, error)
+	GetUpdateStatsFromState(context.Context, ids.ID) (uint64, uint64, error)
+	GetUpdateReportFromState(context.Context, ids.ID, ids.ID) (bool, storage.UpdateReportData, error)
 	GetMachineCID(context.Context, ids.ID) (bool, storage.RegisterMachineCIDData, error)
+	GetMachineCIDIndexFromState(context.Context, []byte) (bool, ids.ID, error)
+	GetMachineKeyIndexFromState(context.Context, uint8, []byte) (bool, ids.ID, error)
+	GetMachineAttestationFromState(context.Context, codec.Address) (bool, ids.ID, error)
 	GetAttestMachine(context.Context, ids.ID) (bool, storage.AttestMachineData, error)
 	GetNotarizeData(context.Context, ids.ID) (bool, storage.NotarizeDataData, error)
+	GetDataCIDNotarizationsFromState(context.Context, []byte) ([]storage.NotarizationRef, error)
+	GetMachineNotarizations(context.Context, codec.Address, []byte, int) ([]storage.NotarizationRef, []byte, error)
+	GetListingFromState(context.Context, ids.ID) (bool, storage.ListingData, error)
+	GetPurchaseReceiptFromState(context.Context, ids.ID, codec.Address) (bool, storage.PurchaseReceiptData, error)
+	GetLicenseOfferFromState(context.Context, ids.ID) (bool, storage.LicenseOfferData, error)
+	GetLicenseFromState(context.Context, ids.ID, codec.Address) (bool, storage.LicenseData, error)
+	GetTimestampFromState(context.Context) (int64, error)
+	GetKeyEnvelopeFromState(context.Context, ids.ID, codec.Address) (bool, storage.KeyEnvelopeData, error)
+	GetNotarizeBatchFromState(context.Context, ids.ID) (bool, storage.NotarizeBatchData, error)
+	GetSponsorshipFromState(context.Context, ids.ID) (bool, storage.SponsorshipData, error)
+	GetManufacturerFromState(context.Context, []byte) (bool, storage.ManufacturerData, error)
+	GetMetadataSchemaFromState(context.Context, []byte) (bool, storage.MetadataSchemaData, error)
 }
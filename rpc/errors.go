@@ -6,12 +6,26 @@ package rpc
 import "errors"
 
 var (
-	ErrTxNotFound            = errors.New("tx not found")
-	ErrAssetNotFound         = errors.New("asset not found")
-	ErrOrderNotFound         = errors.New("order not found")
-	ErrProjectNotFound       = errors.New("project not found")
-	ErrUpdateNotFound        = errors.New("update not found")
-	ErrMachineCIDNotFound    = errors.New("machine cid not found")
-	ErrAttestMachineNotFound = errors.New("attested Machine not found")
-	ErrNotarizedDataNotFound = errors.New("Invalid Notarized Data")
+	ErrTxNotFound              = errors.New("tx not found")
+	ErrAssetNotFound           = errors.New("asset not found")
+	ErrOrderNotFound           = errors.New("order not found")
+	ErrProjectNotFound         = errors.New("project not found")
+	ErrUpdateNotFound          = errors.New("update not found")
+	ErrUpdateReportNotFound    = errors.New("update report not found")
+	ErrInvalidReleaseKey       = errors.New("invalid release key")
+	ErrMachineCIDNotFound      = errors.New("machine cid not found")
+	ErrAttestMachineNotFound   = errors.New("attested Machine not found")
+	ErrNotarizedDataNotFound   = errors.New("Invalid Notarized Data")
+	ErrDataNotNotarized        = errors.New("data not notarized")
+	ErrNotarizationTimeUnknown = errors.New("notarization time unknown")
+	ErrMissingData             = errors.New("data or cid must be provided")
+	ErrListingNotFound         = errors.New("listing not found")
+	ErrPurchaseNotFound        = errors.New("purchase receipt not found")
+	ErrLicenseOfferNotFound    = errors.New("license offer not found")
+	ErrLicenseNotFound         = errors.New("license not found")
+	ErrKeyEnvelopeNotFound     = errors.New("key envelope not found")
+	ErrBatchNotFound           = errors.New("notarized batch not found")
+	ErrSponsorshipNotFound     = errors.New("sponsorship not found")
+	ErrManufacturerNotFound    = errors.New("manufacturer not found")
+	ErrMetadataSchemaNotFound  = errors.New("metadata schema not found")
 )
@@ -15,8 +15,10 @@ import (
 	"dataverse/genesis"
 	"dataverse/orderbook"
 	_ "dataverse/registry" // ensure registry populated
+	"dataverse/storage"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/requester"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
@@ -252,7 +254,7 @@ func (cli *JSONRPCClient) Project(
 	ctx context.Context,
 	project ids.ID,
 	useCache bool,
-) ([]byte, []byte, []byte, []byte, []byte, error) {
+) ([]byte, []byte, []byte, []byte, []byte, []string, []*ProjectChange, error) {
 
 	resp := new(ProjectReply)
 	err := cli.requester.SendRequest(
@@ -263,14 +265,57 @@ func (cli *JSONRPCClient) Project(
 		},
 		resp,
 	)
-	return resp.ID, resp.ProjectName, resp.ProjectDescription, resp.ProjectOwner, resp.Logo, err
+	return resp.ID, resp.ProjectName, resp.ProjectDescription, resp.ProjectOwner, resp.Logo, resp.Maintainers, resp.History, err
+}
+
+// ProjectHistory returns up to [limit] changes made to [project], starting
+// at [cursor], and the cursor of the next page (nil on the last one).
+func (cli *JSONRPCClient) ProjectHistory(
+	ctx context.Context,
+	project ids.ID,
+	cursor []byte,
+	limit int,
+) ([]*ProjectChange, []byte, error) {
+	resp := new(ProjectReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"project",
+		&ProjectArgs{
+			Project:       project,
+			HistoryCursor: cursor,
+			HistoryLimit:  limit,
+		},
+		resp,
+	)
+	return resp.History, resp.HistoryCursor, err
+}
+
+// ReleaseKey returns the key that signs the updates of [project], if one is
+// registered.
+func (cli *JSONRPCClient) ReleaseKey(ctx context.Context, project ids.ID) (bool, ed25519.PublicKey, error) {
+	resp := new(ProjectReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"project",
+		&ProjectArgs{
+			Project: project,
+		},
+		resp,
+	)
+	if err != nil || len(resp.ReleaseKey) == 0 {
+		return false, ed25519.EmptyPublicKey, err
+	}
+	if len(resp.ReleaseKey) != ed25519.PublicKeyLen {
+		return false, ed25519.EmptyPublicKey, ErrInvalidReleaseKey
+	}
+	return true, ed25519.PublicKey(resp.ReleaseKey), nil
 }
 
 func (cli *JSONRPCClient) Update(
 	ctx context.Context,
 	update ids.ID,
 	useCache bool,
-) ([]byte, []byte, []byte, []byte, []byte, uint8, uint8, error) {
+) ([]byte, ids.ID, []byte, []byte, []byte, storage.Version, uint64, error) {
 
 	resp := new(UpdateReply)
 	err := cli.requester.SendRequest(
@@ -285,6 +330,68 @@ func (cli *JSONRPCClient) Update(
 	return resp.ID, resp.ProjectTxID, resp.UpdateExecutableHash, resp.UpdateIPFSUrl, resp.ForDeviceName, resp.UpdateVersion, resp.SuccessCount, err
 }
 
+// UpdateManifest returns an update with the size and signature needed to
+// verify its executable.
+func (cli *JSONRPCClient) UpdateManifest(ctx context.Context, update ids.ID) (*UpdateReply, error) {
+	resp := new(UpdateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"update",
+		&UpdateArgs{
+			Update: update,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// LatestUpdate returns the txID and contents of the most recent update of
+// [project] for [device].
+func (cli *JSONRPCClient) LatestUpdate(ctx context.Context, project ids.ID, device string) (*LatestUpdateReply, error) {
+	resp := new(LatestUpdateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"latestUpdate",
+		&LatestUpdateArgs{
+			Project: project,
+			Device:  device,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) UpdateStats(ctx context.Context, update ids.ID) (uint64, uint64, error) {
+	resp := new(UpdateStatsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"updateStats",
+		&UpdateArgs{
+			Update: update,
+		},
+		resp,
+	)
+	return resp.Succeeded, resp.Failed, err
+}
+
+func (cli *JSONRPCClient) UpdateReport(
+	ctx context.Context,
+	update ids.ID,
+	machineAttestTx ids.ID,
+) (storage.UpdateReportData, error) {
+	resp := new(UpdateReportReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"updateReport",
+		&UpdateReportArgs{
+			Update:          update,
+			MachineAttestTx: machineAttestTx,
+		},
+		resp,
+	)
+	return resp.Report, err
+}
+
 func (cli *JSONRPCClient) MachineCID(
 	ctx context.Context,
 	machineCIDID ids.ID,
@@ -304,11 +411,47 @@ func (cli *JSONRPCClient) MachineCID(
 	return resp.ID, resp.MachineCID, err
 }
 
+// MachineRegistration returns the registration of [machineCID].
+func (cli *JSONRPCClient) MachineRegistration(
+	ctx context.Context,
+	machineCID string,
+) (*MachineRegistrationReply, error) {
+	resp := new(MachineRegistrationReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"machineRegistration",
+		&MachineRegistrationArgs{
+			MachineCID: machineCID,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// MachineKeyRegistration returns the registration of the machine key [key] of
+// type [keyType].
+func (cli *JSONRPCClient) MachineKeyRegistration(
+	ctx context.Context,
+	keyType uint8,
+	key []byte,
+) (*MachineRegistrationReply, error) {
+	resp := new(MachineRegistrationReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"machineRegistration",
+		&MachineRegistrationArgs{
+			KeyType:    keyType,
+			MachineKey: key,
+		},
+		resp,
+	)
+	return resp, err
+}
+
 func (cli *JSONRPCClient) AttestMachine(
 	ctx context.Context,
 	tx ids.ID,
-	useCache bool,
-) ([]byte, []byte, []byte, []byte, []byte, error) {
+) (*AttestMachineReply, error) {
 
 	resp := new(AttestMachineReply)
 	err := cli.requester.SendRequest(
@@ -319,15 +462,32 @@ func (cli *JSONRPCClient) AttestMachine(
 		},
 		resp,
 	)
+	return resp, err
+}
 
-	return resp.ID, resp.MachineAddress, resp.MachineCategory, resp.MachineManufacturer, resp.MachineCID, err
+// MachineAttestation returns the latest attestation of the machine
+// [address].
+func (cli *JSONRPCClient) MachineAttestation(
+	ctx context.Context,
+	address string,
+) (*AttestMachineReply, error) {
+	resp := new(AttestMachineReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"attestMachine",
+		&AttestMachineArgs{
+			MachineAddress: address,
+		},
+		resp,
+	)
+	return resp, err
 }
 
 func (cli *JSONRPCClient) NotarizeData(
 	ctx context.Context,
 	tx ids.ID,
 	useCache bool,
-) ([]byte, []byte, []byte, []byte, []byte, error) {
+) ([]byte, ids.ID, string, []byte, []byte, bool, error) {
 
 	resp := new(NotarizeDataReply)
 	err := cli.requester.SendRequest(
@@ -339,5 +499,243 @@ func (cli *JSONRPCClient) NotarizeData(
 		resp,
 	)
 
-	return resp.ID, resp.AttestMachineTx, resp.DataOwnerAddr, resp.DataCID, resp.DataType, err
+	return resp.ID, resp.AttestMachineTx, resp.DataOwnerAddr, resp.DataCID, resp.DataType, resp.AfterRevocation, err
+}
+
+// NotarizationsByMachine returns a page of the notarizations made by
+// [machine], oldest first. Pass the returned cursor to fetch the next page; it
+// is nil once every notarization has been returned.
+func (cli *JSONRPCClient) NotarizationsByMachine(
+	ctx context.Context,
+	machine string,
+	cursor []byte,
+	limit int,
+) ([]*MachineNotarization, []byte, error) {
+	resp := new(NotarizationsByMachineReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"notarizationsByMachine",
+		&NotarizationsByMachineArgs{
+			Machine: machine,
+			Cursor:  cursor,
+			Limit:   limit,
+		},
+		resp,
+	)
+	return resp.Notarizations, resp.Cursor, err
+}
+
+// VerifyData returns the CID of the data and the notarizations recorded for
+// it. Either [data] or [dataCID] must be provided.
+func (cli *JSONRPCClient) VerifyData(
+	ctx context.Context,
+	data []byte,
+	dataCID string,
+) (string, []*VerifiedNotarization, error) {
+	resp := new(VerifyDataReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"verifyData",
+		&VerifyDataArgs{
+			Data: data,
+			CID:  dataCID,
+		},
+		resp,
+	)
+	return resp.CID, resp.Notarizations, err
+}
+
+func (cli *JSONRPCClient) Listing(
+	ctx context.Context,
+	listing ids.ID,
+) (*ListingReply, error) {
+	resp := new(ListingReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"listing",
+		&ListingArgs{
+			Listing: listing,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) PurchaseReceipt(
+	ctx context.Context,
+	listing ids.ID,
+	buyer string,
+) (*PurchaseReceiptReply, error) {
+	resp := new(PurchaseReceiptReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"purchaseReceipt",
+		&PurchaseReceiptArgs{
+			Listing: listing,
+			Buyer:   buyer,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) LicenseOffer(
+	ctx context.Context,
+	offer ids.ID,
+) (*LicenseOfferReply, error) {
+	resp := new(LicenseOfferReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"licenseOffer",
+		&LicenseOfferArgs{
+			Offer: offer,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) License(
+	ctx context.Context,
+	subject ids.ID,
+	licensee string,
+) (*LicenseReply, error) {
+	resp := new(LicenseReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"license",
+		&LicenseArgs{
+			Subject:  subject,
+			Licensee: licensee,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// HasAccess reports whether [licensee] may access [subject] as of the last
+// accepted block.
+func (cli *JSONRPCClient) HasAccess(
+	ctx context.Context,
+	subject ids.ID,
+	licensee string,
+) (*HasAccessReply, error) {
+	resp := new(HasAccessReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"hasAccess",
+		&HasAccessArgs{
+			Subject:  subject,
+			Licensee: licensee,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) KeyEnvelope(
+	ctx context.Context,
+	listing ids.ID,
+	buyer string,
+) (*KeyEnvelopeReply, error) {
+	resp := new(KeyEnvelopeReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"keyEnvelope",
+		&KeyEnvelopeArgs{
+			Listing: listing,
+			Buyer:   buyer,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) NotarizeBatch(
+	ctx context.Context,
+	tx ids.ID,
+) (*NotarizeBatchReply, error) {
+	resp := new(NotarizeBatchReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"notarizeBatch",
+		&NotarizeBatchArgs{
+			Tx: tx,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// VerifyInclusion checks [proof] that the multihash of [dataCID] is the leaf
+// at [index] of [batch]. Proofs are built with the merkle package.
+func (cli *JSONRPCClient) VerifyInclusion(
+	ctx context.Context,
+	batch ids.ID,
+	dataCID string,
+	index uint32,
+	proof [][]byte,
+) (*VerifyInclusionReply, error) {
+	resp := new(VerifyInclusionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"verifyInclusion",
+		&VerifyInclusionArgs{
+			Batch: batch,
+			CID:   dataCID,
+			Index: index,
+			Proof: proof,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) Sponsorship(
+	ctx context.Context,
+	attestTx ids.ID,
+) (*SponsorshipReply, error) {
+	resp := new(SponsorshipReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"sponsorship",
+		&SponsorshipArgs{
+			MachineAttestTx: attestTx,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) Manufacturer(
+	ctx context.Context,
+	name string,
+) (*ManufacturerReply, error) {
+	resp := new(ManufacturerReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"manufacturer",
+		&ManufacturerArgs{
+			Name: name,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// MetadataSchema returns the metadata schema of machines of [category].
+func (cli *JSONRPCClient) MetadataSchema(
+	ctx context.Context,
+	category string,
+) (*MetadataSchemaReply, error) {
+	resp := new(MetadataSchemaReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"metadataSchema",
+		&MetadataSchemaArgs{
+			Category: category,
+		},
+		resp,
+	)
+	return resp, err
 }
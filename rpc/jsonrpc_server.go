@@ -4,13 +4,17 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/ava-labs/avalanchego/ids"
 
 	"dataverse/consts"
+	"dataverse/content"
 	"dataverse/genesis"
+	"dataverse/merkle"
 	"dataverse/orderbook"
+	"dataverse/storage"
 
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
@@ -190,14 +194,31 @@ func (j *JSONRPCServer) Loan(req *http.Request, args *LoanArgs, reply *LoanReply
 
 type ProjectArgs struct {
 	Project ids.ID `json:"project"`
+
+	// [HistoryCursor] and [HistoryLimit] page through the history of the
+	// project, from its first change.
+	HistoryCursor []byte `json:"history_cursor"`
+	HistoryLimit  int    `json:"history_limit"`
+}
+
+type ProjectChange struct {
+	TxID      ids.ID `json:"tx_id"`
+	Timestamp int64  `json:"timestamp"`
+	Kind      string `json:"kind"`
+	Actor     string `json:"actor"`
+	Subject   string `json:"subject,omitempty"`
 }
 
 type ProjectReply struct {
-	ID                 []byte `json:"ID"`
-	ProjectName        []byte `json:"name"`
-	ProjectDescription []byte `json:"description"`
-	ProjectOwner       []byte `json:"owner"`
-	Logo               []byte `json:"logo"`
+	ID                 []byte           `json:"ID"`
+	ProjectName        []byte           `json:"name"`
+	ProjectDescription []byte           `json:"description"`
+	ProjectOwner       []byte           `json:"owner"`
+	Logo               []byte           `json:"logo"`
+	Maintainers        []string         `json:"maintainers"`
+	ReleaseKey         []byte           `json:"release_key,omitempty"`
+	History            []*ProjectChange `json:"history"`
+	HistoryCursor      []byte           `json:"history_cursor"`
 }
 
 func (j *JSONRPCServer) Project(req *http.Request, args *ProjectArgs, reply *ProjectReply) error {
@@ -217,7 +238,47 @@ func (j *JSONRPCServer) Project(req *http.Request, args *ProjectArgs, reply *Pro
 	reply.ProjectDescription = project.ProjectDescription
 	reply.ProjectOwner = project.ProjectOwner
 	reply.Logo = project.Logo
-	return err
+
+	maintainers, err := j.c.GetProjectMaintainersFromState(ctx, args.Project)
+	if err != nil {
+		return err
+	}
+	reply.Maintainers = make([]string, 0, len(maintainers))
+	for _, maintainer := range maintainers {
+		reply.Maintainers = append(reply.Maintainers, codec.MustAddressBech32(consts.HRP, maintainer))
+	}
+
+	hasReleaseKey, releaseKey, err := j.c.GetProjectReleaseKeyFromState(ctx, args.Project)
+	if err != nil {
+		return err
+	}
+	if hasReleaseKey {
+		reply.ReleaseKey = releaseKey[:]
+	}
+
+	limit := args.HistoryLimit
+	if limit <= 0 || limit > projectChangesToSend {
+		limit = projectChangesToSend
+	}
+	changes, cursor, err := j.c.GetProjectHistory(ctx, args.Project, args.HistoryCursor, limit)
+	if err != nil {
+		return err
+	}
+	reply.HistoryCursor = cursor
+	reply.History = make([]*ProjectChange, 0, len(changes))
+	for _, change := range changes {
+		c := &ProjectChange{
+			TxID:      change.TxID,
+			Timestamp: change.Timestamp,
+			Kind:      change.Kind.String(),
+			Actor:     codec.MustAddressBech32(consts.HRP, change.Actor),
+		}
+		if change.Subject != codec.EmptyAddress {
+			c.Subject = codec.MustAddressBech32(consts.HRP, change.Subject)
+		}
+		reply.History = append(reply.History, c)
+	}
+	return nil
 
 }
 
@@ -226,13 +287,15 @@ type UpdateArgs struct {
 }
 
 type UpdateReply struct {
-	ID                   []byte `json:"ID"`
-	ProjectTxID          []byte `json:"project_id"` // reference to Project
-	UpdateExecutableHash []byte `json:"executable_hash"`
-	UpdateIPFSUrl        []byte `json:"executable_ipfs_url"`
-	ForDeviceName        []byte `json:"for_device_name"`
-	UpdateVersion        uint8  `json:"version"`
-	SuccessCount         uint8  `json:"success_count"`
+	ID                   []byte          `json:"ID"`
+	ProjectTxID          ids.ID          `json:"project_id"` // reference to Project
+	UpdateExecutableHash []byte          `json:"executable_hash"`
+	UpdateIPFSUrl        []byte          `json:"executable_ipfs_url"`
+	ForDeviceName        []byte          `json:"for_device_name"`
+	UpdateVersion        storage.Version `json:"version"`
+	SuccessCount         uint64          `json:"success_count"`
+	UpdateSize           uint64          `json:"size"`
+	Signature            []byte          `json:"signature"`
 }
 
 func (j *JSONRPCServer) Update(req *http.Request, args *UpdateArgs, reply *UpdateReply) error {
@@ -250,17 +313,106 @@ func (j *JSONRPCServer) Update(req *http.Request, args *UpdateArgs, reply *Updat
 	}
 
 	reply.ID = []byte(update.Key)
-	reply.ProjectTxID = []byte(update.ProjectTxID)
+	reply.ProjectTxID = update.ProjectTxID
 	reply.UpdateExecutableHash = []byte(update.UpdateExecutableHash)
 	reply.UpdateIPFSUrl = []byte(update.UpdateIPFSUrl)
 	reply.ForDeviceName = []byte(update.ForDeviceName)
-	reply.UpdateVersion = uint8(update.UpdateVersion)
-	reply.SuccessCount = uint8(update.SuccessCount)
+	reply.UpdateVersion = update.UpdateVersion
+	reply.UpdateSize = update.UpdateSize
+	reply.Signature = update.Signature
+	reply.SuccessCount, _, err = j.c.GetUpdateStatsFromState(ctx, args.Update)
 
 	return err
 
 }
 
+type LatestUpdateArgs struct {
+	Project ids.ID `json:"project"`
+	Device  string `json:"device"`
+}
+
+type LatestUpdateReply struct {
+	UpdateTx ids.ID `json:"update_tx"`
+	UpdateReply
+}
+
+// LatestUpdate returns the most recent update of a project for a device.
+func (j *JSONRPCServer) LatestUpdate(req *http.Request, args *LatestUpdateArgs, reply *LatestUpdateReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.LatestUpdate")
+	defer span.End()
+
+	exists, updateTx, _, err := j.c.GetLatestUpdateFromState(ctx, args.Project, []byte(args.Device))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUpdateNotFound
+	}
+	exists, update, err := j.c.GetUpdateFromState(ctx, updateTx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUpdateNotFound
+	}
+
+	reply.UpdateTx = updateTx
+	reply.ID = []byte(update.Key)
+	reply.ProjectTxID = update.ProjectTxID
+	reply.UpdateExecutableHash = update.UpdateExecutableHash
+	reply.UpdateIPFSUrl = update.UpdateIPFSUrl
+	reply.ForDeviceName = update.ForDeviceName
+	reply.UpdateVersion = update.UpdateVersion
+	reply.UpdateSize = update.UpdateSize
+	reply.Signature = update.Signature
+	reply.SuccessCount, _, err = j.c.GetUpdateStatsFromState(ctx, updateTx)
+	return err
+}
+
+type UpdateStatsReply struct {
+	Succeeded uint64 `json:"succeeded"`
+	Failed    uint64 `json:"failed"`
+}
+
+// UpdateStats returns how many machines reported a successful and a failed
+// install of an update.
+func (j *JSONRPCServer) UpdateStats(req *http.Request, args *UpdateArgs, reply *UpdateStatsReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.UpdateStats")
+	defer span.End()
+
+	succeeded, failed, err := j.c.GetUpdateStatsFromState(ctx, args.Update)
+	if err != nil {
+		return err
+	}
+	reply.Succeeded = succeeded
+	reply.Failed = failed
+	return nil
+}
+
+type UpdateReportArgs struct {
+	Update          ids.ID `json:"update"`
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+}
+
+type UpdateReportReply struct {
+	Report storage.UpdateReportData `json:"report"`
+}
+
+func (j *JSONRPCServer) UpdateReport(req *http.Request, args *UpdateReportArgs, reply *UpdateReportReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.UpdateReport")
+	defer span.End()
+
+	exists, report, err := j.c.GetUpdateReportFromState(ctx, args.Update, args.MachineAttestTx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUpdateReportNotFound
+	}
+	reply.Report = report
+	return nil
+}
+
 type RegisterMachineCIDArgs struct {
 	MachineCIDID ids.ID `json:"MachineCID"`
 }
@@ -291,16 +443,88 @@ func (j *JSONRPCServer) MachineCID(req *http.Request, args *RegisterMachineCIDAr
 
 }
 
+type MachineRegistrationArgs struct {
+	// The registration is looked up by [MachineCID] if it is set, by
+	// [KeyType] and [MachineKey] otherwise.
+	MachineCID string `json:"machine_cid"`
+	KeyType    uint8  `json:"key_type"`
+	MachineKey []byte `json:"machine_key"`
+}
+
+type MachineRegistrationReply struct {
+	Tx             ids.ID `json:"tx"`
+	MachineCID     string `json:"machine_cid"`
+	KeyType        uint8  `json:"key_type"`
+	MachineKey     []byte `json:"machine_key"`
+	MachineAddress string `json:"machine_address"`
+}
+
+// MachineRegistration returns the registration that claimed a machine CID or
+// key.
+func (j *JSONRPCServer) MachineRegistration(
+	req *http.Request,
+	args *MachineRegistrationArgs,
+	reply *MachineRegistrationReply,
+) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.MachineRegistration")
+	defer span.End()
+
+	var (
+		exists bool
+		tx     ids.ID
+		err    error
+	)
+	switch {
+	case len(args.MachineCID) > 0:
+		exists, tx, err = j.c.GetMachineCIDIndexFromState(ctx, []byte(args.MachineCID))
+	case len(args.MachineKey) > 0:
+		exists, tx, err = j.c.GetMachineKeyIndexFromState(ctx, args.KeyType, args.MachineKey)
+	default:
+		return ErrMissingData
+	}
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrMachineCIDNotFound
+	}
+	exists, machine, err := j.c.GetMachineCID(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrMachineCIDNotFound
+	}
+	reply.Tx = tx
+	reply.MachineCID = string(machine.MachineCID)
+	reply.KeyType = machine.KeyType
+	reply.MachineKey = machine.MachineKey
+	reply.MachineAddress = codec.MustAddressBech32(consts.HRP, machine.MachineAddress)
+	return nil
+}
+
 type AttestMachineArgs struct {
-	Tx ids.ID `json:"Tx"`
+	// The attestation is [Tx] if it is set, the latest attestation of
+	// [MachineAddress] otherwise.
+	Tx             ids.ID `json:"Tx"`
+	MachineAddress string `json:"machine_address"`
 }
 
 type AttestMachineReply struct {
 	ID                  []byte `json:"ID"`
-	MachineAddress      []byte `json:"machine_address"`
+	Tx                  ids.ID `json:"tx"`
+	MachineAddress      string `json:"machine_address"`
 	MachineCategory     []byte `json:"machine_category"`
 	MachineManufacturer []byte `json:"machine_manufacturer"`
 	MachineCID          []byte `json:"machine_cid"`
+	Attester            string `json:"attester"`
+	Status              string `json:"status"`
+	StatusChanged       int64  `json:"status_changed,omitempty"`
+	RevokedAt           int64  `json:"revoked_at,omitempty"`
+
+	// [Metadata] maps each metadata field to its value: a string, a number
+	// or a list of sensors. Versions and dates are strings.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 func (j *JSONRPCServer) AttestMachine(req *http.Request, args *AttestMachineArgs, reply *AttestMachineReply) error {
@@ -308,7 +532,22 @@ func (j *JSONRPCServer) AttestMachine(req *http.Request, args *AttestMachineArgs
 	ctx, span := j.c.Tracer().Start(req.Context(), "Server.AttestMachine")
 	defer span.End()
 
-	exists, attestmachine, err := j.c.GetAttestMachine(ctx, args.Tx)
+	tx := args.Tx
+	if tx == ids.Empty && len(args.MachineAddress) > 0 {
+		machine, err := codec.ParseAddressBech32(consts.HRP, args.MachineAddress)
+		if err != nil {
+			return err
+		}
+		exists, attestTx, err := j.c.GetMachineAttestationFromState(ctx, machine)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrAttestMachineNotFound
+		}
+		tx = attestTx
+	}
+	exists, attestmachine, err := j.c.GetAttestMachine(ctx, tx)
 
 	if err != nil {
 		return err
@@ -318,10 +557,16 @@ func (j *JSONRPCServer) AttestMachine(req *http.Request, args *AttestMachineArgs
 	}
 
 	reply.ID = []byte(attestmachine.Key)
-	reply.MachineAddress = []byte(attestmachine.MachineAddress)
+	reply.Tx = tx
+	reply.MachineAddress = codec.MustAddressBech32(consts.HRP, attestmachine.MachineAddress)
 	reply.MachineCategory = []byte(attestmachine.MachineCategory)
 	reply.MachineManufacturer = []byte(attestmachine.MachineManufacturer)
 	reply.MachineCID = []byte(attestmachine.MachineCID)
+	reply.Attester = codec.MustAddressBech32(consts.HRP, attestmachine.Attester)
+	reply.Status = attestmachine.Status.String()
+	reply.StatusChanged = attestmachine.StatusChanged
+	reply.RevokedAt = attestmachine.RevokedAt
+	reply.Metadata = attestmachine.Metadata.JSONValue()
 
 	return err
 
@@ -333,10 +578,16 @@ type NotarizeDataArgs struct {
 
 type NotarizeDataReply struct {
 	ID              []byte `json:"ID"`
-	AttestMachineTx []byte `json:"attest_machine_tx"`
-	DataOwnerAddr   []byte `json:"data_owner_address"`
+	AttestMachineTx ids.ID `json:"attest_machine_tx"`
+	DataOwnerAddr   string `json:"data_owner_address"`
 	DataCID         []byte `json:"data_cid"`
 	DataType        []byte `json:"data_type"`
+	Timestamp       int64  `json:"timestamp"`
+	MachineStatus   string `json:"machine_status"`
+
+	// [AfterRevocation] is set when the machine was revoked and the data was
+	// notarized after it stopped being trusted.
+	AfterRevocation bool `json:"after_revocation"`
 }
 
 func (j *JSONRPCServer) NotarizeData(req *http.Request, args *NotarizeDataArgs, reply *NotarizeDataReply) error {
@@ -354,11 +605,613 @@ func (j *JSONRPCServer) NotarizeData(req *http.Request, args *NotarizeDataArgs,
 	}
 
 	reply.ID = []byte(notarizeddata.Key)
-	reply.AttestMachineTx = []byte(notarizeddata.AttestMachineTx)
-	reply.DataOwnerAddr = []byte(notarizeddata.DataOwnerAddr)
+	reply.AttestMachineTx = notarizeddata.AttestMachineTx
+	reply.DataOwnerAddr = codec.MustAddressBech32(consts.HRP, notarizeddata.DataOwnerAddr)
 	reply.DataCID = []byte(notarizeddata.DataCID)
 	reply.DataType = []byte(notarizeddata.DataType)
 
-	return err
+	timestamp := notarizeddata.Timestamp
+	if timestamp == 0 {
+		// Data notarized before the time was recorded is only dated by the
+		// transaction index
+		found, t, _, _, _, err := j.c.GetTransaction(ctx, args.Tx)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotarizationTimeUnknown
+		}
+		timestamp = t
+	}
+	exists, machine, err := j.c.GetAttestMachine(ctx, notarizeddata.AttestMachineTx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAttestMachineNotFound
+	}
+	reply.Timestamp = timestamp
+	reply.MachineStatus = machine.Status.String()
+	reply.AfterRevocation = machine.RevokedBy(timestamp)
+	return nil
+
+}
+
+type NotarizationsByMachineArgs struct {
+	Machine string `json:"machine"`
+	Cursor  []byte `json:"cursor"`
+	Limit   int    `json:"limit"`
+}
+
+type MachineNotarization struct {
+	storage.NotarizationRef
+	Batch           bool `json:"batch"`
+	AfterRevocation bool `json:"after_revocation"`
+}
+
+type NotarizationsByMachineReply struct {
+	Notarizations []*MachineNotarization `json:"notarizations"`
+	Cursor        []byte                 `json:"cursor"`
+}
+
+func (j *JSONRPCServer) NotarizationsByMachine(req *http.Request, args *NotarizationsByMachineArgs, reply *NotarizationsByMachineReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.NotarizationsByMachine")
+	defer span.End()
+
+	machine, err := codec.ParseAddressBech32(consts.HRP, args.Machine)
+	if err != nil {
+		return err
+	}
+	limit := args.Limit
+	if limit <= 0 || limit > notarizationsToSend {
+		limit = notarizationsToSend
+	}
+	notarizations, cursor, err := j.c.GetMachineNotarizations(ctx, machine, args.Cursor, limit)
+	if err != nil {
+		return err
+	}
+	// A machine may have been attested more than once, each notarization is
+	// checked against the attestation it was made under.
+	attestations := map[ids.ID]storage.AttestMachineData{}
+	reply.Notarizations = make([]*MachineNotarization, 0, len(notarizations))
+	for _, ref := range notarizations {
+		exists, notarized, err := j.c.GetNotarizeData(ctx, ref.TxID)
+		if err != nil {
+			return err
+		}
+		attestTx, isBatch := notarized.AttestMachineTx, !exists
+		if isBatch {
+			// Batches are indexed with the notarizations of the machine
+			exists, batch, err := j.c.GetNotarizeBatchFromState(ctx, ref.TxID)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrNotarizedDataNotFound
+			}
+			attestTx = batch.AttestMachineTx
+		}
+		attestation, ok := attestations[attestTx]
+		if !ok {
+			exists, attestation, err = j.c.GetAttestMachine(ctx, attestTx)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrAttestMachineNotFound
+			}
+			attestations[attestTx] = attestation
+		}
+		reply.Notarizations = append(reply.Notarizations, &MachineNotarization{
+			NotarizationRef: ref,
+			Batch:           isBatch,
+			AfterRevocation: attestation.RevokedBy(ref.Timestamp),
+		})
+	}
+	reply.Cursor = cursor
+	return nil
+}
+
+// VerifyDataArgs identifies the data to verify either by its raw bytes or by
+// its CID. [Data] takes precedence when both are set.
+type VerifyDataArgs struct {
+	Data []byte `json:"data"`
+	CID  string `json:"cid"`
+}
+
+type VerifiedNotarization struct {
+	TxID            ids.ID `json:"tx_id"`
+	Machine         string `json:"machine"`
+	Owner           string `json:"owner"`
+	Timestamp       int64  `json:"timestamp"`
+	MachineStatus   string `json:"machine_status"`
+	AfterRevocation bool   `json:"after_revocation"`
+}
+
+type VerifyDataReply struct {
+	CID           string                  `json:"cid"`
+	Notarizations []*VerifiedNotarization `json:"notarizations"`
+}
+
+func (j *JSONRPCServer) VerifyData(req *http.Request, args *VerifyDataArgs, reply *VerifyDataReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.VerifyData")
+	defer span.End()
+
+	dataCID := args.CID
+	if len(args.Data) > 0 {
+		var err error
+		dataCID, err = content.CID(args.Data)
+		if err != nil {
+			return err
+		}
+	}
+	if len(dataCID) == 0 {
+		return ErrMissingData
+	}
+
+	multihash, err := content.Multihash(dataCID)
+	if err != nil {
+		return err
+	}
+	refs, err := j.c.GetDataCIDNotarizationsFromState(ctx, multihash)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return ErrDataNotNotarized
+	}
+
+	reply.CID = dataCID
+	reply.Notarizations = make([]*VerifiedNotarization, 0, len(refs))
+	for _, ref := range refs {
+		exists, notarized, err := j.c.GetNotarizeData(ctx, ref.TxID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotarizedDataNotFound
+		}
+		exists, machine, err := j.c.GetAttestMachine(ctx, notarized.AttestMachineTx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrAttestMachineNotFound
+		}
+		reply.Notarizations = append(reply.Notarizations, &VerifiedNotarization{
+			TxID:            ref.TxID,
+			Machine:         codec.MustAddressBech32(consts.HRP, machine.MachineAddress),
+			Owner:           codec.MustAddressBech32(consts.HRP, notarized.DataOwnerAddr),
+			Timestamp:       ref.Timestamp,
+			MachineStatus:   machine.Status.String(),
+			AfterRevocation: machine.RevokedBy(ref.Timestamp),
+		})
+	}
+	return nil
+}
+
+type ListingArgs struct {
+	Listing ids.ID `json:"listing"`
+}
+
+type ListingReply struct {
+	Notarization ids.ID `json:"notarization"`
+	Seller       string `json:"seller"`
+	Asset        ids.ID `json:"asset"`
+	Price        uint64 `json:"price"`
+	Closed       bool   `json:"closed"`
+}
+
+func (j *JSONRPCServer) Listing(req *http.Request, args *ListingArgs, reply *ListingReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Listing")
+	defer span.End()
+
+	exists, listing, err := j.c.GetListingFromState(ctx, args.Listing)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrListingNotFound
+	}
+	reply.Notarization = listing.Notarization
+	reply.Seller = codec.MustAddressBech32(consts.HRP, listing.Seller)
+	reply.Asset = listing.Asset
+	reply.Price = listing.Price
+	reply.Closed = listing.Closed
+	return nil
+}
+
+type PurchaseReceiptArgs struct {
+	Listing ids.ID `json:"listing"`
+	Buyer   string `json:"buyer"`
+}
+
+type PurchaseReceiptReply struct {
+	PurchaseTx ids.ID `json:"purchase_tx"`
+	Asset      ids.ID `json:"asset"`
+	Price      uint64 `json:"price"`
+	Timestamp  int64  `json:"timestamp"`
+	BuyerKey   []byte `json:"buyer_key"`
+}
 
+// PurchaseReceipt returns the receipt recorded when [args.Buyer] bought
+// [args.Listing]. It is the on-chain proof that the buyer paid for the data.
+func (j *JSONRPCServer) PurchaseReceipt(req *http.Request, args *PurchaseReceiptArgs, reply *PurchaseReceiptReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.PurchaseReceipt")
+	defer span.End()
+
+	buyer, err := codec.ParseAddressBech32(consts.HRP, args.Buyer)
+	if err != nil {
+		return err
+	}
+	exists, receipt, err := j.c.GetPurchaseReceiptFromState(ctx, args.Listing, buyer)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrPurchaseNotFound
+	}
+	reply.PurchaseTx = receipt.PurchaseTx
+	reply.Asset = receipt.Asset
+	reply.Price = receipt.Price
+	reply.Timestamp = receipt.Timestamp
+	reply.BuyerKey = receipt.BuyerKey[:]
+	return nil
+}
+
+type LicenseOfferArgs struct {
+	Offer ids.ID `json:"offer"`
+}
+
+type LicenseOfferReply struct {
+	Kind      string `json:"kind"`
+	Subject   ids.ID `json:"subject"`
+	Licensor  string `json:"licensor"`
+	Asset     ids.ID `json:"asset"`
+	Price     uint64 `json:"price"`
+	Period    int64  `json:"period"`
+	TermsHash []byte `json:"terms_hash"`
+	Closed    bool   `json:"closed"`
+}
+
+func (j *JSONRPCServer) LicenseOffer(req *http.Request, args *LicenseOfferArgs, reply *LicenseOfferReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.LicenseOffer")
+	defer span.End()
+
+	exists, offer, err := j.c.GetLicenseOfferFromState(ctx, args.Offer)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrLicenseOfferNotFound
+	}
+	reply.Kind = offer.Kind.String()
+	reply.Subject = offer.Subject
+	reply.Licensor = codec.MustAddressBech32(consts.HRP, offer.Licensor)
+	reply.Asset = offer.Asset
+	reply.Price = offer.Price
+	reply.Period = offer.Period
+	reply.TermsHash = offer.TermsHash
+	reply.Closed = offer.Closed
+	return nil
+}
+
+type LicenseArgs struct {
+	Subject  ids.ID `json:"subject"`
+	Licensee string `json:"licensee"`
+}
+
+type LicenseReply struct {
+	Offer     ids.ID `json:"offer"`
+	Kind      string `json:"kind"`
+	Licensor  string `json:"licensor"`
+	Expiry    int64  `json:"expiry"`
+	TermsHash []byte `json:"terms_hash"`
+}
+
+func (j *JSONRPCServer) License(req *http.Request, args *LicenseArgs, reply *LicenseReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.License")
+	defer span.End()
+
+	licensee, err := codec.ParseAddressBech32(consts.HRP, args.Licensee)
+	if err != nil {
+		return err
+	}
+	exists, license, err := j.c.GetLicenseFromState(ctx, args.Subject, licensee)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrLicenseNotFound
+	}
+	reply.Offer = license.Offer
+	reply.Kind = license.Kind.String()
+	reply.Licensor = codec.MustAddressBech32(consts.HRP, license.Licensor)
+	reply.Expiry = license.Expiry
+	reply.TermsHash = license.TermsHash
+	return nil
+}
+
+type HasAccessArgs struct {
+	// [Subject] is a notarization, a notarized batch or a machine
+	// attestation txID.
+	Subject  ids.ID `json:"subject"`
+	Licensee string `json:"licensee"`
+}
+
+type HasAccessReply struct {
+	HasAccess bool `json:"has_access"`
+
+	// [License] is the subject of the license granting access. It differs
+	// from the requested subject when a dataset is accessed through a
+	// license of the machine that notarized it.
+	License   ids.ID `json:"license"`
+	Expiry    int64  `json:"expiry,omitempty"`
+	TermsHash []byte `json:"terms_hash,omitempty"`
+
+	// [Timestamp] is the time of the last accepted block, access is checked
+	// against it.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// HasAccess reports whether [args.Licensee] holds an unexpired license for
+// [args.Subject]. Access to a dataset or a batch is also granted by a license
+// for the machine that notarized it. Batches can't be licensed on their own,
+// only through their machine.
+func (j *JSONRPCServer) HasAccess(req *http.Request, args *HasAccessArgs, reply *HasAccessReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.HasAccess")
+	defer span.End()
+
+	licensee, err := codec.ParseAddressBech32(consts.HRP, args.Licensee)
+	if err != nil {
+		return err
+	}
+	timestamp, err := j.c.GetTimestampFromState(ctx)
+	if err != nil {
+		return err
+	}
+	reply.Timestamp = timestamp
+
+	subjects := []ids.ID{args.Subject}
+	exists, notarized, err := j.c.GetNotarizeData(ctx, args.Subject)
+	if err != nil {
+		return err
+	}
+	if exists {
+		subjects = append(subjects, notarized.AttestMachineTx)
+	} else {
+		exists, batch, err := j.c.GetNotarizeBatchFromState(ctx, args.Subject)
+		if err != nil {
+			return err
+		}
+		if exists {
+			subjects = []ids.ID{batch.AttestMachineTx}
+		}
+	}
+	for _, subject := range subjects {
+		exists, license, err := j.c.GetLicenseFromState(ctx, subject, licensee)
+		if err != nil {
+			return err
+		}
+		if !exists || !license.Active(timestamp) {
+			continue
+		}
+		reply.HasAccess = true
+		reply.License = subject
+		reply.Expiry = license.Expiry
+		reply.TermsHash = license.TermsHash
+		return nil
+	}
+	return nil
+}
+
+type KeyEnvelopeArgs struct {
+	Listing ids.ID `json:"listing"`
+	Buyer   string `json:"buyer"`
+}
+
+type KeyEnvelopeReply struct {
+	DeliverTx ids.ID `json:"deliver_tx"`
+	Timestamp int64  `json:"timestamp"`
+	Envelope  []byte `json:"envelope"`
+}
+
+// KeyEnvelope returns the content key of [args.Listing] sealed to
+// [args.Buyer]. It can be opened with [content.OpenKey].
+func (j *JSONRPCServer) KeyEnvelope(req *http.Request, args *KeyEnvelopeArgs, reply *KeyEnvelopeReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.KeyEnvelope")
+	defer span.End()
+
+	buyer, err := codec.ParseAddressBech32(consts.HRP, args.Buyer)
+	if err != nil {
+		return err
+	}
+	exists, envelope, err := j.c.GetKeyEnvelopeFromState(ctx, args.Listing, buyer)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrKeyEnvelopeNotFound
+	}
+	reply.DeliverTx = envelope.DeliverTx
+	reply.Timestamp = envelope.Timestamp
+	reply.Envelope = envelope.Envelope
+	return nil
+}
+
+type NotarizeBatchArgs struct {
+	Tx ids.ID `json:"tx"`
+}
+
+type NotarizeBatchReply struct {
+	AttestMachineTx ids.ID `json:"attest_machine_tx"`
+	DataOwnerAddr   string `json:"data_owner_address"`
+	Root            []byte `json:"root"`
+	Count           uint32 `json:"count"`
+	Start           int64  `json:"start"`
+	End             int64  `json:"end"`
+	DataType        []byte `json:"data_type"`
+	Timestamp       int64  `json:"timestamp"`
+	MachineStatus   string `json:"machine_status"`
+
+	// [AfterRevocation] is set when the machine was revoked and the batch was
+	// notarized after it stopped being trusted.
+	AfterRevocation bool `json:"after_revocation"`
+}
+
+func (j *JSONRPCServer) NotarizeBatch(req *http.Request, args *NotarizeBatchArgs, reply *NotarizeBatchReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.NotarizeBatch")
+	defer span.End()
+
+	return j.notarizeBatch(ctx, args.Tx, reply)
+}
+
+func (j *JSONRPCServer) notarizeBatch(ctx context.Context, tx ids.ID, reply *NotarizeBatchReply) error {
+	exists, batch, err := j.c.GetNotarizeBatchFromState(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrBatchNotFound
+	}
+	exists, machine, err := j.c.GetAttestMachine(ctx, batch.AttestMachineTx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAttestMachineNotFound
+	}
+	reply.AttestMachineTx = batch.AttestMachineTx
+	reply.DataOwnerAddr = codec.MustAddressBech32(consts.HRP, batch.DataOwnerAddr)
+	reply.Root = batch.Root
+	reply.Count = batch.Count
+	reply.Start = batch.Start
+	reply.End = batch.End
+	reply.DataType = batch.DataType
+	reply.Timestamp = batch.Timestamp
+	reply.MachineStatus = machine.Status.String()
+	reply.AfterRevocation = machine.RevokedBy(batch.Timestamp)
+	return nil
+}
+
+type VerifyInclusionArgs struct {
+	Batch ids.ID   `json:"batch"`
+	CID   string   `json:"cid"`
+	Index uint32   `json:"index"`
+	Proof [][]byte `json:"proof"`
+}
+
+type VerifyInclusionReply struct {
+	Included bool `json:"included"`
+
+	// [Notarization] is the batch [CID] was checked against.
+	Notarization NotarizeBatchReply `json:"notarization"`
+}
+
+// VerifyInclusion checks that the multihash of [args.CID] is the leaf at
+// [args.Index] of the Merkle tree committed by the batch notarization
+// [args.Batch].
+func (j *JSONRPCServer) VerifyInclusion(req *http.Request, args *VerifyInclusionArgs, reply *VerifyInclusionReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.VerifyInclusion")
+	defer span.End()
+
+	if len(args.CID) == 0 {
+		return ErrMissingData
+	}
+	if err := j.notarizeBatch(ctx, args.Batch, &reply.Notarization); err != nil {
+		return err
+	}
+	multihash, err := content.Multihash(args.CID)
+	if err != nil {
+		return err
+	}
+	batch := &reply.Notarization
+	reply.Included = merkle.Verify(batch.Root, uint64(batch.Count), uint64(args.Index), multihash, args.Proof)
+	return nil
+}
+
+type SponsorshipArgs struct {
+	MachineAttestTx ids.ID `json:"machine_attest_tx"`
+}
+
+type SponsorshipReply struct {
+	Sponsor   string `json:"sponsor"`
+	Cap       uint64 `json:"cap"`
+	Spent     uint64 `json:"spent"`
+	Remaining uint64 `json:"remaining"`
+}
+
+func (j *JSONRPCServer) Sponsorship(req *http.Request, args *SponsorshipArgs, reply *SponsorshipReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Sponsorship")
+	defer span.End()
+
+	exists, sponsorship, err := j.c.GetSponsorshipFromState(ctx, args.MachineAttestTx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrSponsorshipNotFound
+	}
+	reply.Sponsor = codec.MustAddressBech32(consts.HRP, sponsorship.Sponsor)
+	reply.Cap = sponsorship.Cap
+	reply.Spent = sponsorship.Spent
+	reply.Remaining = sponsorship.Remaining()
+	return nil
+}
+
+type ManufacturerArgs struct {
+	Name string `json:"name"`
+}
+
+type ManufacturerReply struct {
+	Name      string   `json:"name"`
+	Owner     string   `json:"owner"`
+	Attesters []string `json:"attesters"`
+}
+
+func (j *JSONRPCServer) Manufacturer(req *http.Request, args *ManufacturerArgs, reply *ManufacturerReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Manufacturer")
+	defer span.End()
+
+	exists, manufacturer, err := j.c.GetManufacturerFromState(ctx, []byte(args.Name))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrManufacturerNotFound
+	}
+	reply.Name = string(manufacturer.Name)
+	reply.Owner = codec.MustAddressBech32(consts.HRP, manufacturer.Owner)
+	reply.Attesters = make([]string, len(manufacturer.Attesters))
+	for i, attester := range manufacturer.Attesters {
+		reply.Attesters[i] = codec.MustAddressBech32(consts.HRP, attester)
+	}
+	return nil
+}
+
+type MetadataSchemaArgs struct {
+	Category string `json:"category"`
+}
+
+type MetadataSchemaReply struct {
+	Category string                  `json:"category"`
+	Owner    string                  `json:"owner"`
+	Fields   []storage.MetadataField `json:"fields"`
+}
+
+func (j *JSONRPCServer) MetadataSchema(req *http.Request, args *MetadataSchemaArgs, reply *MetadataSchemaReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.MetadataSchema")
+	defer span.End()
+
+	exists, schema, err := j.c.GetMetadataSchemaFromState(ctx, []byte(args.Category))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrMetadataSchemaNotFound
+	}
+	reply.Category = string(schema.Category)
+	reply.Owner = codec.MustAddressBech32(consts.HRP, schema.Owner)
+	reply.Fields = schema.Fields
+	return nil
 }
@@ -0,0 +1,102 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+
+	"dataverse/consts"
+	"dataverse/gateway"
+)
+
+// requestTimeout bounds each call to the signer, [chain.AuthFactory.Sign]
+// takes no context.
+const requestTimeout = 10 * time.Second
+
+// Client calls a signer at [uri], authenticating with the gateway API key
+// [apiKey].
+type Client struct {
+	uri    string
+	apiKey string
+	client *http.Client
+}
+
+func NewClient(uri string, apiKey string) *Client {
+	return &Client{
+		uri:    strings.TrimSuffix(uri, "/"),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// call sends [args], if not nil, to [path] and decodes the reply into
+// [reply].
+func (c *Client) call(ctx context.Context, method string, path string, args any, reply any) error {
+	var body io.Reader
+	if args != nil {
+		b, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.uri+path, body)
+	if err != nil {
+		return err
+	}
+	if args != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(c.apiKey) > 0 {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var gatewayErr gateway.ErrorReply
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(b, &gatewayErr) != nil {
+			gatewayErr.Error = string(b)
+		}
+		if resp.StatusCode == http.StatusNotFound && gatewayErr.Error == ErrUnknownKey.Error() {
+			return ErrUnknownKey
+		}
+		return fmt.Errorf("signer %s: %s (%d)", path, gatewayErr.Error, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// Keys returns the keys held by the signer.
+func (c *Client) Keys(ctx context.Context) ([]Key, error) {
+	var reply KeysReply
+	if err := c.call(ctx, http.MethodGet, KeysPath, nil, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Keys, nil
+}
+
+// Sign returns the signature of [msg] by the key of [addr].
+func (c *Client) Sign(ctx context.Context, addr codec.Address, msg []byte) ([]byte, error) {
+	saddr, err := codec.AddressBech32(consts.HRP, addr)
+	if err != nil {
+		return nil, err
+	}
+	var reply SignReply
+	if err := c.call(ctx, http.MethodPost, SignPath, &SignArgs{Address: saddr, Message: msg}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+
+	"dataverse/auth"
+	"dataverse/consts"
+	"dataverse/storage"
+)
+
+var _ chain.AuthFactory = (*Factory)(nil)
+
+// Factory signs transactions with a key held by a signer. It can be wrapped
+// like any other factory, e.g. by [auth.NewSponsoredFactory].
+type Factory struct {
+	client *Client
+	addr   codec.Address
+	public []byte
+}
+
+// NewFactory returns the factory signing for [addr] with [client].
+// [codec.EmptyAddress] picks the key of a signer holding a single key.
+func NewFactory(ctx context.Context, client *Client, addr codec.Address) (*Factory, error) {
+	keys, err := client.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if addr == codec.EmptyAddress && len(keys) > 1 {
+		return nil, ErrAmbiguousKey
+	}
+	for _, key := range keys {
+		kaddr, err := codec.ParseAddressBech32(consts.HRP, key.Address)
+		if err != nil {
+			return nil, err
+		}
+		if addr != codec.EmptyAddress && kaddr != addr {
+			continue
+		}
+		// Don't trust the signer to pair the address with its key
+		if derived, err := keyAddress(kaddr[0], key.PublicKey); err != nil || derived != kaddr {
+			return nil, ErrKeyMismatch
+		}
+		return &Factory{client: client, addr: kaddr, public: key.PublicKey}, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// Address returns the address the factory signs for.
+func (f *Factory) Address() codec.Address {
+	return f.addr
+}
+
+// Sign asks the signer to sign [msg] and checks the signature before
+// returning it, so a faulty signer can't get a transaction rejected by the
+// chain.
+func (f *Factory) Sign(msg []byte, _ chain.Action) (chain.Auth, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	sig, err := f.client.Sign(ctx, f.addr, msg)
+	if err != nil {
+		return nil, err
+	}
+	var a chain.Auth
+	switch f.addr[0] {
+	case auth.ED25519Key:
+		if len(sig) != ed25519.SignatureLen {
+			return nil, ErrInvalidSignature
+		}
+		a = &auth.ED25519{Signer: ed25519.PublicKey(f.public), Signature: ed25519.Signature(sig)}
+	case auth.SECP256R1Key:
+		if len(sig) != secp256r1.SignatureLen {
+			return nil, ErrInvalidSignature
+		}
+		a = &auth.SECP256R1{Signer: secp256r1.PublicKey(f.public), Signature: secp256r1.Signature(sig)}
+	default:
+		return nil, auth.ErrUnknownKeyType
+	}
+	if err := a.AsyncVerify(msg); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return a, nil
+}
+
+func (f *Factory) MaxUnits() (uint64, uint64, []uint16) {
+	if f.addr[0] == auth.SECP256R1Key {
+		return auth.SECP256R1Size, auth.SECP256R1ComputeUnits, []uint16{storage.BalanceChunks}
+	}
+	return auth.ED25519Size, auth.ED25519ComputeUnits, []uint16{storage.BalanceChunks}
+}
+
+// keyAddress returns the address of the public key [public] of type [typ].
+func keyAddress(typ uint8, public []byte) (codec.Address, error) {
+	switch typ {
+	case auth.ED25519Key:
+		if len(public) != ed25519.PublicKeyLen {
+			return codec.EmptyAddress, auth.ErrInvalidKeyLength
+		}
+		return auth.NewED25519Address(ed25519.PublicKey(public)), nil
+	case auth.SECP256R1Key:
+		if len(public) != secp256r1.PublicKeyLen {
+			return codec.EmptyAddress, auth.ErrInvalidKeyLength
+		}
+		return auth.NewSECP256R1Address(secp256r1.PublicKey(public)), nil
+	default:
+		return codec.EmptyAddress, auth.ErrUnknownKeyType
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package signer signs transactions with keys held by another process, so
+// hosts that issue transactions (e.g. the gateway) never load private keys.
+//
+// The protocol is two JSON routes served by a [gateway.Gateway], which
+// authenticates callers and rate limits them:
+//
+//	GET  /v1/keys  -> [KeysReply]
+//	POST /v1/sign  [SignArgs] -> [SignReply]
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+
+	"dataverse/auth"
+	"dataverse/consts"
+	"dataverse/gateway"
+)
+
+const (
+	KeysPath = "/v1/keys"
+	SignPath = "/v1/sign"
+
+	// MaxMessageSize bounds the messages a signer signs, transaction digests
+	// are much smaller.
+	MaxMessageSize = 64 * 1024
+)
+
+var (
+	ErrUnknownKey       = errors.New("signer does not hold the key")
+	ErrKeyMismatch      = errors.New("signer key does not match the address")
+	ErrInvalidSignature = errors.New("signer returned an invalid signature")
+	ErrAmbiguousKey     = errors.New("signer holds several keys, an address must be chosen")
+)
+
+// Key is a key held by a signer.
+type Key struct {
+	Address   string `json:"address"`
+	PublicKey []byte `json:"public_key"`
+}
+
+type KeysReply struct {
+	Keys []Key `json:"keys"`
+}
+
+type SignArgs struct {
+	Address string `json:"address"`
+	Message []byte `json:"message"`
+}
+
+type SignReply struct {
+	Signature []byte `json:"signature"`
+}
+
+type signerKey struct {
+	public []byte
+	sign   func([]byte) ([]byte, error)
+}
+
+// Service holds the keys of a signer and serves the signer protocol.
+type Service struct {
+	mu   sync.RWMutex
+	keys map[codec.Address]signerKey
+}
+
+func NewService() *Service {
+	return &Service{keys: map[codec.Address]signerKey{}}
+}
+
+// AddKey makes the service sign for [addr] with [priv], the type of the key
+// is taken from the address.
+func (s *Service) AddKey(addr codec.Address, priv []byte) error {
+	var key signerKey
+	switch addr[0] {
+	case auth.ED25519Key:
+		if len(priv) != ed25519.PrivateKeyLen {
+			return auth.ErrInvalidKeyLength
+		}
+		pk := ed25519.PrivateKey(priv)
+		pub := pk.PublicKey()
+		if auth.NewED25519Address(pub) != addr {
+			return ErrKeyMismatch
+		}
+		key = signerKey{
+			public: pub[:],
+			sign: func(msg []byte) ([]byte, error) {
+				sig := ed25519.Sign(msg, pk)
+				return sig[:], nil
+			},
+		}
+	case auth.SECP256R1Key:
+		if len(priv) != secp256r1.PrivateKeyLen {
+			return auth.ErrInvalidKeyLength
+		}
+		pk := secp256r1.PrivateKey(priv)
+		pub := pk.PublicKey()
+		if auth.NewSECP256R1Address(pub) != addr {
+			return ErrKeyMismatch
+		}
+		key = signerKey{
+			public: pub[:],
+			sign: func(msg []byte) ([]byte, error) {
+				sig, err := secp256r1.Sign(msg, pk)
+				return sig[:], err
+			},
+		}
+	default:
+		return auth.ErrUnknownKeyType
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[addr] = key
+	return nil
+}
+
+// Register serves the signer protocol on [g].
+func (s *Service) Register(g *gateway.Gateway) {
+	g.HandleFunc(KeysPath, s.handleKeys)
+	g.HandleFunc(SignPath, s.handleSign)
+}
+
+func (s *Service) handleKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	reply := KeysReply{Keys: make([]Key, 0, len(s.keys))}
+	for addr, key := range s.keys {
+		reply.Keys = append(reply.Keys, Key{
+			Address:   codec.MustAddressBech32(consts.HRP, addr),
+			PublicKey: key.public,
+		})
+	}
+	s.mu.RUnlock()
+	writeJSON(w, reply)
+}
+
+func (s *Service) handleSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		gateway.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var args SignArgs
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*MaxMessageSize)).Decode(&args); err != nil {
+		gateway.Error(w, "invalid sign request", http.StatusBadRequest)
+		return
+	}
+	if len(args.Message) == 0 || len(args.Message) > MaxMessageSize {
+		gateway.Error(w, "invalid message size", http.StatusBadRequest)
+		return
+	}
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		gateway.Error(w, "invalid address", http.StatusBadRequest)
+		return
+	}
+	s.mu.RLock()
+	key, ok := s.keys[addr]
+	s.mu.RUnlock()
+	if !ok {
+		gateway.Error(w, ErrUnknownKey.Error(), http.StatusNotFound)
+		return
+	}
+	sig, err := key.sign(args.Message)
+	if err != nil {
+		gateway.Error(w, "cannot sign message", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, SignReply{Signature: sig})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	"dataverse/auth"
+	"dataverse/gateway"
+)
+
+const testAPIKey = "signer-key"
+
+// newMockSigner serves [service] behind a gateway accepting [testAPIKey].
+func newMockSigner(t *testing.T, service *Service) *httptest.Server {
+	digest := sha256.Sum256([]byte(testAPIKey))
+	cfg := gateway.DefaultConfig()
+	cfg.APIKeys = []gateway.APIKey{{Name: "gateway", SHA256: hex.EncodeToString(digest[:])}}
+	cfg.DefaultRoute.Auth = []string{gateway.AuthAPIKey}
+	g, err := gateway.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.Register(g)
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newKey(t *testing.T) (codec.Address, ed25519.PrivateKey) {
+	priv, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.NewED25519Address(priv.PublicKey()), priv
+}
+
+func TestRemoteFactory(t *testing.T) {
+	ctx := context.Background()
+	addr, priv := newKey(t)
+	service := NewService()
+	if err := service.AddKey(addr, priv[:]); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := newKey(t)
+	if err := service.AddKey(other, priv[:]); !errors.Is(err, ErrKeyMismatch) {
+		t.Fatalf("expected key mismatch, got %v", err)
+	}
+	server := newMockSigner(t, service)
+
+	if _, err := NewFactory(ctx, NewClient(server.URL, "wrong"), addr); err == nil {
+		t.Fatal("expected unauthenticated client to be rejected")
+	}
+	client := NewClient(server.URL, testAPIKey)
+	if _, err := NewFactory(ctx, client, other); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown key, got %v", err)
+	}
+	factory, err := NewFactory(ctx, client, codec.EmptyAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factory.Address() != addr {
+		t.Fatal("expected the only key of the signer")
+	}
+	msg := []byte("tx digest")
+	a, err := factory.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Actor() != addr {
+		t.Fatal("expected the signer to sign for the key")
+	}
+	if err := a.AsyncVerify(msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Sign(ctx, other, msg); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown key, got %v", err)
+	}
+}
+
+func TestFaultySigner(t *testing.T) {
+	addr, priv := newKey(t)
+	service := NewService()
+	if err := service.AddKey(addr, priv[:]); err != nil {
+		t.Fatal(err)
+	}
+	factory, err := NewFactory(context.Background(), NewClient(newMockSigner(t, service).URL, testAPIKey), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A signer answering with the signature of another message
+	_, otherPriv := newKey(t)
+	faulty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig := ed25519.Sign([]byte("other"), otherPriv)
+		writeJSON(w, SignReply{Signature: sig[:]})
+	}))
+	defer faulty.Close()
+	factory.client = NewClient(faulty.URL, testAPIKey)
+	if _, err := factory.Sign([]byte("tx digest"), nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
@@ -1,5 +1,11 @@
 package storage
 
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
 type ProjectData struct {
 	Key                string `json:"key"`
 	ProjectName        []byte `json:"name"`
@@ -9,32 +15,266 @@ type ProjectData struct {
 }
 
 type UpdateData struct {
-	Key                  string `json:"key"`
-	ProjectTxID          []byte `json:"project_id"` // reference to Project
-	UpdateExecutableHash []byte `json:"executable_hash"`
-	UpdateIPFSUrl        []byte `json:"executable_ipfs_url"`
-	ForDeviceName        []byte `json:"for_device_name"`
-	UpdateVersion        uint8  `json:"version"`
-	SuccessCount         uint8  `json:"success_count"`
+	Key                  string  `json:"key"`
+	ProjectTxID          ids.ID  `json:"project_id"` // reference to Project
+	UpdateExecutableHash []byte  `json:"executable_hash"`
+	UpdateIPFSUrl        []byte  `json:"executable_ipfs_url"`
+	ForDeviceName        []byte  `json:"for_device_name"`
+	UpdateVersion        Version `json:"version"`
+	UpdateSize           uint64  `json:"size"`
+	Signature            []byte  `json:"signature"`
+}
+
+// UpdateReportData is the outcome of an update as reported by the machine
+// that installed it.
+type UpdateReportData struct {
+	Success          bool    `json:"success"`
+	InstalledHash    []byte  `json:"installed_hash"`
+	InstalledVersion Version `json:"installed_version"`
+	Timestamp        int64   `json:"timestamp"`
 }
 
 type RegisterMachineCIDData struct {
 	Key        string `json:"key"`
 	MachineCID []byte `json:"machine_cid"`
+
+	// [MachineKey] is the public key of type [KeyType] that proved
+	// possession when the machine was registered, [MachineAddress] is its
+	// address. Machines registered before proofs were required have none.
+	KeyType        uint8         `json:"key_type"`
+	MachineKey     []byte        `json:"machine_key"`
+	MachineAddress codec.Address `json:"machine_address"`
 }
 
 type AttestMachineData struct {
-	Key                 string `json:"key"`
-	MachineAddress      []byte `json:"machine_address"`
-	MachineCategory     []byte `json:"machine_category"`
-	MachineManufacturer []byte `json:"machine_manufacturer"`
-	MachineCID          []byte `json:"machine_cid"`
+	Key                 string        `json:"key"`
+	MachineAddress      codec.Address `json:"machine_address"`
+	MachineCategory     []byte        `json:"machine_category"`
+	MachineManufacturer []byte        `json:"machine_manufacturer"`
+	MachineCID          []byte        `json:"machine_cid"`
+	Attester            codec.Address `json:"attester"`
+
+	// [Status] is set by the attester, [StatusChanged] is when it was last
+	// changed. [RevokedAt] is the time from which a revoked machine is no
+	// longer trusted, which may predate its revocation.
+	Status        MachineStatus `json:"status"`
+	StatusChanged int64         `json:"status_changed"`
+	RevokedAt     int64         `json:"revoked_at"`
+
+	// [Metadata] describes the machine, it is validated against the
+	// [MetadataSchemaData] of [MachineCategory] when it is attested.
+	Metadata Metadata `json:"metadata"`
+}
+
+// RevokedBy reports whether the machine was no longer trusted at
+// [timestamp], so data it notarized from then on shouldn't be relied on.
+func (m AttestMachineData) RevokedBy(timestamp int64) bool {
+	return m.Status == MachineRevoked && timestamp >= m.RevokedAt
+}
+
+type MachineStatus uint8
+
+const (
+	MachineActive MachineStatus = iota
+	MachineSuspended
+	MachineRevoked
+)
+
+func (s MachineStatus) String() string {
+	switch s {
+	case MachineActive:
+		return "active"
+	case MachineSuspended:
+		return "suspended"
+	case MachineRevoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
 }
 
 type NotarizeDataData struct {
-	Key             string `json:"key"`
-	AttestMachineTx []byte `json:"attest_machine_tx"`
-	DataOwnerAddr   []byte `json:"data_owner_address"`
-	DataCID         []byte `json:"data_cid"`
-	DataType        []byte `json:"data_type"`
+	Key             string        `json:"key"`
+	AttestMachineTx ids.ID        `json:"attest_machine_tx"`
+	DataOwnerAddr   codec.Address `json:"data_owner_address"`
+	DataCID         []byte        `json:"data_cid"`
+	DataType        []byte        `json:"data_type"`
+
+	// [Timestamp] is the time (in ms) of the block that notarized the data.
+	// It is zero for data notarized before it was recorded.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// NotarizeBatchData commits to [Count] data CIDs produced by a machine
+// between [Start] and [End] through the root of their Merkle tree (see the
+// merkle package).
+type NotarizeBatchData struct {
+	AttestMachineTx ids.ID        `json:"attest_machine_tx"`
+	DataOwnerAddr   codec.Address `json:"data_owner_address"`
+	Root            []byte        `json:"root"`
+	Count           uint32        `json:"count"`
+	Start           int64         `json:"start"`
+	End             int64         `json:"end"`
+	DataType        []byte        `json:"data_type"`
+
+	// [Timestamp] is the time (in ms) of the block that notarized the batch.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ManufacturerData is a manufacturer registered by [Owner]. Machines can only
+// be attested as made by [Name] by one of its [Attesters].
+type ManufacturerData struct {
+	Name      []byte          `json:"name"`
+	Owner     codec.Address   `json:"owner"`
+	Attesters []codec.Address `json:"attesters"`
+}
+
+// IsAttester reports whether [addr] may attest machines of the manufacturer.
+func (m ManufacturerData) IsAttester(addr codec.Address) bool {
+	for _, attester := range m.Attesters {
+		if attester == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// SponsorshipData lets [Sponsor] pay the fees of transactions signed by an
+// attested machine, up to [Cap] units of the native asset in total. [Spent]
+// is how much it has paid so far.
+type SponsorshipData struct {
+	Sponsor codec.Address `json:"sponsor"`
+	Cap     uint64        `json:"cap"`
+	Spent   uint64        `json:"spent"`
+}
+
+// Remaining is how much more the sponsor will pay for the machine.
+func (s SponsorshipData) Remaining() uint64 {
+	if s.Spent >= s.Cap {
+		return 0
+	}
+	return s.Cap - s.Spent
+}
+
+// ListingData offers access to a notarized dataset for [Price] units of
+// [Asset].
+type ListingData struct {
+	Notarization ids.ID        `json:"notarization"`
+	Seller       codec.Address `json:"seller"`
+	Asset        ids.ID        `json:"asset"`
+	Price        uint64        `json:"price"`
+	Closed       bool          `json:"closed"`
+}
+
+// PurchaseReceiptData proves that a buyer paid for a listing.
+type PurchaseReceiptData struct {
+	PurchaseTx ids.ID `json:"purchase_tx"`
+	Asset      ids.ID `json:"asset"`
+	Price      uint64 `json:"price"`
+	Timestamp  int64  `json:"timestamp"`
+
+	// [BuyerKey] is the ed25519 key the buyer asked the content key to be
+	// sealed to.
+	BuyerKey ed25519.PublicKey `json:"buyer_key"`
+}
+
+// KeyEnvelopeData is the content key of a listing sealed to a buyer.
+type KeyEnvelopeData struct {
+	DeliverTx ids.ID `json:"deliver_tx"`
+	Timestamp int64  `json:"timestamp"`
+	Envelope  []byte `json:"envelope"`
+}
+
+// LicenseKind is what a license grants access to.
+type LicenseKind uint8
+
+const (
+	// LicenseDataset grants access to a single notarized dataset.
+	LicenseDataset LicenseKind = iota
+	// LicenseMachine grants access to everything notarized by a machine.
+	LicenseMachine
+)
+
+func (k LicenseKind) String() string {
+	switch k {
+	case LicenseDataset:
+		return "dataset"
+	case LicenseMachine:
+		return "machine"
+	default:
+		return "unknown"
+	}
+}
+
+// LicenseOfferData sells access to [Subject] for [Period] milliseconds at a
+// time. [Subject] is a notarization for [LicenseDataset] and a machine
+// attestation for [LicenseMachine].
+type LicenseOfferData struct {
+	Kind      LicenseKind   `json:"kind"`
+	Subject   ids.ID        `json:"subject"`
+	Licensor  codec.Address `json:"licensor"`
+	Asset     ids.ID        `json:"asset"`
+	Price     uint64        `json:"price"`
+	Period    int64         `json:"period"`
+	TermsHash []byte        `json:"terms_hash"`
+	Closed    bool          `json:"closed"`
+}
+
+// LicenseData entitles a licensee to access a subject until [Expiry].
+type LicenseData struct {
+	Offer     ids.ID        `json:"offer"`
+	Kind      LicenseKind   `json:"kind"`
+	Licensor  codec.Address `json:"licensor"`
+	Expiry    int64         `json:"expiry"`
+	TermsHash []byte        `json:"terms_hash"`
+}
+
+// Active reports whether the license is still valid at [timestamp].
+func (l LicenseData) Active(timestamp int64) bool {
+	return timestamp < l.Expiry
+}
+
+type NotarizationRef struct {
+	TxID      ids.ID `json:"tx_id"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type ProjectChangeKind uint8
+
+const (
+	ProjectCreated ProjectChangeKind = iota
+	ProjectMaintainerAdded
+	ProjectMaintainerRemoved
+	ProjectOwnershipTransferred
+	ProjectMetadataUpdated
+	ProjectReleaseKeyChanged
+)
+
+func (k ProjectChangeKind) String() string {
+	switch k {
+	case ProjectCreated:
+		return "created"
+	case ProjectMaintainerAdded:
+		return "maintainer_added"
+	case ProjectMaintainerRemoved:
+		return "maintainer_removed"
+	case ProjectOwnershipTransferred:
+		return "ownership_transferred"
+	case ProjectMetadataUpdated:
+		return "metadata_updated"
+	case ProjectReleaseKeyChanged:
+		return "release_key_changed"
+	default:
+		return "unknown"
+	}
+}
+
+// ProjectChange is an entry of the change log of a project. [Subject] is the
+// owner, maintainer or new owner the change is about, if any.
+type ProjectChange struct {
+	TxID      ids.ID
+	Timestamp int64
+	Kind      ProjectChangeKind
+	Actor     codec.Address
+	Subject   codec.Address
 }
@@ -0,0 +1,693 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+
+	tconsts "dataverse/consts"
+)
+
+// Project, update, machine and notarization values are encoded as a version
+// byte followed by their fields packed with [codec.Packer], so they only take
+// as much state as their contents.
+//
+// Values written before versioning was introduced are fixed-size and
+// zero-padded. They are recognized by their length and can still be read.
+const valueVersion byte = 0x1
+
+const (
+	legacyProjectLen = int(ProjectNameChunks + ProjectDescriptionChunks + ProjectOwnerChunks + ProjectLogoChunks)
+	legacyUpdateLen  = ProjectTxIDChunks + UpdateExecutableHashChunks + UpdateExecutableIPFSUrlChunks +
+		ForDeviceNameChunks + UpdateVersionUnitsChunks + SuccessCountUnitsChunks
+	legacyMachineCIDLen    = MachineCIDChunks + 3
+	legacyAttestMachineLen = MachineCIDChunks + MachineAddressChunks + MachineCategoryChunks +
+		MachineManufacturerChunks
+	legacyNotarizeDataLen = AttestMachineTxChunks + DataOwnerAddrChunks + DataCIDChunks + DataTypeChunks
+)
+
+// newValueWriter returns a packer sized for a value of [size] bytes with the
+// version byte already written.
+func newValueWriter(size int) *codec.Packer {
+	p := codec.NewWriter(1+size, 1+size)
+	p.PackByte(valueVersion)
+	return p
+}
+
+// newValueReader returns a packer positioned after the version byte, or nil
+// if [v] was not written by [newValueWriter].
+func newValueReader(v []byte) *codec.Packer {
+	if len(v) == 0 || v[0] != valueVersion {
+		return nil
+	}
+	p := codec.NewReader(v, len(v))
+	p.UnpackByte()
+	return p
+}
+
+// doneReading returns the error of [p], if any, and whether the whole value
+// was consumed.
+func doneReading(p *codec.Packer) (bool, error) {
+	if err := p.Err(); err != nil {
+		return false, err
+	}
+	return p.Empty(), nil
+}
+
+// legacyField returns the zero-padded field at [start, end) of [v] without the
+// padding.
+func legacyField(v []byte, start, end int) []byte {
+	return bytes.Trim(v[start:end], "\x00")
+}
+
+// legacyAddress parses the bech32 address stored as a zero-padded string at
+// [start, end) of [v]. Legacy values were not checked to hold addresses of
+// this chain, those that don't are decoded as the empty address.
+func legacyAddress(v []byte, start, end int) codec.Address {
+	addr, err := codec.ParseAddressBech32(tconsts.HRP, string(legacyField(v, start, end)))
+	if err != nil {
+		return codec.EmptyAddress
+	}
+	return addr
+}
+
+// legacyTxID returns the transaction ID stored at [start, end) of [v]. The
+// CLI referenced it by its notarization key, other legacy values (like those
+// of the gateway) don't reference a transaction and are decoded as the empty
+// ID.
+func legacyTxID(v []byte, start, end int) ids.ID {
+	field := legacyField(v, start, end)
+	if len(field) != 1+consts.IDLen+consts.Uint16Len || field[0] != notarizeDataPrefix {
+		return ids.Empty
+	}
+	var id ids.ID
+	copy(id[:], field[1:1+consts.IDLen])
+	return id
+}
+
+func encodeProject(name, description, owner, logo []byte) []byte {
+	p := newValueWriter(codec.BytesLen(name) + codec.BytesLen(description) +
+		codec.BytesLen(owner) + codec.BytesLen(logo))
+	p.PackBytes(name)
+	p.PackBytes(description)
+	p.PackBytes(owner)
+	p.PackBytes(logo)
+	return p.Bytes()
+}
+
+func decodeProject(v []byte) (ProjectData, error) {
+	if p := newValueReader(v); p != nil {
+		var d ProjectData
+		p.UnpackBytes(int(ProjectNameChunks), false, &d.ProjectName)
+		p.UnpackBytes(int(ProjectDescriptionChunks), false, &d.ProjectDescription)
+		p.UnpackBytes(int(ProjectOwnerChunks), false, &d.ProjectOwner)
+		p.UnpackBytes(int(ProjectLogoChunks), false, &d.Logo)
+		done, err := doneReading(p)
+		if done || len(v) != legacyProjectLen {
+			if err == nil && !done {
+				err = ErrInvalidValue
+			}
+			return d, err
+		}
+	}
+	if len(v) != legacyProjectLen {
+		return ProjectData{}, ErrInvalidValue
+	}
+	o := 0
+	name := legacyField(v, o, o+int(ProjectNameChunks))
+	o += int(ProjectNameChunks)
+	description := legacyField(v, o, o+int(ProjectDescriptionChunks))
+	o += int(ProjectDescriptionChunks)
+	owner := legacyField(v, o, o+int(ProjectOwnerChunks))
+	o += int(ProjectOwnerChunks)
+	logo := legacyField(v, o, o+int(ProjectLogoChunks))
+	return ProjectData{
+		ProjectName:        name,
+		ProjectDescription: description,
+		ProjectOwner:       owner,
+		Logo:               logo,
+	}, nil
+}
+
+// decodeProjectID reads a project reference of an update. It is stored as the
+// raw ID, but updates created before project IDs were checked stored its
+// string form.
+func decodeProjectID(b []byte) ids.ID {
+	if len(b) == consts.IDLen {
+		var id ids.ID
+		copy(id[:], b)
+		return id
+	}
+	id, err := ids.FromString(string(b))
+	if err != nil {
+		return ids.Empty
+	}
+	return id
+}
+
+func encodeUpdate(projectID ids.ID, hash, url, device []byte, version Version, size uint64, signature []byte) []byte {
+	p := newValueWriter(codec.BytesLen(projectID[:]) + codec.BytesLen(hash) +
+		codec.BytesLen(url) + codec.BytesLen(device) + VersionLen + consts.Uint64Len + codec.BytesLen(signature))
+	p.PackBytes(projectID[:])
+	p.PackBytes(hash)
+	p.PackBytes(url)
+	p.PackBytes(device)
+	PackVersion(p, version)
+	p.PackUint64(size)
+	p.PackBytes(signature)
+	return p.Bytes()
+}
+
+func decodeUpdate(v []byte) (UpdateData, error) {
+	if p := newValueReader(v); p != nil {
+		var d UpdateData
+		var projectID []byte
+		p.UnpackBytes(ProjectTxIDChunks, false, &projectID)
+		d.ProjectTxID = decodeProjectID(projectID)
+		p.UnpackBytes(UpdateExecutableHashChunks, false, &d.UpdateExecutableHash)
+		p.UnpackBytes(UpdateExecutableIPFSUrlChunks, false, &d.UpdateIPFSUrl)
+		p.UnpackBytes(ForDeviceNameChunks, false, &d.ForDeviceName)
+		d.UpdateVersion = UnpackVersion(p)
+		d.UpdateSize = p.UnpackUint64(false)
+		p.UnpackBytes(ed25519.SignatureLen, false, &d.Signature)
+		done, err := doneReading(p)
+		if done || len(v) != legacyUpdateLen {
+			if err == nil && !done {
+				err = ErrInvalidValue
+			}
+			return d, err
+		}
+	}
+	if len(v) != legacyUpdateLen {
+		return UpdateData{}, ErrInvalidValue
+	}
+	o := 0
+	projectID := legacyField(v, o, o+ProjectTxIDChunks)
+	o += ProjectTxIDChunks
+	hash := legacyField(v, o, o+UpdateExecutableHashChunks)
+	o += UpdateExecutableHashChunks
+	url := legacyField(v, o, o+UpdateExecutableIPFSUrlChunks)
+	o += UpdateExecutableIPFSUrlChunks
+	device := legacyField(v, o, o+ForDeviceNameChunks)
+	o += ForDeviceNameChunks
+	return UpdateData{
+		ProjectTxID:          decodeProjectID(projectID),
+		UpdateExecutableHash: hash,
+		UpdateIPFSUrl:        url,
+		ForDeviceName:        device,
+		UpdateVersion:        Version{Major: uint32(v[o])},
+	}, nil
+}
+
+func encodeMachineCID(m RegisterMachineCIDData) []byte {
+	p := newValueWriter(codec.BytesLen(m.MachineCID) + consts.ByteLen +
+		codec.BytesLen(m.MachineKey) + codec.AddressLen)
+	p.PackBytes(m.MachineCID)
+	p.PackByte(m.KeyType)
+	p.PackBytes(m.MachineKey)
+	p.PackAddress(m.MachineAddress)
+	return p.Bytes()
+}
+
+func decodeMachineCID(v []byte) (RegisterMachineCIDData, error) {
+	if p := newValueReader(v); p != nil {
+		var m RegisterMachineCIDData
+		p.UnpackBytes(MachineCIDChunks, false, &m.MachineCID)
+		m.KeyType = p.UnpackByte()
+		p.UnpackBytes(secp256r1.PublicKeyLen, false, &m.MachineKey)
+		p.UnpackAddress(&m.MachineAddress)
+		done, err := doneReading(p)
+		if done || len(v) != legacyMachineCIDLen {
+			if err == nil && !done {
+				err = ErrInvalidValue
+			}
+			return m, err
+		}
+	}
+	if len(v) != legacyMachineCIDLen {
+		return RegisterMachineCIDData{}, ErrInvalidValue
+	}
+	return RegisterMachineCIDData{MachineCID: legacyField(v, 0, len(v))}, nil
+}
+
+func encodeMachineIndex(tx ids.ID) []byte {
+	p := newValueWriter(consts.IDLen)
+	p.PackID(tx)
+	return p.Bytes()
+}
+
+func decodeMachineIndex(v []byte) (ids.ID, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return ids.Empty, ErrInvalidValue
+	}
+	var tx ids.ID
+	p.UnpackID(true, &tx)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return tx, err
+}
+
+func encodeAttestMachine(d AttestMachineData) []byte {
+	p := newValueWriter(codec.AddressLen + codec.BytesLen(d.MachineCategory) +
+		codec.BytesLen(d.MachineManufacturer) + codec.BytesLen(d.MachineCID) + codec.AddressLen +
+		consts.ByteLen + consts.Int64Len*2 + MetadataSize(d.Metadata))
+	p.PackAddress(d.MachineAddress)
+	p.PackBytes(d.MachineCategory)
+	p.PackBytes(d.MachineManufacturer)
+	p.PackBytes(d.MachineCID)
+	p.PackAddress(d.Attester)
+	p.PackByte(byte(d.Status))
+	p.PackInt64(d.StatusChanged)
+	p.PackInt64(d.RevokedAt)
+	PackMetadata(p, d.Metadata)
+	return p.Bytes()
+}
+
+func decodeAttestMachine(v []byte) (AttestMachineData, error) {
+	if p := newValueReader(v); p != nil {
+		var d AttestMachineData
+		p.UnpackAddress(&d.MachineAddress)
+		p.UnpackBytes(MachineCategoryChunks, false, &d.MachineCategory)
+		p.UnpackBytes(MachineManufacturerChunks, false, &d.MachineManufacturer)
+		p.UnpackBytes(MachineCIDChunks, false, &d.MachineCID)
+		p.UnpackAddress(&d.Attester)
+		d.Status = MachineStatus(p.UnpackByte())
+		d.StatusChanged = p.UnpackInt64(false)
+		d.RevokedAt = p.UnpackInt64(false)
+		metadata, err := UnpackMetadata(p)
+		d.Metadata = metadata
+		done := false
+		if err == nil {
+			done, err = doneReading(p)
+		}
+		if done || len(v) != legacyAttestMachineLen {
+			if err == nil && !done {
+				err = ErrInvalidValue
+			}
+			return d, err
+		}
+	}
+	if len(v) != legacyAttestMachineLen {
+		return AttestMachineData{}, ErrInvalidValue
+	}
+	// The legacy layout has no attester, its fields are in the order of
+	// their chunk sizes: the address is in the slot of the CID, the category
+	// in that of the address and so on
+	var d AttestMachineData
+	o := 0
+	d.MachineAddress = legacyAddress(v, o, o+MachineCIDChunks)
+	o += MachineCIDChunks
+	d.MachineCategory = legacyField(v, o, o+MachineAddressChunks)
+	o += MachineAddressChunks
+	d.MachineManufacturer = legacyField(v, o, o+MachineCategoryChunks)
+	o += MachineCategoryChunks
+	d.MachineCID = legacyField(v, o, o+MachineManufacturerChunks)
+	return d, nil
+}
+
+func encodeNotarizeData(attestMachineTx ids.ID, dataOwnerAddr codec.Address, dataCID, dataType []byte, timestamp int64) []byte {
+	p := newValueWriter(consts.IDLen + codec.AddressLen + codec.BytesLen(dataCID) + codec.BytesLen(dataType) +
+		consts.Int64Len)
+	p.PackID(attestMachineTx)
+	p.PackAddress(dataOwnerAddr)
+	p.PackBytes(dataCID)
+	p.PackBytes(dataType)
+	p.PackInt64(timestamp)
+	return p.Bytes()
+}
+
+func decodeNotarizeData(v []byte) (NotarizeDataData, error) {
+	if p := newValueReader(v); p != nil {
+		var d NotarizeDataData
+		p.UnpackID(true, &d.AttestMachineTx)
+		p.UnpackAddress(&d.DataOwnerAddr)
+		p.UnpackBytes(DataCIDChunks, false, &d.DataCID)
+		p.UnpackBytes(DataTypeChunks, false, &d.DataType)
+		d.Timestamp = p.UnpackInt64(false)
+		done, err := doneReading(p)
+		if done || len(v) != legacyNotarizeDataLen {
+			if err == nil && !done {
+				err = ErrInvalidValue
+			}
+			return d, err
+		}
+	}
+	if len(v) != legacyNotarizeDataLen {
+		return NotarizeDataData{}, ErrInvalidValue
+	}
+	var d NotarizeDataData
+	o := 0
+	d.AttestMachineTx = legacyTxID(v, o, o+AttestMachineTxChunks)
+	o += AttestMachineTxChunks
+	d.DataOwnerAddr = legacyAddress(v, o, o+DataOwnerAddrChunks)
+	o += DataOwnerAddrChunks
+	d.DataCID = legacyField(v, o, o+DataCIDChunks)
+	o += DataCIDChunks
+	d.DataType = legacyField(v, o, o+DataTypeChunks)
+	return d, nil
+}
+
+func encodeUpdateReport(r UpdateReportData) []byte {
+	p := newValueWriter(consts.BoolLen + codec.BytesLen(r.InstalledHash) + VersionLen + consts.Int64Len)
+	p.PackBool(r.Success)
+	p.PackBytes(r.InstalledHash)
+	PackVersion(p, r.InstalledVersion)
+	p.PackInt64(r.Timestamp)
+	return p.Bytes()
+}
+
+func decodeUpdateReport(v []byte) (UpdateReportData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return UpdateReportData{}, ErrInvalidValue
+	}
+	var r UpdateReportData
+	r.Success = p.UnpackBool()
+	p.UnpackBytes(UpdateExecutableHashChunks, false, &r.InstalledHash)
+	r.InstalledVersion = UnpackVersion(p)
+	r.Timestamp = p.UnpackInt64(false)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return r, err
+}
+
+func encodeAddresses(addrs []codec.Address) []byte {
+	p := newValueWriter(consts.IntLen + len(addrs)*codec.AddressLen)
+	p.PackInt(len(addrs))
+	for _, addr := range addrs {
+		p.PackAddress(addr)
+	}
+	return p.Bytes()
+}
+
+func decodeAddresses(v []byte, limit int) ([]codec.Address, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return nil, ErrInvalidValue
+	}
+	count := p.UnpackInt(false)
+	if count > limit {
+		return nil, ErrInvalidValue
+	}
+	addrs := make([]codec.Address, count)
+	for i := range addrs {
+		p.UnpackAddress(&addrs[i])
+	}
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return addrs, err
+}
+
+func encodeListing(l ListingData) []byte {
+	p := newValueWriter(consts.IDLen + codec.AddressLen + consts.IDLen + consts.Uint64Len + consts.BoolLen)
+	p.PackID(l.Notarization)
+	p.PackAddress(l.Seller)
+	p.PackID(l.Asset)
+	p.PackUint64(l.Price)
+	p.PackBool(l.Closed)
+	return p.Bytes()
+}
+
+func decodeListing(v []byte) (ListingData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return ListingData{}, ErrInvalidValue
+	}
+	var l ListingData
+	p.UnpackID(false, &l.Notarization)
+	p.UnpackAddress(&l.Seller)
+	p.UnpackID(false, &l.Asset)
+	l.Price = p.UnpackUint64(false)
+	l.Closed = p.UnpackBool()
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return l, err
+}
+
+func encodePurchaseReceipt(r PurchaseReceiptData) []byte {
+	p := newValueWriter(consts.IDLen*2 + consts.Uint64Len + consts.Int64Len + ed25519.PublicKeyLen)
+	p.PackID(r.PurchaseTx)
+	p.PackID(r.Asset)
+	p.PackUint64(r.Price)
+	p.PackInt64(r.Timestamp)
+	p.PackFixedBytes(r.BuyerKey[:])
+	return p.Bytes()
+}
+
+func decodePurchaseReceipt(v []byte) (PurchaseReceiptData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return PurchaseReceiptData{}, ErrInvalidValue
+	}
+	var r PurchaseReceiptData
+	p.UnpackID(false, &r.PurchaseTx)
+	p.UnpackID(false, &r.Asset)
+	r.Price = p.UnpackUint64(false)
+	r.Timestamp = p.UnpackInt64(false)
+	key := r.BuyerKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &key)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return r, err
+}
+
+func encodeLicenseOffer(o LicenseOfferData) []byte {
+	p := newValueWriter(1 + consts.IDLen + codec.AddressLen + consts.IDLen + consts.Uint64Len + consts.Int64Len +
+		codec.BytesLen(o.TermsHash) + consts.BoolLen)
+	p.PackByte(byte(o.Kind))
+	p.PackID(o.Subject)
+	p.PackAddress(o.Licensor)
+	p.PackID(o.Asset)
+	p.PackUint64(o.Price)
+	p.PackInt64(o.Period)
+	p.PackBytes(o.TermsHash)
+	p.PackBool(o.Closed)
+	return p.Bytes()
+}
+
+func decodeLicenseOffer(v []byte) (LicenseOfferData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return LicenseOfferData{}, ErrInvalidValue
+	}
+	var o LicenseOfferData
+	o.Kind = LicenseKind(p.UnpackByte())
+	p.UnpackID(false, &o.Subject)
+	p.UnpackAddress(&o.Licensor)
+	p.UnpackID(false, &o.Asset)
+	o.Price = p.UnpackUint64(false)
+	o.Period = p.UnpackInt64(false)
+	p.UnpackBytes(sha256.Size, false, &o.TermsHash)
+	o.Closed = p.UnpackBool()
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return o, err
+}
+
+func encodeLicense(l LicenseData) []byte {
+	p := newValueWriter(consts.IDLen + 1 + codec.AddressLen + consts.Int64Len + codec.BytesLen(l.TermsHash))
+	p.PackID(l.Offer)
+	p.PackByte(byte(l.Kind))
+	p.PackAddress(l.Licensor)
+	p.PackInt64(l.Expiry)
+	p.PackBytes(l.TermsHash)
+	return p.Bytes()
+}
+
+func decodeLicense(v []byte) (LicenseData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return LicenseData{}, ErrInvalidValue
+	}
+	var l LicenseData
+	p.UnpackID(false, &l.Offer)
+	l.Kind = LicenseKind(p.UnpackByte())
+	p.UnpackAddress(&l.Licensor)
+	l.Expiry = p.UnpackInt64(false)
+	p.UnpackBytes(sha256.Size, false, &l.TermsHash)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return l, err
+}
+
+func encodeKeyEnvelope(e KeyEnvelopeData) []byte {
+	p := newValueWriter(consts.IDLen + consts.Int64Len + codec.BytesLen(e.Envelope))
+	p.PackID(e.DeliverTx)
+	p.PackInt64(e.Timestamp)
+	p.PackBytes(e.Envelope)
+	return p.Bytes()
+}
+
+func decodeKeyEnvelope(v []byte) (KeyEnvelopeData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return KeyEnvelopeData{}, ErrInvalidValue
+	}
+	var e KeyEnvelopeData
+	p.UnpackID(false, &e.DeliverTx)
+	e.Timestamp = p.UnpackInt64(false)
+	p.UnpackBytes(int(KeyEnvelopeChunks)*64, true, &e.Envelope)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return e, err
+}
+
+func encodeNotarizeBatch(b NotarizeBatchData) []byte {
+	p := newValueWriter(consts.IDLen + codec.AddressLen + codec.BytesLen(b.Root) + consts.Uint32Len +
+		consts.Int64Len*3 + codec.BytesLen(b.DataType))
+	p.PackID(b.AttestMachineTx)
+	p.PackAddress(b.DataOwnerAddr)
+	p.PackBytes(b.Root)
+	p.PackInt(int(b.Count))
+	p.PackInt64(b.Start)
+	p.PackInt64(b.End)
+	p.PackBytes(b.DataType)
+	p.PackInt64(b.Timestamp)
+	return p.Bytes()
+}
+
+func decodeNotarizeBatch(v []byte) (NotarizeBatchData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return NotarizeBatchData{}, ErrInvalidValue
+	}
+	var b NotarizeBatchData
+	p.UnpackID(true, &b.AttestMachineTx)
+	p.UnpackAddress(&b.DataOwnerAddr)
+	p.UnpackBytes(sha256.Size, true, &b.Root)
+	b.Count = uint32(p.UnpackInt(true))
+	b.Start = p.UnpackInt64(false)
+	b.End = p.UnpackInt64(false)
+	p.UnpackBytes(DataTypeChunks, false, &b.DataType)
+	b.Timestamp = p.UnpackInt64(false)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return b, err
+}
+
+func encodeSponsorship(s SponsorshipData) []byte {
+	p := newValueWriter(codec.AddressLen + consts.Uint64Len*2)
+	p.PackAddress(s.Sponsor)
+	p.PackUint64(s.Cap)
+	p.PackUint64(s.Spent)
+	return p.Bytes()
+}
+
+func decodeSponsorship(v []byte) (SponsorshipData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return SponsorshipData{}, ErrInvalidValue
+	}
+	var s SponsorshipData
+	p.UnpackAddress(&s.Sponsor)
+	s.Cap = p.UnpackUint64(false)
+	s.Spent = p.UnpackUint64(false)
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return s, err
+}
+
+func encodeManufacturer(m ManufacturerData) []byte {
+	p := newValueWriter(codec.BytesLen(m.Name) + codec.AddressLen + consts.IntLen + len(m.Attesters)*codec.AddressLen)
+	p.PackBytes(m.Name)
+	p.PackAddress(m.Owner)
+	p.PackInt(len(m.Attesters))
+	for _, attester := range m.Attesters {
+		p.PackAddress(attester)
+	}
+	return p.Bytes()
+}
+
+func decodeManufacturer(v []byte) (ManufacturerData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return ManufacturerData{}, ErrInvalidValue
+	}
+	var m ManufacturerData
+	p.UnpackBytes(MachineManufacturerChunks, true, &m.Name)
+	p.UnpackAddress(&m.Owner)
+	count := p.UnpackInt(false)
+	if count > MaxManufacturerAttesters {
+		return ManufacturerData{}, ErrInvalidValue
+	}
+	m.Attesters = make([]codec.Address, count)
+	for i := range m.Attesters {
+		p.UnpackAddress(&m.Attesters[i])
+	}
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return m, err
+}
+
+func encodeMetadataSchema(s MetadataSchemaData) []byte {
+	size := codec.BytesLen(s.Category) + codec.AddressLen + consts.IntLen
+	for _, f := range s.Fields {
+		size += codec.StringLen(f.Name) + consts.ByteLen + consts.BoolLen
+	}
+	p := newValueWriter(size)
+	p.PackBytes(s.Category)
+	p.PackAddress(s.Owner)
+	p.PackInt(len(s.Fields))
+	for _, f := range s.Fields {
+		p.PackString(f.Name)
+		p.PackByte(byte(f.Type))
+		p.PackBool(f.Required)
+	}
+	return p.Bytes()
+}
+
+func decodeMetadataSchema(v []byte) (MetadataSchemaData, error) {
+	p := newValueReader(v)
+	if p == nil {
+		return MetadataSchemaData{}, ErrInvalidValue
+	}
+	var s MetadataSchemaData
+	p.UnpackBytes(MachineCategoryChunks, true, &s.Category)
+	p.UnpackAddress(&s.Owner)
+	count := p.UnpackInt(false)
+	if count > MaxMetadataFields {
+		return MetadataSchemaData{}, ErrInvalidValue
+	}
+	s.Fields = make([]MetadataField, count)
+	for i := range s.Fields {
+		s.Fields[i].Name = p.UnpackString(true)
+		s.Fields[i].Type = MetadataType(p.UnpackByte())
+		s.Fields[i].Required = p.UnpackBool()
+	}
+	done, err := doneReading(p)
+	if err == nil && !done {
+		err = ErrInvalidValue
+	}
+	return s, err
+}
@@ -0,0 +1,440 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+
+	tconsts "dataverse/consts"
+)
+
+// legacyForeignAddress is an address of another chain, as attested and
+// notarized before addresses were checked.
+const legacyForeignAddress = "plmnt1qh05ghszrxfh8taksfqhvyfgewleq6u5ru9xlg"
+
+func TestProjectEncoding(t *testing.T) {
+	v := encodeProject([]byte("name"), []byte("description"), []byte("owner"), nil)
+	if len(v) >= legacyProjectLen {
+		t.Fatalf("encoded project is %d bytes, legacy layout is %d", len(v), legacyProjectLen)
+	}
+	p, err := decodeProject(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.ProjectName) != "name" || string(p.ProjectDescription) != "description" ||
+		string(p.ProjectOwner) != "owner" || len(p.Logo) != 0 {
+		t.Fatalf("unexpected project %+v", p)
+	}
+}
+
+func TestLegacyProjectDecoding(t *testing.T) {
+	v := make([]byte, legacyProjectLen)
+	copy(v, "name")
+	copy(v[ProjectNameChunks:], "description")
+	copy(v[ProjectNameChunks+ProjectDescriptionChunks:], "owner")
+	copy(v[ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks:], "logo")
+	p, err := decodeProject(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.ProjectName) != "name" || string(p.ProjectDescription) != "description" ||
+		string(p.ProjectOwner) != "owner" || string(p.Logo) != "logo" {
+		t.Fatalf("unexpected project %+v", p)
+	}
+}
+
+func TestUpdateEncoding(t *testing.T) {
+	project := ids.GenerateTestID()
+	version := Version{Major: 1, Minor: 4, Patch: 2}
+	signature := bytes.Repeat([]byte{0xab}, 64)
+	v := encodeUpdate(project, []byte("hash"), []byte("url"), []byte("device"), version, 1024, signature)
+	u, err := decodeUpdate(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ProjectTxID != project || string(u.UpdateExecutableHash) != "hash" ||
+		string(u.UpdateIPFSUrl) != "url" || string(u.ForDeviceName) != "device" ||
+		u.UpdateVersion != version || u.UpdateSize != 1024 ||
+		!bytes.Equal(u.Signature, signature) {
+		t.Fatalf("unexpected update %+v", u)
+	}
+
+	legacy := make([]byte, legacyUpdateLen)
+	copy(legacy, project.String())
+	legacy[legacyUpdateLen-2] = 3
+	legacy[legacyUpdateLen-1] = 7
+	u, err = decodeUpdate(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ProjectTxID != project || len(u.UpdateExecutableHash) != 0 ||
+		u.UpdateVersion != (Version{Major: 3}) {
+		t.Fatalf("unexpected legacy update %+v", u)
+	}
+}
+
+func TestMachineCIDEncoding(t *testing.T) {
+	m := RegisterMachineCIDData{
+		MachineCID:     bytes.Repeat([]byte{'c'}, 66),
+		KeyType:        1,
+		MachineKey:     bytes.Repeat([]byte{0x2}, 33),
+		MachineAddress: codec.CreateAddress(1, ids.GenerateTestID()),
+	}
+	d, err := decodeMachineCID(encodeMachineCID(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.MachineCID, m.MachineCID) || d.KeyType != m.KeyType ||
+		!bytes.Equal(d.MachineKey, m.MachineKey) || d.MachineAddress != m.MachineAddress {
+		t.Fatalf("unexpected registration %+v", d)
+	}
+
+}
+
+func TestAttestMachineEncoding(t *testing.T) {
+	machine := codec.CreateAddress(0, ids.GenerateTestID())
+	attester := codec.CreateAddress(0, ids.GenerateTestID())
+	v := encodeAttestMachine(AttestMachineData{
+		MachineAddress:      machine,
+		MachineCategory:     []byte("sensor"),
+		MachineManufacturer: []byte("acme"),
+		MachineCID:          []byte("cid"),
+		Attester:            attester,
+		Status:              MachineRevoked,
+		StatusChanged:       2000,
+		RevokedAt:           1000,
+	})
+	m, err := decodeAttestMachine(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MachineAddress != machine || m.Attester != attester || string(m.MachineCategory) != "sensor" ||
+		string(m.MachineManufacturer) != "acme" || string(m.MachineCID) != "cid" ||
+		m.Status != MachineRevoked || m.StatusChanged != 2000 || m.RevokedAt != 1000 {
+		t.Fatalf("unexpected attestation %+v", m)
+	}
+	if m.RevokedBy(999) || !m.RevokedBy(1000) {
+		t.Fatal("unexpected revocation time")
+	}
+
+	// Laid out as AttestMachine wrote them before values were versioned,
+	// with the address as a bech32 string
+	cid := bytes.Repeat([]byte{'c'}, 66)
+	legacy := make([]byte, legacyAttestMachineLen)
+	copy(legacy[:MachineCIDChunks], codec.MustAddressBech32(tconsts.HRP, machine))
+	copy(legacy[MachineCIDChunks:MachineCIDChunks+MachineAddressChunks], "sensor")
+	copy(legacy[MachineCIDChunks+MachineAddressChunks:MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks], "acme")
+	copy(legacy[MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks:], cid)
+	m, err = decodeAttestMachine(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MachineAddress != machine || m.Attester != codec.EmptyAddress || string(m.MachineCategory) != "sensor" ||
+		string(m.MachineManufacturer) != "acme" || !bytes.Equal(m.MachineCID, cid) || m.Status != MachineActive {
+		t.Fatalf("unexpected legacy attestation %+v", m)
+	}
+
+	// Addresses of other chains were accepted too
+	copy(legacy[:MachineCIDChunks], make([]byte, MachineCIDChunks))
+	copy(legacy, legacyForeignAddress)
+	m, err = decodeAttestMachine(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MachineAddress != codec.EmptyAddress || string(m.MachineCategory) != "sensor" || !bytes.Equal(m.MachineCID, cid) {
+		t.Fatalf("unexpected legacy attestation %+v", m)
+	}
+}
+
+func TestNotarizeDataEncoding(t *testing.T) {
+	tx := ids.GenerateTestID()
+	owner := codec.CreateAddress(0, ids.GenerateTestID())
+	v := encodeNotarizeData(tx, owner, []byte("cid"), []byte("type"), 1700000000000)
+	d, err := decodeNotarizeData(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AttestMachineTx != tx || d.DataOwnerAddr != owner ||
+		!bytes.Equal(d.DataCID, []byte("cid")) || !bytes.Equal(d.DataType, []byte("type")) ||
+		d.Timestamp != 1700000000000 {
+		t.Fatalf("unexpected notarization %+v", d)
+	}
+
+	// Laid out as NotarizeData wrote them before values were versioned. The
+	// CLI referenced the attestation by its notarization key, the gateway by
+	// an address.
+	cid := bytes.Repeat([]byte{'d'}, 59)
+	legacy := make([]byte, legacyNotarizeDataLen)
+	copy(legacy[:AttestMachineTxChunks], NotarizeDataKey(tx))
+	copy(legacy[AttestMachineTxChunks:AttestMachineTxChunks+DataOwnerAddrChunks], legacyForeignAddress)
+	copy(legacy[AttestMachineTxChunks+DataOwnerAddrChunks:AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks], cid)
+	copy(legacy[AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks:], "/dataverse.asset.MsgNotarizedAsset")
+	d, err = decodeNotarizeData(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AttestMachineTx != tx || d.DataOwnerAddr != codec.EmptyAddress || !bytes.Equal(d.DataCID, cid) ||
+		string(d.DataType) != "/dataverse.asset.MsgNotarizedAsset" || d.Timestamp != 0 {
+		t.Fatalf("unexpected legacy notarization %+v", d)
+	}
+
+	copy(legacy[:AttestMachineTxChunks], make([]byte, AttestMachineTxChunks))
+	copy(legacy, legacyForeignAddress)
+	d, err = decodeNotarizeData(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AttestMachineTx != ids.Empty || !bytes.Equal(d.DataCID, cid) {
+		t.Fatalf("unexpected legacy notarization %+v", d)
+	}
+}
+
+func TestNotarizeBatchEncoding(t *testing.T) {
+	b := NotarizeBatchData{
+		AttestMachineTx: ids.GenerateTestID(),
+		DataOwnerAddr:   codec.CreateAddress(0, ids.GenerateTestID()),
+		Root:            bytes.Repeat([]byte{0x2}, 32),
+		Count:           3600,
+		Start:           1700000000000,
+		End:             1700003599000,
+		DataType:        []byte("type"),
+		Timestamp:       1700003600000,
+	}
+	d, err := decodeNotarizeBatch(encodeNotarizeBatch(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AttestMachineTx != b.AttestMachineTx || d.DataOwnerAddr != b.DataOwnerAddr || !bytes.Equal(d.Root, b.Root) ||
+		d.Count != b.Count || d.Start != b.Start || d.End != b.End || !bytes.Equal(d.DataType, b.DataType) ||
+		d.Timestamp != b.Timestamp {
+		t.Fatalf("unexpected batch %+v", d)
+	}
+}
+
+func TestSponsorshipEncoding(t *testing.T) {
+	s := SponsorshipData{
+		Sponsor: codec.CreateAddress(0, ids.GenerateTestID()),
+		Cap:     1_000_000,
+		Spent:   1_500,
+	}
+	d, err := decodeSponsorship(encodeSponsorship(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != s {
+		t.Fatalf("unexpected sponsorship %+v", d)
+	}
+	if d.Remaining() != 998_500 {
+		t.Fatalf("unexpected remaining %d", d.Remaining())
+	}
+}
+
+func TestManufacturerEncoding(t *testing.T) {
+	m := ManufacturerData{
+		Name:      []byte("acme"),
+		Owner:     codec.CreateAddress(0, ids.GenerateTestID()),
+		Attesters: []codec.Address{codec.CreateAddress(0, ids.GenerateTestID()), codec.CreateAddress(1, ids.GenerateTestID())},
+	}
+	d, err := decodeManufacturer(encodeManufacturer(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d.Name) != "acme" || d.Owner != m.Owner || len(d.Attesters) != 2 ||
+		!d.IsAttester(m.Attesters[1]) || d.IsAttester(m.Owner) {
+		t.Fatalf("unexpected manufacturer %+v", d)
+	}
+}
+
+func TestInvalidValue(t *testing.T) {
+	if _, err := decodeProject([]byte{valueVersion, 0xff}); err == nil {
+		t.Fatal("expected error for truncated value")
+	}
+	if _, err := decodeNotarizeData(make([]byte, 10)); err == nil {
+		t.Fatal("expected error for unknown layout")
+	}
+}
+
+func TestUpdateReportEncoding(t *testing.T) {
+	r := UpdateReportData{Success: true, InstalledHash: []byte("hash"), InstalledVersion: Version{Major: 2, Patch: 1}, Timestamp: 1700000000000}
+	d, err := decodeUpdateReport(encodeUpdateReport(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Success != r.Success || !bytes.Equal(d.InstalledHash, r.InstalledHash) ||
+		d.InstalledVersion != r.InstalledVersion || d.Timestamp != r.Timestamp {
+		t.Fatalf("unexpected report %+v", d)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	v, err := ParseVersion("v1.10.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != (Version{Major: 1, Minor: 10, Patch: 2}) || v.String() != "1.10.2" {
+		t.Fatalf("unexpected version %v", v)
+	}
+	if v.Compare(Version{Major: 1, Minor: 9, Patch: 30}) != 1 || v.Compare(v) != 0 ||
+		v.Compare(Version{Major: 2}) != -1 {
+		t.Fatal("unexpected ordering")
+	}
+	for _, s := range []string{"1.2", "1.2.x", "1.2.3.4", ""} {
+		if _, err := ParseVersion(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestListingEncoding(t *testing.T) {
+	l := ListingData{
+		Notarization: ids.GenerateTestID(),
+		Seller:       codec.CreateAddress(0, ids.GenerateTestID()),
+		Asset:        ids.GenerateTestID(),
+		Price:        100,
+		Closed:       true,
+	}
+	d, err := decodeListing(encodeListing(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != l {
+		t.Fatalf("unexpected listing %+v", d)
+	}
+
+	r := PurchaseReceiptData{PurchaseTx: ids.GenerateTestID(), Asset: l.Asset, Price: l.Price, Timestamp: 1700000000000}
+	r.BuyerKey[0] = 0x1
+	dr, err := decodePurchaseReceipt(encodePurchaseReceipt(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dr != r {
+		t.Fatalf("unexpected receipt %+v", dr)
+	}
+
+	e := KeyEnvelopeData{DeliverTx: ids.GenerateTestID(), Timestamp: 1700000000000, Envelope: []byte("envelope")}
+	de, err := decodeKeyEnvelope(encodeKeyEnvelope(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if de.DeliverTx != e.DeliverTx || de.Timestamp != e.Timestamp || !bytes.Equal(de.Envelope, e.Envelope) {
+		t.Fatalf("unexpected envelope %+v", de)
+	}
+}
+
+func TestLicenseEncoding(t *testing.T) {
+	o := LicenseOfferData{
+		Kind:      LicenseMachine,
+		Subject:   ids.GenerateTestID(),
+		Licensor:  codec.CreateAddress(0, ids.GenerateTestID()),
+		Asset:     ids.GenerateTestID(),
+		Price:     10,
+		Period:    3_600_000,
+		TermsHash: bytes.Repeat([]byte{0x1}, 32),
+		Closed:    true,
+	}
+	do, err := decodeLicenseOffer(encodeLicenseOffer(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if do.Kind != o.Kind || do.Subject != o.Subject || do.Licensor != o.Licensor || do.Asset != o.Asset ||
+		do.Price != o.Price || do.Period != o.Period || !bytes.Equal(do.TermsHash, o.TermsHash) || do.Closed != o.Closed {
+		t.Fatalf("unexpected offer %+v", do)
+	}
+
+	l := LicenseData{Offer: ids.GenerateTestID(), Kind: o.Kind, Licensor: o.Licensor, Expiry: 1700000000000, TermsHash: o.TermsHash}
+	dl, err := decodeLicense(encodeLicense(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.Offer != l.Offer || dl.Kind != l.Kind || dl.Licensor != l.Licensor || dl.Expiry != l.Expiry ||
+		!bytes.Equal(dl.TermsHash, l.TermsHash) {
+		t.Fatalf("unexpected license %+v", dl)
+	}
+	if !dl.Active(l.Expiry-1) || dl.Active(l.Expiry) {
+		t.Fatal("license must expire at its expiry")
+	}
+}
+
+func TestMetadataEncoding(t *testing.T) {
+	schema := MetadataSchemaData{
+		Category: []byte("sensor"),
+		Owner:    codec.CreateAddress(0, ids.GenerateTestID()),
+		Fields: []MetadataField{
+			{Name: "model", Type: MetadataString, Required: true},
+			{Name: "firmware_version", Type: MetadataVersion, Required: true},
+			{Name: "location", Type: MetadataGeohash},
+			{Name: "sensors", Type: MetadataSensors, Required: true},
+			{Name: "calibration_date", Type: MetadataDate},
+		},
+	}
+	if err := ValidMetadataFields(schema.Fields); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := decodeMetadataSchema(encodeMetadataSchema(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ds.Category, schema.Category) || ds.Owner != schema.Owner || len(ds.Fields) != len(schema.Fields) ||
+		ds.Fields[1] != schema.Fields[1] || ds.Fields[4] != schema.Fields[4] {
+		t.Fatalf("unexpected schema %+v", ds)
+	}
+
+	m, err := schema.ParseMetadata([]byte(`{
+		"model": "X1",
+		"firmware_version": "1.2.3",
+		"location": "u4pruydqqvj",
+		"sensors": [{"type": "temperature", "unit": "celsius"}],
+		"calibration_date": "2023-10-01"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := decodeAttestMachine(encodeAttestMachine(AttestMachineData{
+		MachineAddress:  codec.CreateAddress(0, ids.GenerateTestID()),
+		MachineCategory: schema.Category,
+		Attester:        schema.Owner,
+		Metadata:        m,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(d.Metadata); err != nil {
+		t.Fatal(err)
+	}
+	values := d.Metadata.JSONValue()
+	if values["model"] != "X1" || values["firmware_version"] != "1.2.3" || values["location"] != "u4pruydqqvj" ||
+		values["calibration_date"] != "2023-10-01" || d.Metadata["sensors"].Sensors[0] != (Sensor{"temperature", "celsius"}) {
+		t.Fatalf("unexpected metadata %+v", values)
+	}
+
+	for _, invalid := range []string{
+		`{"firmware_version": "1.2.3", "sensors": [{"type": "co2", "unit": "ppm"}]}`,
+		`{"model": "X1", "firmware_version": 3, "sensors": [{"type": "co2", "unit": "ppm"}]}`,
+		`{"model": "X1", "firmware_version": "1.2.3", "sensors": [], "color": "red"}`,
+		`{"model": "X1", "firmware_version": "1.2.3", "sensors": [{"type": "co2", "unit": "ppm"}], "location": "ail"}`,
+	} {
+		if _, err := schema.ParseMetadata([]byte(invalid)); !errors.Is(err, ErrInvalidMetadata) {
+			t.Fatalf("expected invalid metadata for %s, got %v", invalid, err)
+		}
+	}
+	// Metadata has a single encoding, with its fields in order and unique
+	for _, names := range [][]string{{"model", "location"}, {"model", "model"}} {
+		p := codec.NewWriter(0, 1024)
+		p.PackInt(len(names))
+		for _, name := range names {
+			p.PackString(name)
+			p.PackByte(byte(MetadataString))
+			p.PackString("value")
+		}
+		r := codec.NewReader(p.Bytes(), len(p.Bytes()))
+		if _, err := UnpackMetadata(r); !errors.Is(err, ErrInvalidMetadata) {
+			t.Fatalf("expected invalid metadata for fields %v, got %v", names, err)
+		}
+	}
+}
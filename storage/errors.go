@@ -5,4 +5,9 @@ package storage
 
 import "errors"
 
-var ErrInvalidBalance = errors.New("invalid balance")
+var (
+	ErrInvalidBalance    = errors.New("invalid balance")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidIndexValue = errors.New("invalid index value")
+	ErrInvalidValue      = errors.New("invalid value")
+)
@@ -0,0 +1,425 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// Machine attestations carry typed [Metadata] (model, firmware version,
+// location, sensors...) instead of free text. Each machine category has a
+// [MetadataSchemaData] that lists the fields its machines describe and their
+// [MetadataType], and attestations are validated against it.
+
+const (
+	// MaxMetadataFields is how many fields a schema, and so an
+	// attestation, may have.
+	MaxMetadataFields = 16
+	// MaxMetadataFieldLen is the longest field name.
+	MaxMetadataFieldLen = 32
+	// MaxMetadataStringLen is the longest string value, sensor type or unit.
+	MaxMetadataStringLen = 64
+	// MaxGeohashLen is the longest geohash, 12 characters is under 4cm.
+	MaxGeohashLen = 12
+	// MaxMetadataSensors is how many sensors a sensors value may list.
+	MaxMetadataSensors = 16
+	// MaxMetadataSize bounds the packed size of the metadata of a machine.
+	MaxMetadataSize = 2048
+
+	// metadataDateLayout is how dates are written in JSON.
+	metadataDateLayout = "2006-01-02"
+	// geohashAlphabet is the base32 alphabet of geohashes.
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+var (
+	ErrInvalidMetadata       = errors.New("invalid metadata")
+	ErrInvalidMetadataSchema = errors.New("invalid metadata schema")
+)
+
+type MetadataType uint8
+
+const (
+	// MetadataString is free text, e.g. a model name.
+	MetadataString MetadataType = iota
+	// MetadataInt is a signed integer.
+	MetadataInt
+	// MetadataVersion is a semantic version, e.g. a firmware version.
+	MetadataVersion
+	// MetadataGeohash is a location encoded as a geohash.
+	MetadataGeohash
+	// MetadataDate is a day, e.g. a calibration date. It is stored as the
+	// unix time in milliseconds of its start (UTC).
+	MetadataDate
+	// MetadataSensors lists sensor types and the unit they measure in.
+	MetadataSensors
+
+	metadataTypes
+)
+
+var metadataTypeNames = [...]string{"string", "int", "version", "geohash", "date", "sensors"}
+
+func (t MetadataType) String() string {
+	if t >= metadataTypes {
+		return "unknown"
+	}
+	return metadataTypeNames[t]
+}
+
+// ParseMetadataType parses the name of a [MetadataType].
+func ParseMetadataType(s string) (MetadataType, error) {
+	for i, name := range metadataTypeNames {
+		if name == s {
+			return MetadataType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown type %q", ErrInvalidMetadataSchema, s)
+}
+
+func (t MetadataType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *MetadataType) UnmarshalText(b []byte) error {
+	parsed, err := ParseMetadataType(string(b))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Sensor is a sensor of a machine and the unit of its readings.
+type Sensor struct {
+	Type string `json:"type"`
+	Unit string `json:"unit"`
+}
+
+// MetadataValue is the value of a metadata field. Only the field of [Type] is
+// set: [String] for strings and geohashes, [Int] for integers and dates,
+// [Version] and [Sensors].
+type MetadataValue struct {
+	Type    MetadataType
+	String  string
+	Int     int64
+	Version Version
+	Sensors []Sensor
+}
+
+// JSONValue returns the plain value of [v]: a string, a number or a list of
+// sensors. Versions and dates are written as strings.
+func (v MetadataValue) JSONValue() any {
+	switch v.Type {
+	case MetadataInt:
+		return v.Int
+	case MetadataVersion:
+		return v.Version.String()
+	case MetadataDate:
+		return time.UnixMilli(v.Int).UTC().Format(metadataDateLayout)
+	case MetadataSensors:
+		return v.Sensors
+	default:
+		return v.String
+	}
+}
+
+func (v MetadataValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.JSONValue())
+}
+
+// ParseMetadataValue parses the JSON value [raw] of a field of type [t].
+func ParseMetadataValue(t MetadataType, raw json.RawMessage) (MetadataValue, error) {
+	v := MetadataValue{Type: t}
+	var err error
+	switch t {
+	case MetadataString, MetadataGeohash:
+		err = json.Unmarshal(raw, &v.String)
+	case MetadataInt:
+		err = json.Unmarshal(raw, &v.Int)
+	case MetadataVersion:
+		err = json.Unmarshal(raw, &v.Version)
+	case MetadataDate:
+		var s string
+		if err = json.Unmarshal(raw, &s); err != nil {
+			break
+		}
+		var d time.Time
+		d, err = time.Parse(metadataDateLayout, s)
+		if err != nil {
+			d, err = time.Parse(time.RFC3339, s)
+		}
+		v.Int = d.UnixMilli()
+	case MetadataSensors:
+		err = json.Unmarshal(raw, &v.Sensors)
+	default:
+		err = fmt.Errorf("unknown type %d", t)
+	}
+	if err != nil {
+		return MetadataValue{}, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return v, v.validate()
+}
+
+func (v MetadataValue) validate() error {
+	switch v.Type {
+	case MetadataString:
+		if len(v.String) == 0 || len(v.String) > MaxMetadataStringLen {
+			return fmt.Errorf("%w: string must have 1 to %d bytes", ErrInvalidMetadata, MaxMetadataStringLen)
+		}
+	case MetadataInt:
+	case MetadataVersion:
+		if v.Version.IsZero() {
+			return fmt.Errorf("%w: version can't be 0.0.0", ErrInvalidMetadata)
+		}
+	case MetadataGeohash:
+		if len(v.String) == 0 || len(v.String) > MaxGeohashLen {
+			return fmt.Errorf("%w: geohash must have 1 to %d characters", ErrInvalidMetadata, MaxGeohashLen)
+		}
+		for _, c := range v.String {
+			if !strings.ContainsRune(geohashAlphabet, c) {
+				return fmt.Errorf("%w: invalid geohash %q", ErrInvalidMetadata, v.String)
+			}
+		}
+	case MetadataDate:
+		if v.Int <= 0 {
+			return fmt.Errorf("%w: date must be after 1970-01-01", ErrInvalidMetadata)
+		}
+	case MetadataSensors:
+		if len(v.Sensors) == 0 || len(v.Sensors) > MaxMetadataSensors {
+			return fmt.Errorf("%w: must list 1 to %d sensors", ErrInvalidMetadata, MaxMetadataSensors)
+		}
+		for _, s := range v.Sensors {
+			if len(s.Type) == 0 || len(s.Type) > MaxMetadataStringLen ||
+				len(s.Unit) == 0 || len(s.Unit) > MaxMetadataStringLen {
+				return fmt.Errorf("%w: sensor type and unit must have 1 to %d bytes", ErrInvalidMetadata, MaxMetadataStringLen)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %d", ErrInvalidMetadata, v.Type)
+	}
+	return nil
+}
+
+// Metadata maps field names to their value.
+type Metadata map[string]MetadataValue
+
+// JSONValue returns [m] with the plain value of each field.
+func (m Metadata) JSONValue() map[string]any {
+	values := make(map[string]any, len(m))
+	for name, v := range m {
+		values[name] = v.JSONValue()
+	}
+	return values
+}
+
+// fields returns the field names of [m] in order, metadata is always packed
+// in that order so it has a single encoding.
+func (m Metadata) fields() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PackMetadata packs [m], nil metadata is packed as empty.
+func PackMetadata(p *codec.Packer, m Metadata) {
+	p.PackInt(len(m))
+	for _, name := range m.fields() {
+		v := m[name]
+		p.PackString(name)
+		p.PackByte(byte(v.Type))
+		switch v.Type {
+		case MetadataInt, MetadataDate:
+			p.PackInt64(v.Int)
+		case MetadataVersion:
+			PackVersion(p, v.Version)
+		case MetadataSensors:
+			p.PackInt(len(v.Sensors))
+			for _, s := range v.Sensors {
+				p.PackString(s.Type)
+				p.PackString(s.Unit)
+			}
+		default:
+			p.PackString(v.String)
+		}
+	}
+}
+
+// UnpackMetadata unpacks metadata packed by [PackMetadata]. Fields must be in
+// strictly increasing order of their names, as they are packed, so metadata
+// has a single encoding. Values are not validated, see
+// [MetadataSchemaData.Validate].
+func UnpackMetadata(p *codec.Packer) (Metadata, error) {
+	count := p.UnpackInt(false)
+	if count > MaxMetadataFields {
+		return nil, fmt.Errorf("%w: more than %d fields", ErrInvalidMetadata, MaxMetadataFields)
+	}
+	if count == 0 {
+		return nil, p.Err()
+	}
+	m := make(Metadata, count)
+	var last string
+	for i := 0; i < count && p.Err() == nil; i++ {
+		name := p.UnpackString(true)
+		if i > 0 && name <= last {
+			return nil, fmt.Errorf("%w: field %q is out of order", ErrInvalidMetadata, name)
+		}
+		last = name
+		v := MetadataValue{Type: MetadataType(p.UnpackByte())}
+		switch v.Type {
+		case MetadataInt, MetadataDate:
+			v.Int = p.UnpackInt64(false)
+		case MetadataVersion:
+			v.Version = UnpackVersion(p)
+		case MetadataSensors:
+			n := p.UnpackInt(true)
+			if n > MaxMetadataSensors {
+				return nil, fmt.Errorf("%w: more than %d sensors", ErrInvalidMetadata, MaxMetadataSensors)
+			}
+			v.Sensors = make([]Sensor, n)
+			for j := range v.Sensors {
+				v.Sensors[j].Type = p.UnpackString(true)
+				v.Sensors[j].Unit = p.UnpackString(true)
+			}
+		default:
+			v.String = p.UnpackString(true)
+		}
+		m[name] = v
+	}
+	return m, p.Err()
+}
+
+// MetadataSize is the packed size of [m].
+func MetadataSize(m Metadata) int {
+	size := consts.IntLen
+	for name, v := range m {
+		size += codec.StringLen(name) + consts.ByteLen
+		switch v.Type {
+		case MetadataInt, MetadataDate:
+			size += consts.Int64Len
+		case MetadataVersion:
+			size += VersionLen
+		case MetadataSensors:
+			size += consts.IntLen
+			for _, s := range v.Sensors {
+				size += codec.StringLen(s.Type) + codec.StringLen(s.Unit)
+			}
+		default:
+			size += codec.StringLen(v.String)
+		}
+	}
+	return size
+}
+
+// MetadataField is a field of a [MetadataSchemaData].
+type MetadataField struct {
+	Name     string       `json:"name"`
+	Type     MetadataType `json:"type"`
+	Required bool         `json:"required"`
+}
+
+// MetadataSchemaData is the schema of the metadata of machines of
+// [Category]. Only [Owner], who registered it, can change it.
+type MetadataSchemaData struct {
+	Category []byte          `json:"category"`
+	Owner    codec.Address   `json:"owner"`
+	Fields   []MetadataField `json:"fields"`
+}
+
+// ValidMetadataFields checks that [fields] can make up a schema: field names
+// are unique lowercase identifiers (e.g. firmware_version) and types are
+// known.
+func ValidMetadataFields(fields []MetadataField) error {
+	if len(fields) == 0 || len(fields) > MaxMetadataFields {
+		return fmt.Errorf("%w: must have 1 to %d fields", ErrInvalidMetadataSchema, MaxMetadataFields)
+	}
+	seen := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		if len(f.Name) == 0 || len(f.Name) > MaxMetadataFieldLen {
+			return fmt.Errorf("%w: field names must have 1 to %d characters", ErrInvalidMetadataSchema, MaxMetadataFieldLen)
+		}
+		for _, c := range f.Name {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+				return fmt.Errorf("%w: invalid field name %q", ErrInvalidMetadataSchema, f.Name)
+			}
+		}
+		if _, ok := seen[f.Name]; ok {
+			return fmt.Errorf("%w: duplicate field %q", ErrInvalidMetadataSchema, f.Name)
+		}
+		seen[f.Name] = struct{}{}
+		if f.Type >= metadataTypes {
+			return fmt.Errorf("%w: unknown type %d", ErrInvalidMetadataSchema, f.Type)
+		}
+	}
+	return nil
+}
+
+// Field returns the field [name] of the schema.
+func (s MetadataSchemaData) Field(name string) (MetadataField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return MetadataField{}, false
+}
+
+// Validate checks that [m] only has fields of the schema, of the right type
+// and with valid values, and that it has all the required ones.
+func (s MetadataSchemaData) Validate(m Metadata) error {
+	if MetadataSize(m) > MaxMetadataSize {
+		return fmt.Errorf("%w: larger than %d bytes", ErrInvalidMetadata, MaxMetadataSize)
+	}
+	for _, name := range m.fields() {
+		f, ok := s.Field(name)
+		if !ok {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidMetadata, name)
+		}
+		v := m[name]
+		if v.Type != f.Type {
+			return fmt.Errorf("%w: %q must be a %s", ErrInvalidMetadata, name, f.Type)
+		}
+		if err := v.validate(); err != nil {
+			return fmt.Errorf("%q: %w", name, err)
+		}
+	}
+	for _, f := range s.Fields {
+		if _, ok := m[f.Name]; f.Required && !ok {
+			return fmt.Errorf("%w: missing field %q", ErrInvalidMetadata, f.Name)
+		}
+	}
+	return nil
+}
+
+// ParseMetadata parses JSON metadata, an object of field names to values,
+// with the field types of the schema and validates it.
+func (s MetadataSchemaData) ParseMetadata(b []byte) (Metadata, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	m := make(Metadata, len(raw))
+	for name, value := range raw {
+		f, ok := s.Field(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidMetadata, name)
+		}
+		v, err := ParseMetadataValue(f.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", name, err)
+		}
+		m[name] = v
+	}
+	return m, s.Validate(m)
+}
@@ -5,6 +5,7 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -19,6 +20,7 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/state"
 )
 
@@ -27,6 +29,10 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // Metadata
 // 0x0/ (tx)
 //   -> [txID] => timestamp
+// 0x1/ (machine notarizations)
+//   -> [machine|height|index] => txID|timestamp
+// 0x2/ (project history)
+//   -> [project|height|index] => txID|timestamp|kind|actor|subject
 //
 // State
 // 0x0/ (balance)
@@ -45,23 +51,44 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 
 const (
 	// metaDB
-	txPrefix = 0x0
+	txPrefix                  = 0x0
+	machineNotarizationPrefix = 0x1
+	projectHistoryPrefix      = 0x2
 
 	// stateDB
-	balancePrefix            = 0x0
-	assetPrefix              = 0x1
-	orderPrefix              = 0x2
-	loanPrefix               = 0x3
-	heightPrefix             = 0x4
-	timestampPrefix          = 0x5
-	feePrefix                = 0x6
-	incomingWarpPrefix       = 0x7
-	outgoingWarpPrefix       = 0x8
-	projectPrefix            = 0x9
-	updatePrefix             = 0xA
-	registerMachineCIDPrefix = 0xB
-	attestMachineCIDPrefix   = 0xC
-	notarizeDataPrefix       = 0xD
+	balancePrefix             = 0x0
+	assetPrefix               = 0x1
+	orderPrefix               = 0x2
+	loanPrefix                = 0x3
+	heightPrefix              = 0x4
+	timestampPrefix           = 0x5
+	feePrefix                 = 0x6
+	incomingWarpPrefix        = 0x7
+	outgoingWarpPrefix        = 0x8
+	projectPrefix             = 0x9
+	updatePrefix              = 0xA
+	registerMachineCIDPrefix  = 0xB
+	attestMachineCIDPrefix    = 0xC
+	notarizeDataPrefix        = 0xD
+	dataCIDPrefix             = 0xE
+	updateReportPrefix        = 0xF
+	updateStatsPrefix         = 0x10
+	projectMaintainersPrefix  = 0x11
+	latestUpdatePrefix        = 0x12
+	projectReleaseKeyPrefix   = 0x13
+	listingPrefix             = 0x14
+	purchaseReceiptPrefix     = 0x15
+	licenseOfferPrefix        = 0x16
+	licensePrefix             = 0x17
+	keyEnvelopePrefix         = 0x18
+	notarizeBatchPrefix       = 0x19
+	sponsorshipPrefix         = 0x1A
+	machineCIDIndexPrefix     = 0x1B
+	machineKeyIndexPrefix     = 0x1C
+	manufacturerPrefix        = 0x1D
+	metadataSchemaPrefix      = 0x1E
+	machineAddressIndexPrefix = 0x1F
+	machineAttestationPrefix  = 0x20
 )
 
 const (
@@ -91,8 +118,50 @@ const (
 	DataTypeChunks        = 36
 	DataOwnerAddrChunks   = 45
 	AttestMachineTxChunks = 49
+
+	DataCIDIndexChunks uint16 = 16
+
+	UpdateReportChunks uint16 = 2
+	UpdateStatsChunks  uint16 = 1
+	LatestUpdateChunks uint16 = 1
+
+	ProjectMaintainersChunks uint16 = 9
+	ProjectReleaseKeyChunks  uint16 = 1
+
+	ListingChunks         uint16 = 2
+	PurchaseReceiptChunks uint16 = 2
+
+	LicenseOfferChunks uint16 = 3
+	LicenseChunks      uint16 = 2
+
+	KeyEnvelopeChunks uint16 = 3
+
+	NotarizeBatchChunks uint16 = 3
+
+	SponsorshipChunks uint16 = 2
+
+	MachineIndexChunks uint16 = 1
+
+	ManufacturerChunks uint16 = 12
+
+	MetadataSchemaChunks uint16 = 12
 )
 
+// MaxProjectMaintainers is how many addresses, besides the owner, may be
+// delegated to publish updates for a project.
+const MaxProjectMaintainers = 16
+
+// MaxManufacturerAttesters is how many accounts a manufacturer may authorize
+// to attest its machines.
+const MaxManufacturerAttesters = 16
+
+// MaxDataCIDNotarizations is how many notarizations of the same data are kept
+// in the [DataCIDKey] index. Only the earliest ones are indexed, they are the
+// ones that matter for provenance.
+const MaxDataCIDNotarizations = int(DataCIDIndexChunks) * 64 / dataCIDEntryLen
+
+const dataCIDEntryLen = consts.IDLen + consts.Uint64Len
+
 var (
 	failureByte  = byte(0x0)
 	successByte  = byte(0x1)
@@ -163,6 +232,172 @@ func GetTransaction(
 	return true, t, success, d, fee, nil
 }
 
+// [machineNotarizationPrefix] + [machine]
+func MachineNotarizationPrefix(machine codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen)
+	k[0] = machineNotarizationPrefix
+	copy(k[1:], machine[:])
+	return
+}
+
+// [machineNotarizationPrefix] + [machine] + [height] + [index]
+//
+// Keys sort in the order the notarizations were accepted.
+func MachineNotarizationKey(machine codec.Address, height uint64, index uint32) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint64Len+consts.Uint32Len)
+	copy(k, MachineNotarizationPrefix(machine))
+	binary.BigEndian.PutUint64(k[1+codec.AddressLen:], height)
+	binary.BigEndian.PutUint32(k[1+codec.AddressLen+consts.Uint64Len:], index)
+	return
+}
+
+func StoreMachineNotarization(
+	_ context.Context,
+	db database.KeyValueWriter,
+	machine codec.Address,
+	height uint64,
+	index uint32,
+	id ids.ID,
+	t int64,
+) error {
+	k := MachineNotarizationKey(machine, height, index)
+	v := make([]byte, consts.IDLen+consts.Uint64Len)
+	copy(v, id[:])
+	binary.BigEndian.PutUint64(v[consts.IDLen:], uint64(t))
+	return db.Put(k, v)
+}
+
+// cursorStart returns where to start iterating the index at [prefix] from
+// [cursor], the [height] + [index] suffix of the first key to read.
+func cursorStart(prefix []byte, cursor []byte) ([]byte, error) {
+	if len(cursor) == 0 {
+		return prefix, nil
+	}
+	if len(cursor) != consts.Uint64Len+consts.Uint32Len {
+		return nil, ErrInvalidCursor
+	}
+	return append(prefix[:len(prefix):len(prefix)], cursor...), nil
+}
+
+// nextCursor returns the cursor of the index key [k] at [prefix].
+func nextCursor(prefix []byte, k []byte) []byte {
+	next := make([]byte, len(k)-len(prefix))
+	copy(next, k[len(prefix):])
+	return next
+}
+
+// GetMachineNotarizations returns up to [limit] notarizations of [machine],
+// oldest first, starting at [cursor] (nil starts at the beginning). The
+// returned cursor is nil once there are no more notarizations to read.
+func GetMachineNotarizations(
+	_ context.Context,
+	db database.Iteratee,
+	machine codec.Address,
+	cursor []byte,
+	limit int,
+) ([]NotarizationRef, []byte, error) {
+	prefix := MachineNotarizationPrefix(machine)
+	start, err := cursorStart(prefix, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	iter := db.NewIteratorWithStartAndPrefix(start, prefix)
+	defer iter.Release()
+
+	notarizations := []NotarizationRef{}
+	for iter.Next() {
+		if len(notarizations) == limit {
+			return notarizations, nextCursor(prefix, iter.Key()), iter.Error()
+		}
+		v := iter.Value()
+		if len(v) != consts.IDLen+consts.Uint64Len {
+			return nil, nil, ErrInvalidIndexValue
+		}
+		var id ids.ID
+		copy(id[:], v[:consts.IDLen])
+		notarizations = append(notarizations, NotarizationRef{
+			TxID:      id,
+			Timestamp: int64(binary.BigEndian.Uint64(v[consts.IDLen:])),
+		})
+	}
+	return notarizations, nil, iter.Error()
+}
+
+// [projectHistoryPrefix] + [project]
+func ProjectHistoryPrefix(project ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen)
+	k[0] = projectHistoryPrefix
+	copy(k[1:], project[:])
+	return
+}
+
+// [projectHistoryPrefix] + [project] + [height] + [index]
+func ProjectHistoryKey(project ids.ID, height uint64, index uint32) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint64Len+consts.Uint32Len)
+	copy(k, ProjectHistoryPrefix(project))
+	binary.BigEndian.PutUint64(k[1+consts.IDLen:], height)
+	binary.BigEndian.PutUint32(k[1+consts.IDLen+consts.Uint64Len:], index)
+	return
+}
+
+const projectChangeLen = consts.IDLen + consts.Uint64Len + 1 + codec.AddressLen*2
+
+func StoreProjectChange(
+	_ context.Context,
+	db database.KeyValueWriter,
+	project ids.ID,
+	height uint64,
+	index uint32,
+	change ProjectChange,
+) error {
+	k := ProjectHistoryKey(project, height, index)
+	v := make([]byte, projectChangeLen)
+	copy(v, change.TxID[:])
+	binary.BigEndian.PutUint64(v[consts.IDLen:], uint64(change.Timestamp))
+	v[consts.IDLen+consts.Uint64Len] = byte(change.Kind)
+	copy(v[consts.IDLen+consts.Uint64Len+1:], change.Actor[:])
+	copy(v[consts.IDLen+consts.Uint64Len+1+codec.AddressLen:], change.Subject[:])
+	return db.Put(k, v)
+}
+
+// GetProjectHistory returns up to [limit] changes made to [project], oldest
+// first, starting at [cursor] (nil starts at the beginning). The returned
+// cursor is nil once there are no more changes to read.
+func GetProjectHistory(
+	_ context.Context,
+	db database.Iteratee,
+	project ids.ID,
+	cursor []byte,
+	limit int,
+) ([]ProjectChange, []byte, error) {
+	prefix := ProjectHistoryPrefix(project)
+	start, err := cursorStart(prefix, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	iter := db.NewIteratorWithStartAndPrefix(start, prefix)
+	defer iter.Release()
+
+	changes := []ProjectChange{}
+	for iter.Next() {
+		if len(changes) == limit {
+			return changes, nextCursor(prefix, iter.Key()), iter.Error()
+		}
+		v := iter.Value()
+		if len(v) != projectChangeLen {
+			return nil, nil, ErrInvalidIndexValue
+		}
+		var change ProjectChange
+		copy(change.TxID[:], v)
+		change.Timestamp = int64(binary.BigEndian.Uint64(v[consts.IDLen:]))
+		change.Kind = ProjectChangeKind(v[consts.IDLen+consts.Uint64Len])
+		copy(change.Actor[:], v[consts.IDLen+consts.Uint64Len+1:])
+		copy(change.Subject[:], v[consts.IDLen+consts.Uint64Len+1+codec.AddressLen:])
+		changes = append(changes, change)
+	}
+	return changes, nil, iter.Error()
+}
+
 // [accountPrefix] + [address] + [asset]
 func BalanceKey(addr codec.Address, asset ids.ID) (k []byte) {
 	k = balanceKeyPool.Get().([]byte)
@@ -614,6 +849,20 @@ func TimestampKey() (k []byte) {
 	return timestampKey
 }
 
+// GetTimestampFromState returns the timestamp of the last accepted block.
+//
+// Used to serve RPC queries
+func GetTimestampFromState(ctx context.Context, f ReadState) (int64, error) {
+	values, errs := f(ctx, [][]byte{chain.TimestampKey(TimestampKey())})
+	if errs[0] != nil {
+		return 0, errs[0]
+	}
+	if len(values[0]) != consts.Uint64Len {
+		return 0, ErrInvalidValue
+	}
+	return int64(binary.BigEndian.Uint64(values[0])), nil
+}
+
 func FeeKey() (k []byte) {
 	return feeKey
 }
@@ -653,19 +902,22 @@ func SetProject(
 ) error {
 
 	k := ProjectKey(project)
-
-	v := make([]byte, ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks+ProjectLogoChunks)
-
-	// saddr, _ := codec.AddressBech32(tconsts.HRP, owner)
-
-	copy(v[:ProjectNameChunks], project_name[:])
-	copy(v[ProjectNameChunks:ProjectNameChunks+ProjectDescriptionChunks], project_description[:])
-	copy(v[ProjectNameChunks+ProjectDescriptionChunks:ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks], project_owner[:])
-	copy(v[ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks:ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks+ProjectLogoChunks], logo[:])
+	v := encodeProject(project_name, project_description, project_owner, logo)
 	fmt.Println("Project Added to the Chain State")
 	return mu.Insert(ctx, k, v)
 }
 
+func GetProject(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+) (bool, ProjectData, error) {
+	k := ProjectKey(project)
+	v, err := im.GetValue(ctx, k)
+	return innerGetProject(k, v, err)
+}
+
+// Used to serve RPC queries
 func GetProjectFromState(
 	ctx context.Context,
 	f ReadState,
@@ -673,22 +925,129 @@ func GetProjectFromState(
 ) (bool, ProjectData, error) {
 
 	k := ProjectKey(project)
-	v, errs := f(ctx, [][]byte{k})
+	values, errs := f(ctx, [][]byte{k})
+	return innerGetProject(k, values[0], errs[0])
+}
 
-	if errors.Is(errs[0], database.ErrNotFound) {
+func innerGetProject(k []byte, v []byte, err error) (bool, ProjectData, error) {
+	if errors.Is(err, database.ErrNotFound) {
 		return false, ProjectData{}, nil
 	}
-	if errs[0] != nil {
-		return false, ProjectData{}, nil
+	if err != nil {
+		return false, ProjectData{}, err
+	}
+	project, err := decodeProject(v)
+	if err != nil {
+		return false, ProjectData{}, err
+	}
+	project.Key = hex.EncodeToString(k)
+	return true, project, nil
+}
+
+// ProjectOwnerAddress parses the bech32 owner stored with [project].
+func ProjectOwnerAddress(project ProjectData) (codec.Address, error) {
+	return codec.ParseAddressBech32(tconsts.HRP, string(project.ProjectOwner))
+}
+
+// [projectMaintainersPrefix] + [project]
+func ProjectMaintainersKey(project ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = projectMaintainersPrefix
+	copy(k[1:], project[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], ProjectMaintainersChunks)
+	return k
+}
+
+func SetProjectMaintainers(
+	ctx context.Context,
+	mu state.Mutable,
+	project ids.ID,
+	maintainers []codec.Address,
+) error {
+	k := ProjectMaintainersKey(project)
+	if len(maintainers) == 0 {
+		return mu.Remove(ctx, k)
+	}
+	return mu.Insert(ctx, k, encodeAddresses(maintainers))
+}
+
+func GetProjectMaintainers(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+) ([]codec.Address, error) {
+	return innerGetProjectMaintainers(im.GetValue(ctx, ProjectMaintainersKey(project)))
+}
+
+// Used to serve RPC queries
+func GetProjectMaintainersFromState(
+	ctx context.Context,
+	f ReadState,
+	project ids.ID,
+) ([]codec.Address, error) {
+	values, errs := f(ctx, [][]byte{ProjectMaintainersKey(project)})
+	return innerGetProjectMaintainers(values[0], errs[0])
+}
+
+func innerGetProjectMaintainers(v []byte, err error) ([]codec.Address, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
+	return decodeAddresses(v, MaxProjectMaintainers)
+}
+
+// [projectReleaseKeyPrefix] + [project]
+func ProjectReleaseKeyKey(project ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = projectReleaseKeyPrefix
+	copy(k[1:], project[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], ProjectReleaseKeyChunks)
+	return k
+}
+
+func SetProjectReleaseKey(
+	ctx context.Context,
+	mu state.Mutable,
+	project ids.ID,
+	key ed25519.PublicKey,
+) error {
+	return mu.Insert(ctx, ProjectReleaseKeyKey(project), key[:])
+}
+
+// GetProjectReleaseKey returns the key that must sign the updates of
+// [project], if one was registered.
+func GetProjectReleaseKey(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+) (bool, ed25519.PublicKey, error) {
+	return innerGetProjectReleaseKey(im.GetValue(ctx, ProjectReleaseKeyKey(project)))
+}
+
+// Used to serve RPC queries
+func GetProjectReleaseKeyFromState(
+	ctx context.Context,
+	f ReadState,
+	project ids.ID,
+) (bool, ed25519.PublicKey, error) {
+	values, errs := f(ctx, [][]byte{ProjectReleaseKeyKey(project)})
+	return innerGetProjectReleaseKey(values[0], errs[0])
+}
 
-	return true, ProjectData{
-		Key:                hex.EncodeToString(k),
-		ProjectName:        v[0][:ProjectNameChunks],
-		ProjectDescription: v[0][ProjectNameChunks : ProjectNameChunks+ProjectDescriptionChunks],
-		ProjectOwner:       v[0][ProjectNameChunks+ProjectDescriptionChunks : ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks],
-		Logo:               v[0][ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks : ProjectNameChunks+ProjectDescriptionChunks+ProjectOwnerChunks+ProjectLogoChunks],
-	}, errs[0]
+func innerGetProjectReleaseKey(v []byte, err error) (bool, ed25519.PublicKey, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ed25519.EmptyPublicKey, nil
+	}
+	if err != nil {
+		return false, ed25519.EmptyPublicKey, err
+	}
+	if len(v) != ed25519.PublicKeyLen {
+		return false, ed25519.EmptyPublicKey, ErrInvalidValue
+	}
+	return true, ed25519.PublicKey(v), nil
 }
 
 // [updatePrefix] + [address]
@@ -700,52 +1059,44 @@ func UpdateKey(update ids.ID) (k []byte) {
 	return k
 }
 
-// ProjectTxID          []byte `json:"project_id"` // reference to Project
+// ProjectTxID          ids.ID `json:"project_id"` // reference to Project
 //
 //	UpdateExecutableHash []byte `json:"executable_hash"`
 //	UpdateIPFSUrl       []byte `json:"executable_ipfs_url"`
 //	ForDeviceName        []byte `json:"for_device_name"`
-//	UpdateVersion        uint8  `json:"version"`
-//	SuccessCount         uint8  `json:"success_count"`
+//	UpdateVersion        Version `json:"version"`
+//	UpdateSize           uint64 `json:"size"`
+//	Signature            []byte `json:"signature"`
 func SetUpdate(
 	ctx context.Context,
 	mu state.Mutable,
 	update ids.ID,
-	project_id []byte,
+	project_id ids.ID,
 	executable_hash []byte,
 	executable_ipfs_url []byte,
 	for_device_name []byte,
-	version uint8,
-	success_count uint8,
+	version Version,
+	size uint64,
+	signature []byte,
 ) error {
 
 	k := UpdateKey(update)
-
-	v := make([]byte,
-		ProjectTxIDChunks+
-			UpdateExecutableHashChunks+
-			UpdateExecutableIPFSUrlChunks+
-			ForDeviceNameChunks+
-			UpdateVersionUnitsChunks+
-			SuccessCountUnitsChunks)
-
-	// saddr, _ := codec.AddressBech32(tconsts.HRP, owner)
-
-	copy(v[:ProjectTxIDChunks], project_id[:])
-
-	copy(v[ProjectTxIDChunks:ProjectTxIDChunks+UpdateExecutableHashChunks], executable_hash[:])
-
-	copy(v[ProjectTxIDChunks+UpdateExecutableHashChunks:ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks], executable_ipfs_url[:])
-
-	copy(v[ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks:ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks], for_device_name[:])
-
-	v[ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks] = version
-	v[ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks+UpdateVersionUnitsChunks] = success_count
-
+	v := encodeUpdate(project_id, executable_hash, executable_ipfs_url, for_device_name, version, size, signature)
 	fmt.Println("Update Added to the Chain State")
 	return mu.Insert(ctx, k, v)
 }
 
+func GetUpdate(
+	ctx context.Context,
+	im state.Immutable,
+	update ids.ID,
+) (bool, UpdateData, error) {
+	k := UpdateKey(update)
+	v, err := im.GetValue(ctx, k)
+	return innerGetUpdate(k, v, err)
+}
+
+// Used to serve RPC queries
 func GetUpdateFromState(
 	ctx context.Context,
 	f ReadState,
@@ -753,24 +1104,23 @@ func GetUpdateFromState(
 ) (bool, UpdateData, error) {
 
 	k := UpdateKey(update)
-	v, errs := f(ctx, [][]byte{k})
+	values, errs := f(ctx, [][]byte{k})
+	return innerGetUpdate(k, values[0], errs[0])
+}
 
-	if errors.Is(errs[0], database.ErrNotFound) {
+func innerGetUpdate(k []byte, v []byte, err error) (bool, UpdateData, error) {
+	if errors.Is(err, database.ErrNotFound) {
 		return false, UpdateData{}, nil
 	}
-	if errs[0] != nil {
-		return false, UpdateData{}, nil
+	if err != nil {
+		return false, UpdateData{}, err
 	}
-
-	return true, UpdateData{
-		Key:                  hex.EncodeToString(k),
-		ProjectTxID:          v[0][:ProjectTxIDChunks],
-		UpdateExecutableHash: v[0][ProjectTxIDChunks : ProjectTxIDChunks+UpdateExecutableHashChunks],
-		UpdateIPFSUrl:        v[0][ProjectTxIDChunks+UpdateExecutableHashChunks : ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks],
-		ForDeviceName:        v[0][ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks : ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks],
-		UpdateVersion:        v[0][ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks],
-		SuccessCount:         v[0][ProjectTxIDChunks+UpdateExecutableHashChunks+UpdateExecutableIPFSUrlChunks+ForDeviceNameChunks+UpdateVersionUnitsChunks],
-	}, errs[0]
+	update, err := decodeUpdate(v)
+	if err != nil {
+		return false, UpdateData{}, err
+	}
+	update.Key = hex.EncodeToString(k)
+	return true, update, nil
 }
 
 // [registerMachineCIDPrefix] + [address]
@@ -786,15 +1136,12 @@ func SetMachineCID(
 	ctx context.Context,
 	mu state.Mutable,
 	machineCIDID ids.ID,
-	machineCID []byte,
+	machine RegisterMachineCIDData,
 ) error {
 
 	k := RegisterMachineCIDKey(machineCIDID)
 
-	v := make([]byte, MachineCIDChunks+3)
-
-	copy(v[:], machineCID[:])
-	fmt.Println("Machine successfully registered")
+	v := encodeMachineCID(machine)
 	return mu.Insert(ctx, k, v)
 }
 
@@ -805,22 +1152,102 @@ func GetMachineCID(
 ) (bool, RegisterMachineCIDData, error) {
 
 	k := RegisterMachineCIDKey(machineCIDID)
-	v, errs := f(ctx, [][]byte{k})
+	values, errs := f(ctx, [][]byte{k})
 
 	if errors.Is(errs[0], database.ErrNotFound) {
 		return false, RegisterMachineCIDData{}, nil
 	}
 	if errs[0] != nil {
-		return false, RegisterMachineCIDData{}, nil
+		return false, RegisterMachineCIDData{}, errs[0]
+	}
+	machine, err := decodeMachineCID(values[0])
+	if err != nil {
+		return false, RegisterMachineCIDData{}, err
 	}
+	machine.Key = hex.EncodeToString(k)
+	return true, machine, nil
+}
+
+// The machine CID, key and address indexes map a CID, a machine key or its
+// address to the txID of the registration that claimed it, so none can be
+// registered twice. A CID and an address registered by the same
+// registration are bound to each other.
+
+// [machineCIDIndexPrefix] + [sha256(cid)]
+func MachineCIDIndexKey(machineCID []byte) (k []byte) {
+	return machineIndexKey(machineCIDIndexPrefix, machineCID)
+}
+
+// [machineKeyIndexPrefix] + [sha256(keyType | key)]
+func MachineKeyIndexKey(keyType uint8, key []byte) (k []byte) {
+	return machineIndexKey(machineKeyIndexPrefix, append([]byte{keyType}, key...))
+}
+
+// [machineAddressIndexPrefix] + [sha256(address)]
+func MachineAddressIndexKey(addr codec.Address) (k []byte) {
+	return machineIndexKey(machineAddressIndexPrefix, addr[:])
+}
+
+// [machineAttestationPrefix] + [sha256(address)] maps a machine address to
+// its latest attestation.
+func MachineAttestationKey(addr codec.Address) (k []byte) {
+	return machineIndexKey(machineAttestationPrefix, addr[:])
+}
+
+func machineIndexKey(prefix byte, v []byte) (k []byte) {
+	h := sha256.Sum256(v)
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = prefix
+	copy(k[1:], h[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], MachineIndexChunks)
+	return k
+}
+
+// SetMachineIndex records that the CID or key indexed by [k] was registered
+// in [tx].
+func SetMachineIndex(
+	ctx context.Context,
+	mu state.Mutable,
+	k []byte,
+	tx ids.ID,
+) error {
+	return mu.Insert(ctx, k, encodeMachineIndex(tx))
+}
+
+func GetMachineIndex(
+	ctx context.Context,
+	im state.Immutable,
+	k []byte,
+) (bool, ids.ID, error) {
+	v, err := im.GetValue(ctx, k)
+	return innerGetMachineIndex(v, err)
+}
+
+// Used to serve RPC queries
+func GetMachineIndexFromState(
+	ctx context.Context,
+	f ReadState,
+	k []byte,
+) (bool, ids.ID, error) {
+	values, errs := f(ctx, [][]byte{k})
+	return innerGetMachineIndex(values[0], errs[0])
+}
 
-	return true, RegisterMachineCIDData{
-		Key:        hex.EncodeToString(k),
-		MachineCID: v[0][:],
-	}, errs[0]
+func innerGetMachineIndex(v []byte, err error) (bool, ids.ID, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ids.Empty, nil
+	}
+	if err != nil {
+		return false, ids.Empty, err
+	}
+	tx, err := decodeMachineIndex(v)
+	if err != nil {
+		return false, ids.Empty, err
+	}
+	return true, tx, nil
 }
 
-// [attestMachineCIDPrefix] + [address]
+// [attestMachineCIDPrefix] + [txID]
 func AttestMachineKey(tx ids.ID) (k []byte) {
 	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
 	k[0] = attestMachineCIDPrefix
@@ -833,59 +1260,74 @@ func AttestMachine(
 	ctx context.Context,
 	mu state.Mutable,
 	tx ids.ID,
-	address []byte,
+	address codec.Address,
 	category []byte,
 	manufacturer []byte,
-	MachineCID []byte,
+	machineCID []byte,
+	attester codec.Address,
+	metadata Metadata,
 ) error {
 
-	k := AttestMachineKey(tx)
-
-	v := make([]byte,
-		MachineCIDChunks+
-			MachineAddressChunks+
-			MachineCategoryChunks+
-			MachineManufacturerChunks)
-
-	// saddr, _ := codec.AddressBech32(tconsts.HRP, owner)
-
-	copy(v[:MachineCIDChunks], address[:])
-
-	copy(v[MachineCIDChunks:MachineCIDChunks+MachineAddressChunks], category[:])
-
-	copy(v[MachineCIDChunks+MachineAddressChunks:MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks], manufacturer[:])
-
-	copy(v[MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks:], MachineCID[:])
+	return SetAttestMachine(ctx, mu, tx, AttestMachineData{
+		MachineAddress:      address,
+		MachineCategory:     category,
+		MachineManufacturer: manufacturer,
+		MachineCID:          machineCID,
+		Attester:            attester,
+		Status:              MachineActive,
+		Metadata:            metadata,
+	})
+}
 
-	return mu.Insert(ctx, k, v)
+// SetAttestMachine overwrites the attestation record [tx], it is used to
+// change the status of an attested machine.
+func SetAttestMachine(
+	ctx context.Context,
+	mu state.Mutable,
+	tx ids.ID,
+	machine AttestMachineData,
+) error {
+	return mu.Insert(ctx, AttestMachineKey(tx), encodeAttestMachine(machine))
 }
 
 func GetAttestMachine(
 	ctx context.Context,
-	f ReadState,
+	im state.Immutable,
 	tx ids.ID,
 ) (bool, AttestMachineData, error) {
+	k := AttestMachineKey(tx)
+	v, err := im.GetValue(ctx, k)
+	return innerGetAttestMachine(k, v, err)
+}
 
+// Used to serve RPC queries
+func GetAttestMachineFromState(
+	ctx context.Context,
+	f ReadState,
+	tx ids.ID,
+) (bool, AttestMachineData, error) {
 	k := AttestMachineKey(tx)
-	v, errs := f(ctx, [][]byte{k})
+	values, errs := f(ctx, [][]byte{k})
+	return innerGetAttestMachine(k, values[0], errs[0])
+}
 
-	if errors.Is(errs[0], database.ErrNotFound) {
+func innerGetAttestMachine(k []byte, v []byte, err error) (bool, AttestMachineData, error) {
+	if errors.Is(err, database.ErrNotFound) {
 		return false, AttestMachineData{}, nil
 	}
-	if errs[0] != nil {
-		return false, AttestMachineData{}, nil
+	if err != nil {
+		return false, AttestMachineData{}, err
 	}
 
-	return true, AttestMachineData{
-		Key:                 hex.EncodeToString(k),
-		MachineAddress:      v[0][:MachineCIDChunks],
-		MachineCategory:     v[0][MachineCIDChunks : MachineCIDChunks+MachineAddressChunks],
-		MachineManufacturer: v[0][MachineCIDChunks+MachineAddressChunks : MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks],
-		MachineCID:          v[0][MachineCIDChunks+MachineAddressChunks+MachineCategoryChunks:],
-	}, errs[0]
+	machine, err := decodeAttestMachine(v)
+	if err != nil {
+		return false, AttestMachineData{}, err
+	}
+	machine.Key = hex.EncodeToString(k)
+	return true, machine, nil
 }
 
-// [notarizeDataPrefix] + [address]
+// [notarizeDataPrefix] + [txID]
 func NotarizeDataKey(tx ids.ID) (k []byte) {
 	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
 	k[0] = notarizeDataPrefix
@@ -898,54 +1340,745 @@ func NotarizeData(
 	ctx context.Context,
 	mu state.Mutable,
 	tx ids.ID,
-	attestMachineTx []byte,
-	dataOwnerAddr []byte,
+	attestMachineTx ids.ID,
+	dataOwnerAddr codec.Address,
 	dataCID []byte,
 	dataType []byte,
+	timestamp int64,
 ) error {
 
 	k := NotarizeDataKey(tx)
+	v := encodeNotarizeData(attestMachineTx, dataOwnerAddr, dataCID, dataType, timestamp)
+	return mu.Insert(ctx, k, v)
+}
 
-	v := make([]byte,
-		AttestMachineTxChunks+
-			DataOwnerAddrChunks+
-			DataCIDChunks+
-			DataTypeChunks)
-
-	// saddr, _ := codec.AddressBech32(tconsts.HRP, owner)
+func GetNotarizeData(
+	ctx context.Context,
+	im state.Immutable,
+	tx ids.ID,
+) (bool, NotarizeDataData, error) {
+	k := NotarizeDataKey(tx)
+	v, err := im.GetValue(ctx, k)
+	return innerGetNotarizeData(k, v, err)
+}
 
-	copy(v[:AttestMachineTxChunks], attestMachineTx[:])
+// Used to serve RPC queries
+func GetNotarizeDataFromState(
+	ctx context.Context,
+	f ReadState,
+	tx ids.ID,
+) (bool, NotarizeDataData, error) {
+	k := NotarizeDataKey(tx)
+	values, errs := f(ctx, [][]byte{k})
+	return innerGetNotarizeData(k, values[0], errs[0])
+}
 
-	copy(v[AttestMachineTxChunks:AttestMachineTxChunks+DataOwnerAddrChunks], dataOwnerAddr[:])
+func innerGetNotarizeData(k []byte, v []byte, err error) (bool, NotarizeDataData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, NotarizeDataData{}, nil
+	}
+	if err != nil {
+		return false, NotarizeDataData{}, err
+	}
+	notarized, err := decodeNotarizeData(v)
+	if err != nil {
+		return false, NotarizeDataData{}, err
+	}
+	notarized.Key = hex.EncodeToString(k)
+	return true, notarized, nil
+}
 
-	copy(v[AttestMachineTxChunks+DataOwnerAddrChunks:AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks], dataCID[:])
+// [dataCIDPrefix] + [sha256(multihash)]
+//
+// Data CIDs are indexed by their multihash (see content.Multihash), so the
+// same data is found whatever version or base its CID was submitted in.
+func DataCIDKey(multihash []byte) (k []byte) {
+	h := sha256.Sum256(multihash)
+	k = make([]byte, 1+sha256.Size+consts.Uint16Len)
+	k[0] = dataCIDPrefix
+	copy(k[1:], h[:])
+	binary.BigEndian.PutUint16(k[1+sha256.Size:], DataCIDIndexChunks)
+	return k
+}
 
-	copy(v[AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks:], dataType[:])
+// AddDataCIDNotarization records that [tx] notarized the data of CID
+// multihash [multihash] at [t]. Once [MaxDataCIDNotarizations] have been
+// recorded, later ones are not indexed.
+func AddDataCIDNotarization(
+	ctx context.Context,
+	mu state.Mutable,
+	multihash []byte,
+	tx ids.ID,
+	t int64,
+) error {
+	k := DataCIDKey(multihash)
+	v, err := mu.GetValue(ctx, k)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return err
+	}
+	if len(v)/dataCIDEntryLen >= MaxDataCIDNotarizations {
+		return nil
+	}
+	nv := make([]byte, len(v)+dataCIDEntryLen)
+	copy(nv, v)
+	copy(nv[len(v):], tx[:])
+	binary.BigEndian.PutUint64(nv[len(v)+consts.IDLen:], uint64(t))
+	return mu.Insert(ctx, k, nv)
+}
 
-	return mu.Insert(ctx, k, v)
+// Used to serve RPC queries
+func GetDataCIDNotarizationsFromState(
+	ctx context.Context,
+	f ReadState,
+	multihash []byte,
+) ([]NotarizationRef, error) {
+	values, errs := f(ctx, [][]byte{DataCIDKey(multihash)})
+	if errors.Is(errs[0], database.ErrNotFound) {
+		return nil, nil
+	}
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	v := values[0]
+	if len(v)%dataCIDEntryLen != 0 {
+		return nil, ErrInvalidIndexValue
+	}
+	notarizations := make([]NotarizationRef, 0, len(v)/dataCIDEntryLen)
+	for i := 0; i < len(v); i += dataCIDEntryLen {
+		var id ids.ID
+		copy(id[:], v[i:i+consts.IDLen])
+		notarizations = append(notarizations, NotarizationRef{
+			TxID:      id,
+			Timestamp: int64(binary.BigEndian.Uint64(v[i+consts.IDLen:])),
+		})
+	}
+	return notarizations, nil
 }
 
-func GetNotarizeData(
+// [updateReportPrefix] + [updateTx] + [machineAttestTx]
+func UpdateReportKey(update ids.ID, machine ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen*2+consts.Uint16Len)
+	k[0] = updateReportPrefix
+	copy(k[1:], update[:])
+	copy(k[1+consts.IDLen:], machine[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen*2:], UpdateReportChunks)
+	return k
+}
+
+func SetUpdateReport(
+	ctx context.Context,
+	mu state.Mutable,
+	update ids.ID,
+	machine ids.ID,
+	report UpdateReportData,
+) error {
+	k := UpdateReportKey(update, machine)
+	return mu.Insert(ctx, k, encodeUpdateReport(report))
+}
+
+func GetUpdateReport(
+	ctx context.Context,
+	im state.Immutable,
+	update ids.ID,
+	machine ids.ID,
+) (bool, UpdateReportData, error) {
+	v, err := im.GetValue(ctx, UpdateReportKey(update, machine))
+	return innerGetUpdateReport(v, err)
+}
+
+// Used to serve RPC queries
+func GetUpdateReportFromState(
 	ctx context.Context,
 	f ReadState,
-	tx ids.ID,
-) (bool, NotarizeDataData, error) {
+	update ids.ID,
+	machine ids.ID,
+) (bool, UpdateReportData, error) {
+	values, errs := f(ctx, [][]byte{UpdateReportKey(update, machine)})
+	return innerGetUpdateReport(values[0], errs[0])
+}
 
-	k := NotarizeDataKey(tx)
-	v, errs := f(ctx, [][]byte{k})
+func innerGetUpdateReport(v []byte, err error) (bool, UpdateReportData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, UpdateReportData{}, nil
+	}
+	if err != nil {
+		return false, UpdateReportData{}, err
+	}
+	report, err := decodeUpdateReport(v)
+	if err != nil {
+		return false, UpdateReportData{}, err
+	}
+	return true, report, nil
+}
+
+// [updateStatsPrefix] + [updateTx]
+func UpdateStatsKey(update ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = updateStatsPrefix
+	copy(k[1:], update[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], UpdateStatsChunks)
+	return k
+}
+
+func SetUpdateStats(
+	ctx context.Context,
+	mu state.Mutable,
+	update ids.ID,
+	succeeded uint64,
+	failed uint64,
+) error {
+	v := make([]byte, consts.Uint64Len*2)
+	binary.BigEndian.PutUint64(v, succeeded)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], failed)
+	return mu.Insert(ctx, UpdateStatsKey(update), v)
+}
+
+// GetUpdateStats returns how many machines reported a successful and a
+// failed install of [update].
+func GetUpdateStats(
+	ctx context.Context,
+	im state.Immutable,
+	update ids.ID,
+) (uint64, uint64, error) {
+	return innerGetUpdateStats(im.GetValue(ctx, UpdateStatsKey(update)))
+}
+
+// Used to serve RPC queries
+func GetUpdateStatsFromState(
+	ctx context.Context,
+	f ReadState,
+	update ids.ID,
+) (uint64, uint64, error) {
+	values, errs := f(ctx, [][]byte{UpdateStatsKey(update)})
+	return innerGetUpdateStats(values[0], errs[0])
+}
+
+func innerGetUpdateStats(v []byte, err error) (uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(v) != consts.Uint64Len*2 {
+		return 0, 0, ErrInvalidValue
+	}
+	return binary.BigEndian.Uint64(v), binary.BigEndian.Uint64(v[consts.Uint64Len:]), nil
+}
+
+// [latestUpdatePrefix] + [project] + [sha256(device)]
+func LatestUpdateKey(project ids.ID, device []byte) (k []byte) {
+	deviceHash := sha256.Sum256(device)
+	k = make([]byte, 1+consts.IDLen*2+consts.Uint16Len)
+	k[0] = latestUpdatePrefix
+	copy(k[1:], project[:])
+	copy(k[1+consts.IDLen:], deviceHash[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen*2:], LatestUpdateChunks)
+	return k
+}
+
+// SetLatestUpdate points the (project, device) pair at its most recent
+// update.
+func SetLatestUpdate(
+	ctx context.Context,
+	mu state.Mutable,
+	project ids.ID,
+	device []byte,
+	update ids.ID,
+	version Version,
+) error {
+	p := codec.NewWriter(consts.IDLen+VersionLen, consts.IDLen+VersionLen)
+	p.PackID(update)
+	PackVersion(p, version)
+	return mu.Insert(ctx, LatestUpdateKey(project, device), p.Bytes())
+}
+
+// GetLatestUpdate returns the txID and version of the most recent update of
+// [project] for [device].
+func GetLatestUpdate(
+	ctx context.Context,
+	im state.Immutable,
+	project ids.ID,
+	device []byte,
+) (bool, ids.ID, Version, error) {
+	return innerGetLatestUpdate(im.GetValue(ctx, LatestUpdateKey(project, device)))
+}
+
+// Used to serve RPC queries
+func GetLatestUpdateFromState(
+	ctx context.Context,
+	f ReadState,
+	project ids.ID,
+	device []byte,
+) (bool, ids.ID, Version, error) {
+	values, errs := f(ctx, [][]byte{LatestUpdateKey(project, device)})
+	return innerGetLatestUpdate(values[0], errs[0])
+}
+
+func innerGetLatestUpdate(v []byte, err error) (bool, ids.ID, Version, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ids.Empty, Version{}, nil
+	}
+	if err != nil {
+		return false, ids.Empty, Version{}, err
+	}
+	if len(v) != consts.IDLen+VersionLen {
+		return false, ids.Empty, Version{}, ErrInvalidValue
+	}
+	var update ids.ID
+	p := codec.NewReader(v, len(v))
+	p.UnpackID(true, &update)
+	version := UnpackVersion(p)
+	if err := p.Err(); err != nil {
+		return false, ids.Empty, Version{}, err
+	}
+	return true, update, version, nil
+}
+
+// [listingPrefix] + [listTx]
+func ListingKey(listing ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = listingPrefix
+	copy(k[1:], listing[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], ListingChunks)
+	return k
+}
+
+func SetListing(
+	ctx context.Context,
+	mu state.Mutable,
+	listing ids.ID,
+	data ListingData,
+) error {
+	return mu.Insert(ctx, ListingKey(listing), encodeListing(data))
+}
+
+func GetListing(
+	ctx context.Context,
+	im state.Immutable,
+	listing ids.ID,
+) (bool, ListingData, error) {
+	v, err := im.GetValue(ctx, ListingKey(listing))
+	return innerGetListing(v, err)
+}
+
+// Used to serve RPC queries
+func GetListingFromState(
+	ctx context.Context,
+	f ReadState,
+	listing ids.ID,
+) (bool, ListingData, error) {
+	values, errs := f(ctx, [][]byte{ListingKey(listing)})
+	return innerGetListing(values[0], errs[0])
+}
+
+func innerGetListing(v []byte, err error) (bool, ListingData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ListingData{}, nil
+	}
+	if err != nil {
+		return false, ListingData{}, err
+	}
+	listing, err := decodeListing(v)
+	if err != nil {
+		return false, ListingData{}, err
+	}
+	return true, listing, nil
+}
+
+// [purchaseReceiptPrefix] + [listTx] + [buyer]
+func PurchaseReceiptKey(listing ids.ID, buyer codec.Address) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = purchaseReceiptPrefix
+	copy(k[1:], listing[:])
+	copy(k[1+consts.IDLen:], buyer[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen+codec.AddressLen:], PurchaseReceiptChunks)
+	return k
+}
+
+func SetPurchaseReceipt(
+	ctx context.Context,
+	mu state.Mutable,
+	listing ids.ID,
+	buyer codec.Address,
+	receipt PurchaseReceiptData,
+) error {
+	return mu.Insert(ctx, PurchaseReceiptKey(listing, buyer), encodePurchaseReceipt(receipt))
+}
+
+// GetPurchaseReceipt returns the receipt of [buyer] for [listing], if it
+// bought it.
+func GetPurchaseReceipt(
+	ctx context.Context,
+	im state.Immutable,
+	listing ids.ID,
+	buyer codec.Address,
+) (bool, PurchaseReceiptData, error) {
+	v, err := im.GetValue(ctx, PurchaseReceiptKey(listing, buyer))
+	return innerGetPurchaseReceipt(v, err)
+}
+
+// Used to serve RPC queries
+func GetPurchaseReceiptFromState(
+	ctx context.Context,
+	f ReadState,
+	listing ids.ID,
+	buyer codec.Address,
+) (bool, PurchaseReceiptData, error) {
+	values, errs := f(ctx, [][]byte{PurchaseReceiptKey(listing, buyer)})
+	return innerGetPurchaseReceipt(values[0], errs[0])
+}
+
+func innerGetPurchaseReceipt(v []byte, err error) (bool, PurchaseReceiptData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, PurchaseReceiptData{}, nil
+	}
+	if err != nil {
+		return false, PurchaseReceiptData{}, err
+	}
+	receipt, err := decodePurchaseReceipt(v)
+	if err != nil {
+		return false, PurchaseReceiptData{}, err
+	}
+	return true, receipt, nil
+}
+
+// [licenseOfferPrefix] + [offerTx]
+func LicenseOfferKey(offer ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = licenseOfferPrefix
+	copy(k[1:], offer[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], LicenseOfferChunks)
+	return k
+}
+
+func SetLicenseOffer(
+	ctx context.Context,
+	mu state.Mutable,
+	offer ids.ID,
+	data LicenseOfferData,
+) error {
+	return mu.Insert(ctx, LicenseOfferKey(offer), encodeLicenseOffer(data))
+}
+
+func GetLicenseOffer(
+	ctx context.Context,
+	im state.Immutable,
+	offer ids.ID,
+) (bool, LicenseOfferData, error) {
+	v, err := im.GetValue(ctx, LicenseOfferKey(offer))
+	return innerGetLicenseOffer(v, err)
+}
+
+// Used to serve RPC queries
+func GetLicenseOfferFromState(
+	ctx context.Context,
+	f ReadState,
+	offer ids.ID,
+) (bool, LicenseOfferData, error) {
+	values, errs := f(ctx, [][]byte{LicenseOfferKey(offer)})
+	return innerGetLicenseOffer(values[0], errs[0])
+}
+
+func innerGetLicenseOffer(v []byte, err error) (bool, LicenseOfferData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, LicenseOfferData{}, nil
+	}
+	if err != nil {
+		return false, LicenseOfferData{}, err
+	}
+	offer, err := decodeLicenseOffer(v)
+	if err != nil {
+		return false, LicenseOfferData{}, err
+	}
+	return true, offer, nil
+}
+
+// [licensePrefix] + [subject] + [licensee]
+func LicenseKey(subject ids.ID, licensee codec.Address) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = licensePrefix
+	copy(k[1:], subject[:])
+	copy(k[1+consts.IDLen:], licensee[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen+codec.AddressLen:], LicenseChunks)
+	return k
+}
+
+func SetLicense(
+	ctx context.Context,
+	mu state.Mutable,
+	subject ids.ID,
+	licensee codec.Address,
+	license LicenseData,
+) error {
+	return mu.Insert(ctx, LicenseKey(subject, licensee), encodeLicense(license))
+}
 
+// GetLicense returns the license [licensee] holds for [subject]. The license
+// may have expired.
+func GetLicense(
+	ctx context.Context,
+	im state.Immutable,
+	subject ids.ID,
+	licensee codec.Address,
+) (bool, LicenseData, error) {
+	v, err := im.GetValue(ctx, LicenseKey(subject, licensee))
+	return innerGetLicense(v, err)
+}
+
+// Used to serve RPC queries
+func GetLicenseFromState(
+	ctx context.Context,
+	f ReadState,
+	subject ids.ID,
+	licensee codec.Address,
+) (bool, LicenseData, error) {
+	values, errs := f(ctx, [][]byte{LicenseKey(subject, licensee)})
+	return innerGetLicense(values[0], errs[0])
+}
+
+func innerGetLicense(v []byte, err error) (bool, LicenseData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, LicenseData{}, nil
+	}
+	if err != nil {
+		return false, LicenseData{}, err
+	}
+	license, err := decodeLicense(v)
+	if err != nil {
+		return false, LicenseData{}, err
+	}
+	return true, license, nil
+}
+
+// [keyEnvelopePrefix] + [listTx] + [buyer]
+func KeyEnvelopeKey(listing ids.ID, buyer codec.Address) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = keyEnvelopePrefix
+	copy(k[1:], listing[:])
+	copy(k[1+consts.IDLen:], buyer[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen+codec.AddressLen:], KeyEnvelopeChunks)
+	return k
+}
+
+func SetKeyEnvelope(
+	ctx context.Context,
+	mu state.Mutable,
+	listing ids.ID,
+	buyer codec.Address,
+	envelope KeyEnvelopeData,
+) error {
+	return mu.Insert(ctx, KeyEnvelopeKey(listing, buyer), encodeKeyEnvelope(envelope))
+}
+
+// Used to serve RPC queries
+func GetKeyEnvelopeFromState(
+	ctx context.Context,
+	f ReadState,
+	listing ids.ID,
+	buyer codec.Address,
+) (bool, KeyEnvelopeData, error) {
+	values, errs := f(ctx, [][]byte{KeyEnvelopeKey(listing, buyer)})
 	if errors.Is(errs[0], database.ErrNotFound) {
-		return false, NotarizeDataData{}, nil
+		return false, KeyEnvelopeData{}, nil
 	}
 	if errs[0] != nil {
-		return false, NotarizeDataData{}, nil
+		return false, KeyEnvelopeData{}, errs[0]
+	}
+	envelope, err := decodeKeyEnvelope(values[0])
+	if err != nil {
+		return false, KeyEnvelopeData{}, err
 	}
+	return true, envelope, nil
+}
+
+// [notarizeBatchPrefix] + [txID]
+func NotarizeBatchKey(tx ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = notarizeBatchPrefix
+	copy(k[1:], tx[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], NotarizeBatchChunks)
+	return k
+}
 
-	return true, NotarizeDataData{
-		Key:             hex.EncodeToString(k),
-		AttestMachineTx: v[0][:AttestMachineTxChunks],
-		DataOwnerAddr:   v[0][AttestMachineTxChunks : AttestMachineTxChunks+DataOwnerAddrChunks],
-		DataCID:         v[0][AttestMachineTxChunks+DataOwnerAddrChunks : AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks],
-		DataType:        v[0][AttestMachineTxChunks+DataOwnerAddrChunks+DataCIDChunks:],
-	}, errs[0]
+func SetNotarizeBatch(
+	ctx context.Context,
+	mu state.Mutable,
+	tx ids.ID,
+	batch NotarizeBatchData,
+) error {
+	return mu.Insert(ctx, NotarizeBatchKey(tx), encodeNotarizeBatch(batch))
+}
+
+// Used to serve RPC queries
+func GetNotarizeBatchFromState(
+	ctx context.Context,
+	f ReadState,
+	tx ids.ID,
+) (bool, NotarizeBatchData, error) {
+	values, errs := f(ctx, [][]byte{NotarizeBatchKey(tx)})
+	if errors.Is(errs[0], database.ErrNotFound) {
+		return false, NotarizeBatchData{}, nil
+	}
+	if errs[0] != nil {
+		return false, NotarizeBatchData{}, errs[0]
+	}
+	batch, err := decodeNotarizeBatch(values[0])
+	if err != nil {
+		return false, NotarizeBatchData{}, err
+	}
+	return true, batch, nil
+}
+
+// [sponsorshipPrefix] + [attestTx]
+func SponsorshipKey(attestTx ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = sponsorshipPrefix
+	copy(k[1:], attestTx[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], SponsorshipChunks)
+	return k
+}
+
+func SetSponsorship(
+	ctx context.Context,
+	mu state.Mutable,
+	attestTx ids.ID,
+	sponsorship SponsorshipData,
+) error {
+	return mu.Insert(ctx, SponsorshipKey(attestTx), encodeSponsorship(sponsorship))
+}
+
+func GetSponsorship(
+	ctx context.Context,
+	im state.Immutable,
+	attestTx ids.ID,
+) (bool, SponsorshipData, error) {
+	v, err := im.GetValue(ctx, SponsorshipKey(attestTx))
+	return innerGetSponsorship(v, err)
+}
+
+// Used to serve RPC queries
+func GetSponsorshipFromState(
+	ctx context.Context,
+	f ReadState,
+	attestTx ids.ID,
+) (bool, SponsorshipData, error) {
+	values, errs := f(ctx, [][]byte{SponsorshipKey(attestTx)})
+	return innerGetSponsorship(values[0], errs[0])
+}
+
+func innerGetSponsorship(v []byte, err error) (bool, SponsorshipData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, SponsorshipData{}, nil
+	}
+	if err != nil {
+		return false, SponsorshipData{}, err
+	}
+	sponsorship, err := decodeSponsorship(v)
+	if err != nil {
+		return false, SponsorshipData{}, err
+	}
+	return true, sponsorship, nil
+}
+
+// [manufacturerPrefix] + [sha256(name)]
+func ManufacturerKey(name []byte) (k []byte) {
+	h := sha256.Sum256(name)
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = manufacturerPrefix
+	copy(k[1:], h[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], ManufacturerChunks)
+	return k
+}
+
+func SetManufacturer(
+	ctx context.Context,
+	mu state.Mutable,
+	manufacturer ManufacturerData,
+) error {
+	return mu.Insert(ctx, ManufacturerKey(manufacturer.Name), encodeManufacturer(manufacturer))
+}
+
+func GetManufacturer(
+	ctx context.Context,
+	im state.Immutable,
+	name []byte,
+) (bool, ManufacturerData, error) {
+	v, err := im.GetValue(ctx, ManufacturerKey(name))
+	return innerGetManufacturer(v, err)
+}
+
+// Used to serve RPC queries
+func GetManufacturerFromState(
+	ctx context.Context,
+	f ReadState,
+	name []byte,
+) (bool, ManufacturerData, error) {
+	values, errs := f(ctx, [][]byte{ManufacturerKey(name)})
+	return innerGetManufacturer(values[0], errs[0])
+}
+
+func innerGetManufacturer(v []byte, err error) (bool, ManufacturerData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ManufacturerData{}, nil
+	}
+	if err != nil {
+		return false, ManufacturerData{}, err
+	}
+	manufacturer, err := decodeManufacturer(v)
+	if err != nil {
+		return false, ManufacturerData{}, err
+	}
+	return true, manufacturer, nil
+}
+
+// [metadataSchemaPrefix] + [sha256(category)]
+func MetadataSchemaKey(category []byte) (k []byte) {
+	h := sha256.Sum256(category)
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = metadataSchemaPrefix
+	copy(k[1:], h[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], MetadataSchemaChunks)
+	return k
+}
+
+func SetMetadataSchema(
+	ctx context.Context,
+	mu state.Mutable,
+	schema MetadataSchemaData,
+) error {
+	return mu.Insert(ctx, MetadataSchemaKey(schema.Category), encodeMetadataSchema(schema))
+}
+
+func GetMetadataSchema(
+	ctx context.Context,
+	im state.Immutable,
+	category []byte,
+) (bool, MetadataSchemaData, error) {
+	v, err := im.GetValue(ctx, MetadataSchemaKey(category))
+	return innerGetMetadataSchema(v, err)
+}
+
+// Used to serve RPC queries
+func GetMetadataSchemaFromState(
+	ctx context.Context,
+	f ReadState,
+	category []byte,
+) (bool, MetadataSchemaData, error) {
+	values, errs := f(ctx, [][]byte{MetadataSchemaKey(category)})
+	return innerGetMetadataSchema(values[0], errs[0])
+}
+
+func innerGetMetadataSchema(v []byte, err error) (bool, MetadataSchemaData, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, MetadataSchemaData{}, nil
+	}
+	if err != nil {
+		return false, MetadataSchemaData{}, err
+	}
+	schema, err := decodeMetadataSchema(v)
+	if err != nil {
+		return false, MetadataSchemaData{}, err
+	}
+	return true, schema, nil
 }
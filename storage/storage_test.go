@@ -0,0 +1,65 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestProjectHistoryPages(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.New()
+	project := ids.GenerateTestID()
+	other := ids.GenerateTestID()
+	var txs []ids.ID
+	for height := uint64(1); height <= 3; height++ {
+		for index := uint32(0); index < 2; index++ {
+			tx := ids.GenerateTestID()
+			txs = append(txs, tx)
+			if err := StoreProjectChange(ctx, db, project, height, index, ProjectChange{TxID: tx}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := StoreProjectChange(ctx, db, other, 1, 0, ProjectChange{TxID: ids.GenerateTestID()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		read   []ids.ID
+		cursor []byte
+		pages  int
+	)
+	for {
+		changes, next, err := GetProjectHistory(ctx, db, project, cursor, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, change := range changes {
+			read = append(read, change.TxID)
+		}
+		pages++
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	if pages != 2 || len(read) != len(txs) {
+		t.Fatalf("read %d changes in %d pages", len(read), pages)
+	}
+	for i := range txs {
+		if read[i] != txs[i] {
+			t.Fatalf("change %d is %s, expected %s", i, read[i], txs[i])
+		}
+	}
+
+	if _, _, err := GetProjectHistory(ctx, db, project, []byte{1}, 4); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected %v, got %v", ErrInvalidCursor, err)
+	}
+}
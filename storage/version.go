@@ -0,0 +1,94 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// VersionLen is the packed size of a [Version].
+const VersionLen = consts.Uint32Len * 3
+
+var ErrInvalidVersion = errors.New("invalid version")
+
+// Version is the semantic version (major.minor.patch) of an update.
+type Version struct {
+	Major uint32
+	Minor uint32
+	Patch uint32
+}
+
+// ParseVersion parses a version of the form "major.minor.patch". A leading
+// "v" is accepted.
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	var n [3]uint32
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+		n[i] = uint32(v)
+	}
+	return Version{Major: n[0], Minor: n[1], Patch: n[2]}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero returns true for version 0.0.0, which is not a valid release.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Compare returns -1, 0 or 1 if [v] is lower than, equal to or greater than
+// [o].
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]uint32{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		switch {
+		case d[0] < d[1]:
+			return -1
+		case d[0] > d[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Version) UnmarshalText(b []byte) error {
+	parsed, err := ParseVersion(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+func PackVersion(p *codec.Packer, v Version) {
+	p.PackInt(int(v.Major))
+	p.PackInt(int(v.Minor))
+	p.PackInt(int(v.Patch))
+}
+
+func UnpackVersion(p *codec.Packer) Version {
+	return Version{
+		Major: uint32(p.UnpackInt(false)),
+		Minor: uint32(p.UnpackInt(false)),
+		Patch: uint32(p.UnpackInt(false)),
+	}
+}
@@ -5,6 +5,7 @@ package integration_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"flag"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,9 +47,11 @@ import (
 	"dataverse/actions"
 	"dataverse/auth"
 	tconsts "dataverse/consts"
+	"dataverse/content"
 	"dataverse/controller"
 	"dataverse/genesis"
 	trpc "dataverse/rpc"
+	"dataverse/storage"
 )
 
 var (
@@ -115,6 +119,11 @@ var (
 	asset3Decimals uint8
 	asset3ID       ids.ID
 
+	machineCategory     = []byte("sensor")
+	machineManufacturer = []byte("acme")
+	machinePriv         ed25519.PrivateKey
+	machineAttestTx     ids.ID
+
 	// when used with embedded VMs
 	genesisBytes []byte
 	instances    []instance
@@ -1948,8 +1957,236 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(result.Success).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("not warp asset"))
 	})
+
+	ginkgo.It("attest a machine at an address it was not registered with", func() {
+		ginkgo.By("register a manufacturer and a metadata schema", func() {
+			_, result := issueTx(&actions.CreateManufacturer{Name: machineManufacturer}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+			_, result = issueTx(&actions.AddManufacturerAttester{
+				Manufacturer: machineManufacturer,
+				Attester:     rsender,
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+			_, result = issueTx(&actions.SetMetadataSchema{
+				Category: machineCategory,
+				Fields:   []storage.MetadataField{{Name: "model", Type: storage.MetadataString}},
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+		})
+
+		machineCID := []byte(strings.Repeat("m", 66))
+		ginkgo.By("register the machine key", func() {
+			var err error
+			machinePriv, err = ed25519.GeneratePrivateKey()
+			gomega.Ω(err).Should(gomega.BeNil())
+			pub := machinePriv.PublicKey()
+			sig := ed25519.Sign(actions.RegisterMachineMessage(instances[0].chainID, machineCID), machinePriv)
+			_, result := issueTx(&actions.RegisterMachine{
+				MachineCID: machineCID,
+				KeyType:    auth.ED25519Key,
+				MachineKey: pub[:],
+				Signature:  sig[:],
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+		})
+
+		ginkgo.By("refuse an attestation at another address", func() {
+			_, result := issueTx(&actions.AttestMachine{
+				MachineAddress:      rsender2,
+				MachineCategory:     machineCategory,
+				MachineManufacturer: machineManufacturer,
+				MachineCID:          machineCID,
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeFalse())
+			gomega.Ω(string(result.Output)).
+				Should(gomega.ContainSubstring("not the one registered with the machine CID"))
+		})
+
+		ginkgo.By("attest the machine at the address of its key", func() {
+			var result *chain.Result
+			machineAttestTx, result = issueTx(&actions.AttestMachine{
+				MachineAddress:      auth.NewED25519Address(machinePriv.PublicKey()),
+				MachineCategory:     machineCategory,
+				MachineManufacturer: machineManufacturer,
+				MachineCID:          machineCID,
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.It("onboard a machine with a key it does not hold", func() {
+		held, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		claimed, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		machineCID := []byte(strings.Repeat("o", 66))
+		pub := claimed.PublicKey()
+		sig := ed25519.Sign(actions.RegisterMachineMessage(instances[0].chainID, machineCID), held)
+		_, result := issueTx(&actions.OnboardMachine{
+			MachineCategory:     machineCategory,
+			MachineManufacturer: machineManufacturer,
+			MachineCID:          machineCID,
+			KeyType:             auth.ED25519Key,
+			MachineKey:          pub[:],
+			Signature:           sig[:],
+			Value:               1_000,
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("does not prove possession"))
+
+		balance, err := instances[0].tcli.Balance(
+			context.Background(),
+			codec.MustAddressBech32(tconsts.HRP, auth.NewED25519Address(pub)),
+			ids.Empty,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
+	})
+
+	ginkgo.It("sponsor machine fees up to the cap", func() {
+		machineFactory := auth.NewSponsoredFactory(rsender, machineAttestTx, auth.NewED25519Factory(machinePriv))
+		dataCID, err := content.CID([]byte("reading"))
+		gomega.Ω(err).Should(gomega.BeNil())
+		notarize := &actions.NotarizeData{
+			MachineAttestTx: machineAttestTx,
+			DataCID:         []byte(dataCID),
+			DataType:        []byte("json"),
+		}
+
+		ginkgo.By("refuse a transaction costing more than the cap", func() {
+			_, result := issueTx(&actions.SetSponsorship{MachineAttestTx: machineAttestTx, Cap: 1}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+			parser, err := instances[0].tcli.Parser(context.Background())
+			gomega.Ω(err).Should(gomega.BeNil())
+			submit, _, _, err := instances[0].cli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				notarize,
+				machineFactory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(submit(context.Background())).
+				Should(gomega.MatchError(gomega.ContainSubstring(auth.ErrSponsorshipCapExceeded.Error())))
+		})
+
+		ginkgo.By("charge the fee to the sponsor within the cap", func() {
+			_, result := issueTx(&actions.SetSponsorship{MachineAttestTx: machineAttestTx, Cap: 1_000_000}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+			before, err := instances[0].tcli.Balance(context.Background(), sender, ids.Empty)
+			gomega.Ω(err).Should(gomega.BeNil())
+
+			_, result = issueTx(notarize, machineFactory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+			sponsorship, err := instances[0].tcli.Sponsorship(context.Background(), machineAttestTx)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(sponsorship.Spent).Should(gomega.Equal(result.Fee))
+			gomega.Ω(sponsorship.Remaining).Should(gomega.Equal(1_000_000 - result.Fee))
+			after, err := instances[0].tcli.Balance(context.Background(), sender, ids.Empty)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(after).Should(gomega.Equal(before - result.Fee))
+			balance, err := instances[0].tcli.Balance(
+				context.Background(),
+				codec.MustAddressBech32(tconsts.HRP, auth.NewED25519Address(machinePriv.PublicKey())),
+				ids.Empty,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(balance).Should(gomega.Equal(uint64(0)))
+		})
+	})
+
+	ginkgo.It("publish an update from a non-maintainer", func() {
+		project, result := issueTx(&actions.CreateProject{
+			ProjectName:        []byte("firmware"),
+			ProjectDescription: []byte("sensor firmware"),
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		digest := sha256.Sum256([]byte("firmware"))
+		_, result = issueTx(&actions.CreateUpdate{
+			ProjectTxID:          project,
+			UpdateExecutableHash: digest[:],
+			UpdateIPFSUrl:        []byte("ipfs://firmware"),
+			ForDeviceName:        machineCategory,
+			UpdateVersion:        storage.Version{Major: 1},
+			UpdateSize:           1,
+		}, factory2)
+		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(string(result.Output)).Should(gomega.Equal(string(actions.OutputNotProjectMaintainer)))
+	})
+	ginkgo.It("attest a revoked machine again", func() {
+		priv, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		pub := priv.PublicKey()
+		machineCID := []byte(strings.Repeat("r", 66))
+		sig := ed25519.Sign(actions.RegisterMachineMessage(instances[0].chainID, machineCID), priv)
+		_, result := issueTx(&actions.RegisterMachine{
+			MachineCID: machineCID,
+			KeyType:    auth.ED25519Key,
+			MachineKey: pub[:],
+			Signature:  sig[:],
+		}, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		attest := &actions.AttestMachine{
+			MachineAddress:      auth.NewED25519Address(pub),
+			MachineCategory:     machineCategory,
+			MachineManufacturer: machineManufacturer,
+			MachineCID:          machineCID,
+		}
+		attestTx, result := issueTx(attest, factory)
+		gomega.Ω(result.Success).Should(gomega.BeTrue())
+
+		ginkgo.By("refuse an attestation that doesn't reference the latest one", func() {
+			attest.PreviousAttestTx = ids.GenerateTestID()
+			_, result := issueTx(attest, factory)
+			gomega.Ω(result.Success).Should(gomega.BeFalse())
+			gomega.Ω(string(result.Output)).Should(gomega.Equal(string(actions.OutputPreviousAttestationMismatch)))
+		})
+
+		attest.PreviousAttestTx = attestTx
+		ginkgo.By("refuse an attestation by another attester", func() {
+			_, result := issueTx(&actions.AddManufacturerAttester{
+				Manufacturer: machineManufacturer,
+				Attester:     rsender2,
+			}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+			_, result = issueTx(attest, factory2)
+			gomega.Ω(result.Success).Should(gomega.BeFalse())
+			gomega.Ω(string(result.Output)).Should(gomega.Equal(string(actions.OutputMachineAttestedByOther)))
+		})
+
+		ginkgo.By("refuse an attestation of the revoked machine", func() {
+			_, result := issueTx(&actions.RevokeMachine{MachineAttestTx: attestTx}, factory)
+			gomega.Ω(result.Success).Should(gomega.BeTrue())
+			_, result = issueTx(attest, factory)
+			gomega.Ω(result.Success).Should(gomega.BeFalse())
+			gomega.Ω(string(result.Output)).Should(gomega.Equal(string(actions.OutputMachineRevoked)))
+		})
+	})
 })
 
+// issueTx issues [action] signed with [f] to the first instance and returns
+// its txID with the result of the block that accepts it.
+func issueTx(action chain.Action, f chain.AuthFactory) (ids.ID, *chain.Result) {
+	parser, err := instances[0].tcli.Parser(context.Background())
+	gomega.Ω(err).Should(gomega.BeNil())
+	submit, tx, _, err := instances[0].cli.GenerateTransaction(
+		context.Background(),
+		parser,
+		nil,
+		action,
+		f,
+	)
+	gomega.Ω(err).Should(gomega.BeNil())
+	gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+	accept := expectBlk(instances[0])
+	results := accept(false)
+	gomega.Ω(results).Should(gomega.HaveLen(1))
+	return tx.ID(), results[0]
+}
+
 func expectBlk(i instance) func(bool) []*chain.Result {
 	ctx := context.TODO()
 